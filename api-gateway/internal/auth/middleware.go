@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"ecommerce-microservices/api-gateway/internal/problem"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/rbac"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultRoles are given to tokens that carry no roles claim.
+var defaultRoles = []string{string(rbac.Customer)}
+
+// Middleware checks every request against the policy. Public routes pass
+// without a token; other routes need a valid bearer token whose roles the
+// policy allows. The caller is stored in the request context, so the gRPC
+// clients forward it to the backend services. A valid token sent to a public
+// route is forwarded as well, since some public methods return more to
+// privileged callers; an invalid one is ignored there.
+func Middleware(verifier *Verifier, policy rbac.Policy) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.Request.Method + " " + c.FullPath()
+		rule, ok := policy[route]
+		if !ok {
+			slog.ErrorContext(c.Request.Context(), "Route is missing from the access policy, denying", "route", route)
+			problem.Abort(c, problem.New(http.StatusForbidden, problem.CodePermissionDenied, "Access denied"))
+			return
+		}
+		token, found := bearerToken(c)
+		if rule.IsPublic() {
+			if found {
+				if claims, err := verifier.Verify(token); err == nil {
+					c.Request = c.Request.WithContext(identity.NewContext(c.Request.Context(), callerFromClaims(claims)))
+				}
+			}
+			c.Next()
+			return
+		}
+
+		if !found {
+			abortUnauthorized(c, "Missing bearer token")
+			return
+		}
+
+		claims, err := verifier.Verify(token)
+		if err != nil {
+			slog.InfoContext(c.Request.Context(), "Rejected token", "route", route, "error", err)
+			abortUnauthorized(c, "Invalid or expired token")
+			return
+		}
+
+		caller := callerFromClaims(claims)
+		if !rule.Allows(caller) {
+			slog.WarnContext(c.Request.Context(), "User is not allowed to call route", "user_id", caller.UserID, "roles", caller.Roles, "route", route)
+			problem.Abort(c, problem.New(http.StatusForbidden, problem.CodePermissionDenied, "Insufficient permissions"))
+			return
+		}
+
+		c.Request = c.Request.WithContext(identity.NewContext(c.Request.Context(), caller))
+		c.Next()
+	}
+}
+
+// bearerToken returns the token of the Authorization header, if the request
+// has one.
+func bearerToken(c *gin.Context) (string, bool) {
+	scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+	token = strings.TrimSpace(token)
+	if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
+	}
+	return token, true
+}
+
+func callerFromClaims(claims *Claims) identity.Identity {
+	caller := identity.Identity{UserID: claims.Subject, Roles: claims.Roles}
+	if len(caller.Roles) == 0 {
+		caller.Roles = defaultRoles
+	}
+	return caller
+}
+
+// UserID returns the ID of the authenticated caller.
+func UserID(c *gin.Context) string {
+	caller, _ := identity.FromContext(c.Request.Context())
+	return caller.UserID
+}
+
+func abortUnauthorized(c *gin.Context, message string) {
+	c.Header("WWW-Authenticate", `Bearer realm="api"`)
+	problem.Abort(c, problem.New(http.StatusUnauthorized, problem.CodeUnauthenticated, message))
+}
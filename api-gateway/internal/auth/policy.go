@@ -0,0 +1,36 @@
+package auth
+
+import "ecommerce-microservices/pkg/rbac"
+
+// orderRoles may use the order routes; whose orders they see is decided by
+// order-service.
+var orderRoles = rbac.AnyOf(rbac.Customer, rbac.OrderAdmin, rbac.Support)
+
+// RoutePolicy lists every route under /api/v1 with the roles allowed to call
+// it. Routes missing here are denied.
+var RoutePolicy = rbac.Policy{
+	"GET /api/v1/products":              rbac.Public,
+	"GET /api/v1/products/search":       rbac.Public,
+	"GET /api/v1/products/facets":       rbac.Public,
+	"GET /api/v1/products/:id":          rbac.Public,
+	"POST /api/v1/products":             rbac.AnyOf(rbac.CatalogAdmin),
+	"PUT /api/v1/products/:id":          rbac.AnyOf(rbac.CatalogAdmin),
+	"PATCH /api/v1/products/:id":        rbac.AnyOf(rbac.CatalogAdmin),
+	"DELETE /api/v1/products/:id":       rbac.AnyOf(rbac.CatalogAdmin),
+	"POST /api/v1/products/:id/restore": rbac.AnyOf(rbac.CatalogAdmin),
+
+	"GET /api/v1/categories":              rbac.Public,
+	"GET /api/v1/categories/tree":         rbac.Public,
+	"GET /api/v1/categories/:id":          rbac.Public,
+	"POST /api/v1/categories":             rbac.AnyOf(rbac.CatalogAdmin),
+	"PUT /api/v1/categories/:id":          rbac.AnyOf(rbac.CatalogAdmin),
+	"DELETE /api/v1/categories/:id":       rbac.AnyOf(rbac.CatalogAdmin),
+	"POST /api/v1/categories/:id/restore": rbac.AnyOf(rbac.CatalogAdmin),
+
+	"POST /api/v1/orders":           rbac.AnyOf(rbac.Customer),
+	"GET /api/v1/orders":            orderRoles,
+	"GET /api/v1/orders/:id":        orderRoles,
+	"GET /api/v1/orders/:id/events": orderRoles,
+	// Покупатель может только отменить свой заказ, это проверяет order-service
+	"PATCH /api/v1/orders/:id": rbac.AnyOf(rbac.Customer, rbac.OrderAdmin),
+}
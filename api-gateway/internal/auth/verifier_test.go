@@ -0,0 +1,262 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testSecret = "test-secret"
+
+func signHS256(t *testing.T, secret string, claims jwt.Claims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("failed to sign token: %v", err)
+	}
+	return token
+}
+
+func validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "user-1",
+			Issuer:    "shop",
+			Audience:  jwt.ClaimStrings{"api"},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Hour)),
+		},
+		Roles: []string{"customer"},
+	}
+}
+
+func TestVerifierHS256(t *testing.T) {
+	v, err := NewVerifier(Config{HMACSecret: testSecret, Issuer: "shop", Audience: "api"})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		token   func(t *testing.T) string
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			token: func(t *testing.T) string { return signHS256(t, testSecret, validClaims()) },
+		},
+		{
+			name: "expired within clock skew",
+			token: func(t *testing.T) string {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-clockSkew / 2))
+				return signHS256(t, testSecret, c)
+			},
+		},
+		{
+			name: "expired",
+			token: func(t *testing.T) string {
+				c := validClaims()
+				c.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Hour))
+				return signHS256(t, testSecret, c)
+			},
+			wantErr: true,
+		},
+		{
+			name: "no expiry",
+			token: func(t *testing.T) string {
+				c := validClaims()
+				c.ExpiresAt = nil
+				return signHS256(t, testSecret, c)
+			},
+			wantErr: true,
+		},
+		{
+			name: "no subject",
+			token: func(t *testing.T) string {
+				c := validClaims()
+				c.Subject = ""
+				return signHS256(t, testSecret, c)
+			},
+			wantErr: true,
+		},
+		{
+			name: "other issuer",
+			token: func(t *testing.T) string {
+				c := validClaims()
+				c.Issuer = "evil"
+				return signHS256(t, testSecret, c)
+			},
+			wantErr: true,
+		},
+		{
+			name: "other audience",
+			token: func(t *testing.T) string {
+				c := validClaims()
+				c.Audience = jwt.ClaimStrings{"admin"}
+				return signHS256(t, testSecret, c)
+			},
+			wantErr: true,
+		},
+		{
+			name:    "wrong secret",
+			token:   func(t *testing.T) string { return signHS256(t, "other-secret", validClaims()) },
+			wantErr: true,
+		},
+		{
+			name: "alg none",
+			token: func(t *testing.T) string {
+				token, err := jwt.NewWithClaims(jwt.SigningMethodNone, validClaims()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+				if err != nil {
+					t.Fatalf("failed to sign token: %v", err)
+				}
+				return token
+			},
+			wantErr: true,
+		},
+		{
+			name:    "garbage",
+			token:   func(t *testing.T) string { return "not.a.token" },
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := v.Verify(tt.token(t))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if claims.Subject != "user-1" || !slices.Equal(claims.Roles, []string{"customer"}) {
+				t.Errorf("Verify() = subject %q, roles %v", claims.Subject, claims.Roles)
+			}
+		})
+	}
+}
+
+func TestVerifierRS256(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	jwks := writeJWKS(t, map[string]*rsa.PublicKey{"k1": &key.PublicKey})
+
+	v, err := NewVerifier(Config{JWKSFile: jwks})
+	if err != nil {
+		t.Fatalf("NewVerifier() error = %v", err)
+	}
+
+	sign := func(key *rsa.PrivateKey, kid string) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, validClaims())
+		if kid != "" {
+			token.Header["kid"] = kid
+		}
+		signed, err := token.SignedString(key)
+		if err != nil {
+			t.Fatalf("failed to sign token: %v", err)
+		}
+		return signed
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{"known kid", sign(key, "k1"), false},
+		{"single key without kid", sign(key, ""), false},
+		{"unknown kid", sign(key, "k2"), true},
+		{"other key", sign(other, "k1"), true},
+		// HS256 не настроен, поэтому токен с секретом отклоняется
+		{"hs256 not configured", signHS256(t, testSecret, validClaims()), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := v.Verify(tt.token); (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNewVerifierConfig(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	dir := t.TempDir()
+	encryption := filepath.Join(dir, "enc.json")
+	writeFile(t, encryption, `{"keys":[{"kty":"RSA","use":"enc","n":"AQAB","e":"AQAB"}]}`)
+	invalid := filepath.Join(dir, "invalid.json")
+	writeFile(t, invalid, `{"keys":`)
+
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"hs256", Config{HMACSecret: testSecret}, false},
+		{"jwks", Config{JWKSFile: writeJWKS(t, map[string]*rsa.PublicKey{"k1": &key.PublicKey})}, false},
+		{"nothing configured", Config{}, true},
+		{"missing jwks file", Config{JWKSFile: filepath.Join(dir, "missing.json")}, true},
+		{"malformed jwks file", Config{JWKSFile: invalid}, true},
+		{"only encryption keys", Config{JWKSFile: encryption}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewVerifier(tt.cfg); (err != nil) != tt.wantErr {
+				t.Errorf("NewVerifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func writeJWKS(t *testing.T, keys map[string]*rsa.PublicKey) string {
+	t.Helper()
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	for kid, key := range keys {
+		set.Keys = append(set.Keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			Alg: "RS256",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	data, err := json.Marshal(set)
+	if err != nil {
+		t.Fatalf("failed to encode JWKS: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	writeFile(t, path, string(data))
+	return path
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
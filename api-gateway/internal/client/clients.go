@@ -4,8 +4,11 @@ import (
 	"context"
 	inventorypb "ecommerce-microservices/inventory-service/pb"
 	orderpb "ecommerce-microservices/order-service/pb"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/logging"
+	"ecommerce-microservices/pkg/tracing"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -20,7 +23,9 @@ type ServiceClients struct {
 	ordConn   *grpc.ClientConn
 }
 
-func NewServiceClients(invTarget, ordTarget string) (*ServiceClients, error) {
+// NewServiceClients connects to the backend services. The caller of each
+// request is forwarded in an identity token signed by signer.
+func NewServiceClients(invTarget, ordTarget string, signer *identity.Signer) (*ServiceClients, error) {
 	var wg sync.WaitGroup
 	var invClient inventorypb.InventoryServiceClient
 	var ordClient orderpb.OrderServiceClient
@@ -35,41 +40,47 @@ func NewServiceClients(invTarget, ordTarget string) (*ServiceClients, error) {
 	// Подключение к Inventory
 	go func() {
 		defer wg.Done()
-		log.Printf("API Gateway: Connecting to Inventory gRPC Service at %s", invTarget)
+		slog.Info("Connecting to Inventory gRPC Service", "target", invTarget)
 		ctx, cancel := context.WithTimeout(context.Background(), connTimeout)
 		defer cancel()
 		conn, err := grpc.DialContext(ctx, invTarget,
 			grpc.WithTransportCredentials(insecure.NewCredentials()), // Используем insecure для простоты
 			grpc.WithBlock(), // Ждем установления соединения
+			tracing.DialOption(),
+			grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor(), signer.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(logging.StreamClientInterceptor(), signer.StreamClientInterceptor()),
 		)
 		if err != nil {
 			invErr = fmt.Errorf("failed to dial inventory service (%s): %w", invTarget, err)
-			log.Printf("ERROR: %v", invErr)
+			slog.Error("Failed to connect to Inventory gRPC Service", "error", invErr)
 			return
 		}
 		invConn = conn
 		invClient = inventorypb.NewInventoryServiceClient(invConn)
-		log.Printf("API Gateway: Successfully connected to Inventory gRPC Service")
+		slog.Info("Connected to Inventory gRPC Service")
 	}()
 
 	// Подключение к Order
 	go func() {
 		defer wg.Done()
-		log.Printf("API Gateway: Connecting to Order gRPC Service at %s", ordTarget)
+		slog.Info("Connecting to Order gRPC Service", "target", ordTarget)
 		ctx, cancel := context.WithTimeout(context.Background(), connTimeout)
 		defer cancel()
 		conn, err := grpc.DialContext(ctx, ordTarget,
 			grpc.WithTransportCredentials(insecure.NewCredentials()),
 			grpc.WithBlock(),
+			tracing.DialOption(),
+			grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor(), signer.UnaryClientInterceptor()),
+			grpc.WithChainStreamInterceptor(logging.StreamClientInterceptor(), signer.StreamClientInterceptor()),
 		)
 		if err != nil {
 			ordErr = fmt.Errorf("failed to dial order service (%s): %w", ordTarget, err)
-			log.Printf("ERROR: %v", ordErr)
+			slog.Error("Failed to connect to Order gRPC Service", "error", ordErr)
 			return
 		}
 		ordConn = conn
 		ordClient = orderpb.NewOrderServiceClient(ordConn)
-		log.Printf("API Gateway: Successfully connected to Order gRPC Service")
+		slog.Info("Connected to Order gRPC Service")
 	}()
 
 	wg.Wait()
@@ -96,17 +107,17 @@ func NewServiceClients(invTarget, ordTarget string) (*ServiceClients, error) {
 }
 
 func (c *ServiceClients) Close() {
-	log.Println("API Gateway: Closing gRPC client connections...")
+	slog.Info("Closing gRPC client connections")
 	if c.invConn != nil {
 		err := c.invConn.Close()
 		if err != nil {
-			log.Printf("API Gateway: Error closing inventory connection: %v", err)
+			slog.Error("Error closing inventory connection", "error", err)
 		}
 	}
 	if c.ordConn != nil {
 		err := c.ordConn.Close()
 		if err != nil {
-			log.Printf("API Gateway: Error closing order connection: %v", err)
+			slog.Error("Error closing order connection", "error", err)
 		}
 	}
 }
@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"ecommerce-microservices/api-gateway/internal/problem"
+	inventorypb "ecommerce-microservices/inventory-service/pb"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionETag returns the strong ETag of a resource version. If-Match only
+// uses strong comparison, so the ETag must not be weak.
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// productETag returns the ETag of a product: its version and its stock
+// version. Reservations only change the stock version, so the ETag still
+// changes with the stock.
+func productETag(product *inventorypb.Product) string {
+	return `"` + strconv.FormatInt(product.Version, 10) + "." + strconv.FormatInt(product.StockVersion, 10) + `"`
+}
+
+// versionFromIfMatch returns the version the client based its change on.
+// Writes without If-Match are rejected with 428, and an If-Match that does
+// not hold a single ETag issued by the gateway can never match, so it is
+// rejected with 412. ok is false if the request was aborted.
+func versionFromIfMatch(c *gin.Context) (version int64, ok bool) {
+	value, ok := ifMatchValue(c)
+	if !ok {
+		return 0, false
+	}
+	version, ok = parseVersion(value)
+	if !ok {
+		abortIfMatchMismatch(c)
+		return 0, false
+	}
+	return version, true
+}
+
+// productVersionsFromIfMatch is versionFromIfMatch for product ETags. The
+// product service only compares the stock version if the stock changes, so
+// a stale stock does not block edits of the other fields.
+func productVersionsFromIfMatch(c *gin.Context) (version, stockVersion int64, ok bool) {
+	value, ok := ifMatchValue(c)
+	if !ok {
+		return 0, 0, false
+	}
+	versionPart, stockPart, found := strings.Cut(value, ".")
+	version, versionOK := parseVersion(versionPart)
+	stockVersion, stockOK := parseVersion(stockPart)
+	if !found || !versionOK || !stockOK {
+		abortIfMatchMismatch(c)
+		return 0, 0, false
+	}
+	return version, stockVersion, true
+}
+
+// ifMatchValue returns the If-Match ETag without its quotes.
+func ifMatchValue(c *gin.Context) (string, bool) {
+	header := strings.TrimSpace(c.GetHeader("If-Match"))
+	if header == "" {
+		problem.Abort(c, problem.New(http.StatusPreconditionRequired, problem.CodePreconditionRequired,
+			"The If-Match header with the ETag of the resource is required"))
+		return "", false
+	}
+
+	unquoted, found := strings.CutPrefix(header, `"`)
+	if found {
+		unquoted, found = strings.CutSuffix(unquoted, `"`)
+	}
+	if !found {
+		abortIfMatchMismatch(c)
+		return "", false
+	}
+	return unquoted, true
+}
+
+func parseVersion(s string) (int64, bool) {
+	version, err := strconv.ParseInt(s, 10, 64)
+	return version, err == nil && version >= 0
+}
+
+func abortIfMatchMismatch(c *gin.Context) {
+	problem.Abort(c, problem.New(http.StatusPreconditionFailed, problem.CodeVersionMismatch,
+		"If-Match does not match the current ETag of the resource"))
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	inventorypb "ecommerce-microservices/inventory-service/pb"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestVersionFromIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		ifMatch string
+		version int64
+		status  int // 0, если запрос не прерван
+	}{
+		{name: "strong etag", ifMatch: `"7"`, version: 7},
+		{name: "zero version", ifMatch: `"0"`, version: 0},
+		{name: "surrounding spaces", ifMatch: ` "12" `, version: 12},
+		{name: "missing", ifMatch: "", status: http.StatusPreconditionRequired},
+		{name: "unquoted", ifMatch: "7", status: http.StatusPreconditionFailed},
+		{name: "weak etag", ifMatch: `W/"7"`, status: http.StatusPreconditionFailed},
+		{name: "wildcard", ifMatch: "*", status: http.StatusPreconditionFailed},
+		{name: "several etags", ifMatch: `"7", "8"`, status: http.StatusPreconditionFailed},
+		{name: "negative", ifMatch: `"-1"`, status: http.StatusPreconditionFailed},
+		{name: "not a number", ifMatch: `"abc"`, status: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPut, "/api/v1/products/1", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			version, ok := versionFromIfMatch(c)
+			if ok != (tt.status == 0) {
+				t.Fatalf("ok = %v, want %v", ok, tt.status == 0)
+			}
+			if !ok {
+				if w.Code != tt.status {
+					t.Errorf("status = %d, want %d", w.Code, tt.status)
+				}
+				return
+			}
+			if version != tt.version {
+				t.Errorf("version = %d, want %d", version, tt.version)
+			}
+		})
+	}
+}
+
+func TestVersionETagRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, version := range []int64{0, 1, 42, 1 << 40} {
+		c, _ := gin.CreateTestContext(httptest.NewRecorder())
+		c.Request = httptest.NewRequest(http.MethodPut, "/", nil)
+		c.Request.Header.Set("If-Match", versionETag(version))
+
+		if got, ok := versionFromIfMatch(c); !ok || got != version {
+			t.Errorf("versionFromIfMatch(versionETag(%d)) = %d, %v", version, got, ok)
+		}
+	}
+}
+
+func TestProductVersionsFromIfMatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		ifMatch      string
+		version      int64
+		stockVersion int64
+		status       int // 0, если запрос не прерван
+	}{
+		{name: "product etag", ifMatch: `"7.12"`, version: 7, stockVersion: 12},
+		{name: "zero versions", ifMatch: `"0.0"`},
+		{name: "missing", ifMatch: "", status: http.StatusPreconditionRequired},
+		// ETag категории не подходит товару
+		{name: "version only", ifMatch: `"7"`, status: http.StatusPreconditionFailed},
+		{name: "empty stock version", ifMatch: `"7."`, status: http.StatusPreconditionFailed},
+		{name: "negative stock version", ifMatch: `"7.-1"`, status: http.StatusPreconditionFailed},
+		{name: "too many parts", ifMatch: `"7.1.2"`, status: http.StatusPreconditionFailed},
+		{name: "weak etag", ifMatch: `W/"7.1"`, status: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodPatch, "/api/v1/products/1", nil)
+			if tt.ifMatch != "" {
+				c.Request.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			version, stockVersion, ok := productVersionsFromIfMatch(c)
+			if ok != (tt.status == 0) {
+				t.Fatalf("ok = %v, want %v", ok, tt.status == 0)
+			}
+			if !ok {
+				if w.Code != tt.status {
+					t.Errorf("status = %d, want %d", w.Code, tt.status)
+				}
+				return
+			}
+			if version != tt.version || stockVersion != tt.stockVersion {
+				t.Errorf("versions = %d, %d, want %d, %d", version, stockVersion, tt.version, tt.stockVersion)
+			}
+		})
+	}
+}
+
+func TestProductETagRoundTrip(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	product := &inventorypb.Product{Version: 3, StockVersion: 41}
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodPatch, "/", nil)
+	c.Request.Header.Set("If-Match", productETag(product))
+
+	version, stockVersion, ok := productVersionsFromIfMatch(c)
+	if !ok || version != product.Version || stockVersion != product.StockVersion {
+		t.Errorf("productVersionsFromIfMatch(productETag()) = %d, %d, %v", version, stockVersion, ok)
+	}
+}
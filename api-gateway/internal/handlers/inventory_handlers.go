@@ -1,16 +1,23 @@
 package handlers
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
+	"ecommerce-microservices/api-gateway/internal/problem"
 	inventorypb "ecommerce-microservices/inventory-service/pb"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
 type InventoryHandler struct {
@@ -32,8 +39,8 @@ func (h *InventoryHandler) CreateProduct(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		log.Printf("API Gateway: Invalid input for %s: %v", requestInfo, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		slog.InfoContext(c.Request.Context(), "Invalid input", "request", requestInfo, "error", err)
+		problem.Abort(c, problem.FromBindingError(err))
 		return
 	}
 
@@ -48,14 +55,15 @@ func (h *InventoryHandler) CreateProduct(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s with data: %+v", requestInfo, grpcReq)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
 	resp, err := h.client.CreateProduct(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful, product ID: %s", requestInfo, resp.Product.Id)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "product_id", resp.Product.Id)
+	c.Header("ETag", productETag(resp.Product))
 	c.JSON(http.StatusCreated, resp.Product)
 }
 
@@ -64,24 +72,29 @@ func (h *InventoryHandler) GetProductByID(c *gin.Context) {
 	requestInfo := fmt.Sprintf("GetProductByID (ID: %s)", productID)
 
 	if productID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Product ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Product ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Product ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Product ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
-	grpcReq := &inventorypb.GetProductRequest{Id: productID}
+	includeDeleted, ok := bindIncludeDeleted(c, requestInfo)
+	if !ok {
+		return
+	}
+	grpcReq := &inventorypb.GetProductRequest{Id: productID, IncludeDeleted: includeDeleted}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s", requestInfo)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
 	resp, err := h.client.GetProductByID(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
+	c.Header("ETag", productETag(resp.Product))
 	c.JSON(http.StatusOK, resp.Product)
 }
 
@@ -90,8 +103,8 @@ func (h *InventoryHandler) UpdateProduct(c *gin.Context) {
 	requestInfo := fmt.Sprintf("UpdateProduct (ID: %s)", productID)
 
 	if productID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Product ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Product ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Product ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Product ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
@@ -104,41 +117,166 @@ func (h *InventoryHandler) UpdateProduct(c *gin.Context) {
 	}
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		log.Printf("API Gateway: Invalid input for %s: %v", requestInfo, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		slog.InfoContext(c.Request.Context(), "Invalid input", "request", requestInfo, "error", err)
+		problem.Abort(c, problem.FromBindingError(err))
+		return
+	}
+	version, stockVersion, ok := productVersionsFromIfMatch(c)
+	if !ok {
 		return
 	}
 
 	grpcReq := &inventorypb.UpdateProductRequest{
-		Id:          productID, // ID из URL
-		Name:        reqBody.Name,
-		Description: reqBody.Description,
-		Price:       reqBody.Price,
-		Stock:       reqBody.Stock,
-		CategoryId:  reqBody.CategoryID,
+		Id:           productID, // ID из URL
+		Name:         reqBody.Name,
+		Description:  reqBody.Description,
+		Price:        reqBody.Price,
+		Stock:        reqBody.Stock,
+		CategoryId:   reqBody.CategoryID,
+		Version:      &version,
+		StockVersion: &stockVersion,
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s with data: %+v", requestInfo, grpcReq)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
 	resp, err := h.client.UpdateProduct(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
+	c.Header("ETag", productETag(resp.Product))
 	c.JSON(http.StatusOK, resp.Product)
 }
 
+// mergePatchContentType is the media type of JSON merge patches (RFC 7396).
+const mergePatchContentType = "application/merge-patch+json"
+
+// PatchProduct applies a JSON merge patch to the product: only the fields
+// present in the body change. description can be cleared with null; the
+// other fields are required and cannot be null.
+func (h *InventoryHandler) PatchProduct(c *gin.Context) {
+	productID := c.Param("id")
+	requestInfo := fmt.Sprintf("PatchProduct (ID: %s)", productID)
+
+	if productID == "" {
+		slog.InfoContext(c.Request.Context(), "Invalid input: Product ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Product ID is required", problem.FieldError{Field: "id", Message: "is required"})
+		return
+	}
+	if ct := c.ContentType(); ct != mergePatchContentType && ct != gin.MIMEJSON {
+		c.Header("Accept-Patch", mergePatchContentType)
+		problem.Abort(c, problem.New(http.StatusUnsupportedMediaType, problem.CodeUnsupportedMediaType,
+			"The request body must be a JSON merge patch ("+mergePatchContentType+")"))
+		return
+	}
+
+	var patch map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&patch); err != nil {
+		slog.InfoContext(c.Request.Context(), "Invalid input", "request", requestInfo, "error", err)
+		problem.Abort(c, problem.FromBindingError(err))
+		return
+	}
+	if len(patch) == 0 {
+		problem.BadRequest(c, "The patch must change at least one field")
+		return
+	}
+	grpcReq, fieldErrs := productPatchRequest(patch)
+	if len(fieldErrs) > 0 {
+		slog.InfoContext(c.Request.Context(), "Invalid patch", "request", requestInfo, "errors", len(fieldErrs))
+		problem.BadRequest(c, "The request has invalid fields", fieldErrs...)
+		return
+	}
+	version, stockVersion, ok := productVersionsFromIfMatch(c)
+	if !ok {
+		return
+	}
+	grpcReq.Id = productID
+	grpcReq.Version = &version
+	grpcReq.StockVersion = &stockVersion
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
+	resp, err := h.client.UpdateProduct(ctx, grpcReq)
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
+	c.Header("ETag", productETag(resp.Product))
+	c.JSON(http.StatusOK, resp.Product)
+}
+
+// productPatchRequest turns a merge patch into an update request whose
+// mask names exactly the patched fields.
+func productPatchRequest(patch map[string]json.RawMessage) (*inventorypb.UpdateProductRequest, []problem.FieldError) {
+	req := &inventorypb.UpdateProductRequest{UpdateMask: &fieldmaskpb.FieldMask{}}
+	var fieldErrs []problem.FieldError
+	fail := func(field, message string) {
+		fieldErrs = append(fieldErrs, problem.FieldError{Field: field, Message: message})
+	}
+
+	fields := make([]string, 0, len(patch))
+	for field := range patch {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	for _, field := range fields {
+		raw := patch[field]
+		isNull := string(bytes.TrimSpace(raw)) == "null"
+		if isNull && field != "description" {
+			fail(field, "cannot be null")
+			continue
+		}
+		var err error
+		switch field {
+		case "name":
+			if err = json.Unmarshal(raw, &req.Name); err == nil && req.Name == "" {
+				fail(field, "must not be empty")
+			}
+		case "description":
+			if !isNull {
+				err = json.Unmarshal(raw, &req.Description)
+			}
+		case "price":
+			if err = json.Unmarshal(raw, &req.Price); err == nil && req.Price <= 0 {
+				fail(field, "must be greater than 0")
+			}
+		case "stock":
+			if err = json.Unmarshal(raw, &req.Stock); err == nil && req.Stock < 0 {
+				fail(field, "must be at least 0")
+			}
+		case "category_id":
+			if err = json.Unmarshal(raw, &req.CategoryId); err == nil && req.CategoryId == "" {
+				fail(field, "must not be empty")
+			}
+		default:
+			fail(field, "cannot be changed")
+			continue
+		}
+		if err != nil {
+			fail(field, "has the wrong type")
+			continue
+		}
+		req.UpdateMask.Paths = append(req.UpdateMask.Paths, field)
+	}
+	return req, fieldErrs
+}
+
 func (h *InventoryHandler) DeleteProduct(c *gin.Context) {
 	productID := c.Param("id")
 	requestInfo := fmt.Sprintf("DeleteProduct (ID: %s)", productID)
 
 	if productID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Product ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Product ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Product ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Product ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
@@ -147,59 +285,340 @@ func (h *InventoryHandler) DeleteProduct(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s", requestInfo)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
 	_, err := h.client.DeleteProduct(ctx, grpcReq) // Ответ пустой (Empty)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
 	c.Status(http.StatusNoContent)
 }
 
+// RestoreProduct brings back a deleted product. A product deleted together
+// with its category is restored through the category.
+func (h *InventoryHandler) RestoreProduct(c *gin.Context) {
+	productID := c.Param("id")
+	requestInfo := fmt.Sprintf("RestoreProduct (ID: %s)", productID)
+
+	if productID == "" {
+		slog.InfoContext(c.Request.Context(), "Invalid input: Product ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Product ID is required", problem.FieldError{Field: "id", Message: "is required"})
+		return
+	}
+
+	grpcReq := &inventorypb.RestoreProductRequest{Id: productID}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
+	resp, err := h.client.RestoreProduct(ctx, grpcReq)
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
+	c.Header("ETag", productETag(resp.Product))
+	c.JSON(http.StatusOK, resp.Product)
+}
+
 func (h *InventoryHandler) ListProducts(c *gin.Context) {
 	requestInfo := "ListProducts"
-	pageSizeStr := c.DefaultQuery("page_size", "10")
-	pageNumStr := c.DefaultQuery("page", "1")
 	categoryFilter := c.Query("category_id")
 
+	paging, ok := bindListPaging(c, requestInfo)
+	if !ok {
+		return
+	}
+
+	grpcReq := &inventorypb.ListProductsRequest{
+		PageSize:       int32(paging.PageSize),
+		PageToken:      paging.PageToken,
+		SkipTotalCount: !paging.IncludeTotal,
+	}
+	if paging.PageToken == "" {
+		grpcReq.PageNumber = int32(paging.Page)
+	}
+	if fields := bindProductListQuery(c, grpcReq); len(fields) > 0 {
+		slog.InfoContext(c.Request.Context(), "Invalid product list filters", "request", requestInfo, "fields", len(fields))
+		problem.BadRequest(c, "Invalid product list filters", fields...)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
+	resp, err := h.client.ListProducts(ctx, grpcReq)
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "products", len(resp.Products), "total", resp.GetTotalCount())
+	c.JSON(http.StatusOK, paging.response(gin.H{
+		"data":            resp.Products,
+		"category_filter": categoryFilter,
+	}, resp.TotalCount, resp.NextPageToken))
+}
+
+// productSortFields maps the sort query parameter of ListProducts.
+var productSortFields = map[string]inventorypb.ProductSortField{
+	"created_at": inventorypb.ProductSortField_PRODUCT_SORT_FIELD_CREATED_AT,
+	"updated_at": inventorypb.ProductSortField_PRODUCT_SORT_FIELD_UPDATED_AT,
+	"price":      inventorypb.ProductSortField_PRODUCT_SORT_FIELD_PRICE,
+	"name":       inventorypb.ProductSortField_PRODUCT_SORT_FIELD_NAME,
+	"stock":      inventorypb.ProductSortField_PRODUCT_SORT_FIELD_STOCK,
+}
+
+// sortOrders maps the order query parameter.
+var sortOrders = map[string]inventorypb.SortOrder{
+	"asc":  inventorypb.SortOrder_SORT_ORDER_ASC,
+	"desc": inventorypb.SortOrder_SORT_ORDER_DESC,
+}
+
+// bindProductListQuery copies the filters (see bindProductFilter) and the
+// sort order of a product list from the query string into req:
+//
+//	sort             created_at (default), updated_at, price, name or stock
+//	order            asc or desc; dates default to desc, others to asc
+//	include_deleted  true to also list deleted products (catalog admins only)
+//
+// It returns the parameters that could not be parsed.
+func bindProductListQuery(c *gin.Context, req *inventorypb.ListProductsRequest) []problem.FieldError {
+	filter, fields := bindProductFilter(c)
+	req.CategoryIds = filter.CategoryIds
+	req.MinPrice = filter.MinPrice
+	req.MaxPrice = filter.MaxPrice
+	req.InStockOnly = filter.InStockOnly
+	req.NamePrefix = filter.NamePrefix
+	req.CreatedAfter = filter.CreatedAfter
+	req.CreatedBefore = filter.CreatedBefore
+	req.UpdatedAfter = filter.UpdatedAfter
+	req.UpdatedBefore = filter.UpdatedBefore
+	req.IncludeDescendants = filter.IncludeDescendants
+
+	if value, ok := c.GetQuery("sort"); ok {
+		sortBy, known := productSortFields[value]
+		if !known {
+			fields = append(fields, problem.FieldError{Field: "sort", Message: "must be one of: created_at, updated_at, price, name, stock"})
+		}
+		req.SortBy = sortBy
+	}
+	if value, ok := c.GetQuery("order"); ok {
+		order, known := sortOrders[value]
+		if !known {
+			fields = append(fields, problem.FieldError{Field: "order", Message: "must be one of: asc, desc"})
+		}
+		req.SortOrder = order
+	}
+	if value, ok := c.GetQuery("include_deleted"); ok {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			fields = append(fields, problem.FieldError{Field: "include_deleted", Message: "must be true or false"})
+		}
+		req.IncludeDeleted = include
+	}
+	return fields
+}
+
+// bindIncludeDeleted reads the include_deleted query parameter of the
+// single-resource reads. The backend only allows it to catalog admins.
+func bindIncludeDeleted(c *gin.Context, requestInfo string) (bool, bool) {
+	value, ok := c.GetQuery("include_deleted")
+	if !ok {
+		return false, true
+	}
+	include, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.InfoContext(c.Request.Context(), "Invalid input: include_deleted is not a boolean", "request", requestInfo, "include_deleted", value)
+		problem.BadRequest(c, "The query has invalid parameters", problem.FieldError{Field: "include_deleted", Message: "must be true or false"})
+		return false, false
+	}
+	return include, true
+}
+
+// bindProductFilter reads the product filters from the query string:
+//
+//	category_id          repeated or comma-separated category IDs
+//	include_descendants  true to also match all subcategories of category_id
+//	min_price            inclusive lower price bound
+//	max_price            inclusive upper price bound
+//	in_stock             true to skip products that are out of stock
+//	name_prefix          start of the name, case-insensitive
+//	created_after        RFC 3339, inclusive; also created_before (exclusive),
+//	                     updated_after and updated_before
+//
+// It returns the parameters that could not be parsed. Ranges and limits are
+// checked by inventory-service.
+func bindProductFilter(c *gin.Context) (*inventorypb.ProductFilter, []problem.FieldError) {
+	filter := &inventorypb.ProductFilter{}
+	var fields []problem.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, problem.FieldError{Field: field, Message: message})
+	}
+
+	for _, value := range c.QueryArray("category_id") {
+		for _, id := range strings.Split(value, ",") {
+			if id = strings.TrimSpace(id); id != "" {
+				filter.CategoryIds = append(filter.CategoryIds, id)
+			}
+		}
+	}
+
+	price := func(name string) *float64 {
+		value, ok := c.GetQuery(name)
+		if !ok {
+			return nil
+		}
+		p, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			invalid(name, "must be a number")
+			return nil
+		}
+		return &p
+	}
+	filter.MinPrice = price("min_price")
+	filter.MaxPrice = price("max_price")
+
+	if value, ok := c.GetQuery("in_stock"); ok {
+		inStock, err := strconv.ParseBool(value)
+		if err != nil {
+			invalid("in_stock", "must be true or false")
+		}
+		filter.InStockOnly = inStock
+	}
+	filter.NamePrefix = c.Query("name_prefix")
+	if value, ok := c.GetQuery("include_descendants"); ok {
+		include, err := strconv.ParseBool(value)
+		if err != nil {
+			invalid("include_descendants", "must be true or false")
+		}
+		filter.IncludeDescendants = include
+	}
+
+	timestamp := func(name string) *timestamppb.Timestamp {
+		value, ok := c.GetQuery(name)
+		if !ok {
+			return nil
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			invalid(name, "must be an RFC 3339 timestamp")
+			return nil
+		}
+		return timestamppb.New(t)
+	}
+	filter.CreatedAfter = timestamp("created_after")
+	filter.CreatedBefore = timestamp("created_before")
+	filter.UpdatedAfter = timestamp("updated_after")
+	filter.UpdatedBefore = timestamp("updated_before")
+	return filter, fields
+}
+
+// GetProductFacets counts the products matching the filters of
+// bindProductFilter per category, by availability and per price bucket.
+// price_buckets takes comma-separated ascending bucket boundaries, e.g.
+// "50,100,500" for [0, 50), [50, 100), [100, 500) and [500, +inf).
+func (h *InventoryHandler) GetProductFacets(c *gin.Context) {
+	requestInfo := "GetProductFacets"
+
+	filter, fields := bindProductFilter(c)
+	grpcReq := &inventorypb.GetProductFacetsRequest{Filter: filter}
+	if value := c.Query("price_buckets"); value != "" {
+		for _, part := range strings.Split(value, ",") {
+			boundary, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+			if err != nil {
+				fields = append(fields, problem.FieldError{Field: "price_buckets", Message: "must be comma-separated numbers"})
+				break
+			}
+			grpcReq.PriceBoundaries = append(grpcReq.PriceBoundaries, boundary)
+		}
+	}
+	if len(fields) > 0 {
+		slog.InfoContext(c.Request.Context(), "Invalid product facet filters", "request", requestInfo, "fields", len(fields))
+		problem.BadRequest(c, "Invalid product facet filters", fields...)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
+	resp, err := h.client.GetProductFacets(ctx, grpcReq)
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "categories", len(resp.Categories))
+	c.JSON(http.StatusOK, gin.H{
+		"categories": resp.Categories,
+		"availability": gin.H{
+			"in_stock":     resp.InStockCount,
+			"out_of_stock": resp.OutOfStockCount,
+		},
+		"price_buckets": resp.PriceBuckets,
+	})
+}
+
+// maxSearchQueryLength mirrors the limit of inventory-service.
+const maxSearchQueryLength = 200
+
+// SearchProducts runs a full-text search over product names and
+// descriptions. Results are ordered by relevance; the highlights of each hit
+// are HTML-escaped with the matched words wrapped in <em>.
+func (h *InventoryHandler) SearchProducts(c *gin.Context) {
+	requestInfo := "SearchProducts"
+	query := strings.TrimSpace(c.Query("q"))
+	if query == "" {
+		problem.BadRequest(c, "Query parameter 'q' is required", problem.FieldError{Field: "q", Message: "is required"})
+		return
+	}
+	if len(query) > maxSearchQueryLength {
+		problem.BadRequest(c, "Query parameter 'q' is too long", problem.FieldError{Field: "q", Message: fmt.Sprintf("must have at most %d characters", maxSearchQueryLength)})
+		return
+	}
+
+	pageSizeStr := c.DefaultQuery("page_size", "10")
+	pageNumStr := c.DefaultQuery("page", "1")
 	pageSize, err1 := strconv.ParseInt(pageSizeStr, 10, 32)
 	pageNum, err2 := strconv.ParseInt(pageNumStr, 10, 32)
-
 	if err1 != nil || err2 != nil || pageSize <= 0 || pageNum <= 0 {
-		log.Printf("API Gateway: Invalid pagination parameters for %s: page_size=%s, page=%s", requestInfo, pageSizeStr, pageNumStr)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters. 'page_size' and 'page' must be positive integers."})
+		slog.InfoContext(c.Request.Context(), "Invalid pagination parameters", "request", requestInfo, "page_size", pageSizeStr, "page", pageNumStr)
+		abortInvalidPagination(c, err1 == nil && pageSize > 0, err2 == nil && pageNum > 0)
 		return
 	}
-
-	if pageSize > 100 {
-		pageSize = 100
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
 	}
 
-	grpcReq := &inventorypb.ListProductsRequest{
-		PageSize:         int32(pageSize),
-		PageNumber:       int32(pageNum),
-		CategoryIdFilter: categoryFilter,
+	grpcReq := &inventorypb.SearchProductsRequest{
+		Query:      query,
+		PageSize:   int32(pageSize),
+		PageNumber: int32(pageNum),
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s with params: %+v", requestInfo, grpcReq)
-	resp, err := h.client.ListProducts(ctx, grpcReq)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
+	resp, err := h.client.SearchProducts(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful, found %d products (total: %d)", requestInfo, len(resp.Products), resp.TotalCount)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "hits", len(resp.Hits), "total", resp.TotalCount)
 	c.JSON(http.StatusOK, gin.H{
-		"data":            resp.Products,
-		"total":           resp.TotalCount,
-		"page":            pageNum,
-		"page_size":       pageSize,
-		"category_filter": categoryFilter,
+		"data":      resp.Hits,
+		"total":     resp.TotalCount,
+		"page":      pageNum,
+		"page_size": pageSize,
+		"query":     query,
 	})
 }
 
@@ -208,28 +627,35 @@ func (h *InventoryHandler) ListProducts(c *gin.Context) {
 func (h *InventoryHandler) CreateCategory(c *gin.Context) {
 	requestInfo := "CreateCategory"
 	var reqBody struct {
-		Name string `json:"name" binding:"required"`
+		Name     string `json:"name" binding:"required"`
+		ParentID string `json:"parent_id"`
+		Slug     string `json:"slug"`
 	}
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		log.Printf("API Gateway: Invalid input for %s: %v", requestInfo, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		slog.InfoContext(c.Request.Context(), "Invalid input", "request", requestInfo, "error", err)
+		problem.Abort(c, problem.FromBindingError(err))
 		return
 	}
 
-	grpcReq := &inventorypb.CreateCategoryRequest{Name: reqBody.Name}
+	grpcReq := &inventorypb.CreateCategoryRequest{
+		Name:     reqBody.Name,
+		ParentId: reqBody.ParentID,
+		Slug:     reqBody.Slug,
+	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s with name: %s", requestInfo, grpcReq.Name)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "name", grpcReq.Name)
 	resp, err := h.client.CreateCategory(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful, category ID: %s", requestInfo, resp.Category.Id)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "category_id", resp.Category.Id)
+	c.Header("ETag", versionETag(resp.Category.Version))
 	c.JSON(http.StatusCreated, resp.Category)
 }
 
@@ -238,25 +664,63 @@ func (h *InventoryHandler) GetCategoryByID(c *gin.Context) {
 	requestInfo := fmt.Sprintf("GetCategoryByID (ID: %s)", categoryID)
 
 	if categoryID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Category ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Category ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Category ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Category ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
-	grpcReq := &inventorypb.GetCategoryRequest{Id: categoryID}
+	includeDeleted, ok := bindIncludeDeleted(c, requestInfo)
+	if !ok {
+		return
+	}
+	grpcReq := &inventorypb.GetCategoryRequest{Id: categoryID, IncludeDeleted: includeDeleted}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s", requestInfo)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
 	resp, err := h.client.GetCategoryByID(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
-	c.JSON(http.StatusOK, resp.Category)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
+	c.Header("ETag", versionETag(resp.Category.Version))
+	c.JSON(http.StatusOK, struct {
+		*inventorypb.Category
+		Breadcrumbs []*inventorypb.Breadcrumb `json:"breadcrumbs"`
+	}{resp.Category, resp.Breadcrumbs})
+}
+
+// GetCategoryTree returns the categories as nested trees, the whole catalog
+// or only below root_id. max_depth limits the levels below the roots.
+func (h *InventoryHandler) GetCategoryTree(c *gin.Context) {
+	requestInfo := "GetCategoryTree"
+
+	grpcReq := &inventorypb.GetCategoryTreeRequest{RootId: c.Query("root_id")}
+	if value, ok := c.GetQuery("max_depth"); ok {
+		maxDepth, err := strconv.ParseInt(value, 10, 32)
+		if err != nil || maxDepth < 0 {
+			problem.BadRequest(c, "Invalid category tree parameters",
+				problem.FieldError{Field: "max_depth", Message: "must be a non-negative integer"})
+			return
+		}
+		grpcReq.MaxDepth = int32(maxDepth)
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
+	resp, err := h.client.GetCategoryTree(ctx, grpcReq)
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "roots", len(resp.Roots))
+	c.JSON(http.StatusOK, gin.H{"data": resp.Roots})
 }
 
 func (h *InventoryHandler) UpdateCategory(c *gin.Context) {
@@ -264,81 +728,154 @@ func (h *InventoryHandler) UpdateCategory(c *gin.Context) {
 	requestInfo := fmt.Sprintf("UpdateCategory (ID: %s)", categoryID)
 
 	if categoryID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Category ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Category ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Category ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Category ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
+	// parent_id и slug необязательны: если их нет, они не меняются.
+	// Пустой parent_id делает категорию корневой
 	var reqBody struct {
-		Name string `json:"name" binding:"required"`
+		Name     string  `json:"name" binding:"required"`
+		ParentID *string `json:"parent_id"`
+		Slug     *string `json:"slug"`
 	}
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		log.Printf("API Gateway: Invalid input for %s: %v", requestInfo, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		slog.InfoContext(c.Request.Context(), "Invalid input", "request", requestInfo, "error", err)
+		problem.Abort(c, problem.FromBindingError(err))
+		return
+	}
+
+	version, ok := versionFromIfMatch(c)
+	if !ok {
 		return
 	}
 
 	grpcReq := &inventorypb.UpdateCategoryRequest{
-		Id:   categoryID,
-		Name: reqBody.Name,
+		Id:       categoryID,
+		Name:     reqBody.Name,
+		Version:  &version,
+		ParentId: reqBody.ParentID,
+		Slug:     reqBody.Slug,
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s with data: %+v", requestInfo, grpcReq)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
 	resp, err := h.client.UpdateCategory(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
+	c.Header("ETag", versionETag(resp.Category.Version))
 	c.JSON(http.StatusOK, resp.Category)
 }
 
+// categoryDeleteModes maps the mode query parameter of DeleteCategory.
+var categoryDeleteModes = map[string]inventorypb.DeleteCategoryMode{
+	"reject":   inventorypb.DeleteCategoryMode_DELETE_CATEGORY_MODE_REJECT,
+	"reassign": inventorypb.DeleteCategoryMode_DELETE_CATEGORY_MODE_REASSIGN,
+	"cascade":  inventorypb.DeleteCategoryMode_DELETE_CATEGORY_MODE_CASCADE,
+}
+
+// DeleteCategory deletes a category. The mode query parameter decides what
+// happens to its products and subcategories:
+//
+//	reject    (default) fail with 409 CATEGORY_NOT_EMPTY if there are any
+//	reassign  move them to the category given by reassign_to
+//	cascade   delete the subtree together with its products
 func (h *InventoryHandler) DeleteCategory(c *gin.Context) {
 	categoryID := c.Param("id")
 	requestInfo := fmt.Sprintf("DeleteCategory (ID: %s)", categoryID)
 
 	if categoryID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Category ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Category ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Category ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Category ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
-	grpcReq := &inventorypb.DeleteCategoryRequest{Id: categoryID}
+	grpcReq := &inventorypb.DeleteCategoryRequest{Id: categoryID, ReassignToCategoryId: c.Query("reassign_to")}
+	if value, ok := c.GetQuery("mode"); ok {
+		mode, known := categoryDeleteModes[value]
+		if !known {
+			slog.InfoContext(c.Request.Context(), "Invalid input: unknown delete mode", "request", requestInfo, "mode", value)
+			problem.BadRequest(c, "The query has invalid parameters", problem.FieldError{Field: "mode", Message: "must be one of: reject, reassign, cascade"})
+			return
+		}
+		grpcReq.Mode = mode
+	}
+	if grpcReq.Mode == inventorypb.DeleteCategoryMode_DELETE_CATEGORY_MODE_REASSIGN && grpcReq.ReassignToCategoryId == "" {
+		slog.InfoContext(c.Request.Context(), "Invalid input: reassign target is missing", "request", requestInfo)
+		problem.BadRequest(c, "The query has invalid parameters", problem.FieldError{Field: "reassign_to", Message: "is required for mode reassign"})
+		return
+	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s", requestInfo)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
 	_, err := h.client.DeleteCategory(ctx, grpcReq) // Ответ пустой (Empty)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
 	c.Status(http.StatusNoContent)
 }
 
+// RestoreCategory brings back a deleted category together with the
+// subcategories and products deleted with it.
+func (h *InventoryHandler) RestoreCategory(c *gin.Context) {
+	categoryID := c.Param("id")
+	requestInfo := fmt.Sprintf("RestoreCategory (ID: %s)", categoryID)
+
+	if categoryID == "" {
+		slog.InfoContext(c.Request.Context(), "Invalid input: Category ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Category ID is required", problem.FieldError{Field: "id", Message: "is required"})
+		return
+	}
+
+	grpcReq := &inventorypb.RestoreCategoryRequest{Id: categoryID}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
+	resp, err := h.client.RestoreCategory(ctx, grpcReq)
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
+	c.Header("ETag", versionETag(resp.Category.Version))
+	c.JSON(http.StatusOK, resp.Category)
+}
+
 func (h *InventoryHandler) ListCategories(c *gin.Context) {
 	requestInfo := "ListCategories"
 
-	grpcReq := &inventorypb.ListCategoriesRequest{}
+	includeDeleted, ok := bindIncludeDeleted(c, requestInfo)
+	if !ok {
+		return
+	}
+	grpcReq := &inventorypb.ListCategoriesRequest{IncludeDeleted: includeDeleted}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s", requestInfo)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
 	resp, err := h.client.ListCategories(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful, found %d categories", requestInfo, len(resp.Categories))
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "categories", len(resp.Categories))
 	c.JSON(http.StatusOK, resp.Categories)
 }
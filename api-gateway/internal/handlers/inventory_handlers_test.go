@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"ecommerce-microservices/api-gateway/internal/problem"
+	inventorypb "ecommerce-microservices/inventory-service/pb"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+func TestProductPatchRequest(t *testing.T) {
+	tests := []struct {
+		name      string
+		patch     string
+		want      *inventorypb.UpdateProductRequest
+		fieldErrs []problem.FieldError
+	}{
+		{
+			name:  "single field",
+			patch: `{"price": 12.5}`,
+			want: &inventorypb.UpdateProductRequest{
+				Price:      12.5,
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"price"}},
+			},
+		},
+		{
+			name:  "all fields in sorted mask",
+			patch: `{"stock": 0, "name": "Mouse", "category_id": "c1", "description": "Wireless", "price": 20}`,
+			want: &inventorypb.UpdateProductRequest{
+				Name:        "Mouse",
+				Description: "Wireless",
+				Price:       20,
+				Stock:       0,
+				CategoryId:  "c1",
+				UpdateMask:  &fieldmaskpb.FieldMask{Paths: []string{"category_id", "description", "name", "price", "stock"}},
+			},
+		},
+		{
+			name:  "null clears the description",
+			patch: `{"description": null}`,
+			want: &inventorypb.UpdateProductRequest{
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+			},
+		},
+		{
+			name:      "null is rejected for required fields",
+			patch:     `{"name": null}`,
+			fieldErrs: []problem.FieldError{{Field: "name", Message: "cannot be null"}},
+		},
+		{
+			name:      "empty name",
+			patch:     `{"name": ""}`,
+			fieldErrs: []problem.FieldError{{Field: "name", Message: "must not be empty"}},
+		},
+		{
+			name:      "price must be positive",
+			patch:     `{"price": 0}`,
+			fieldErrs: []problem.FieldError{{Field: "price", Message: "must be greater than 0"}},
+		},
+		{
+			name:      "negative stock",
+			patch:     `{"stock": -1}`,
+			fieldErrs: []problem.FieldError{{Field: "stock", Message: "must be at least 0"}},
+		},
+		{
+			name:      "empty category",
+			patch:     `{"category_id": ""}`,
+			fieldErrs: []problem.FieldError{{Field: "category_id", Message: "must not be empty"}},
+		},
+		{
+			name:      "wrong type",
+			patch:     `{"stock": "many"}`,
+			fieldErrs: []problem.FieldError{{Field: "stock", Message: "has the wrong type"}},
+		},
+		{
+			name:  "read-only and unknown fields",
+			patch: `{"version": 3, "id": "p1", "colour": "red"}`,
+			fieldErrs: []problem.FieldError{
+				{Field: "colour", Message: "cannot be changed"},
+				{Field: "id", Message: "cannot be changed"},
+				{Field: "version", Message: "cannot be changed"},
+			},
+		},
+		{
+			name:  "all errors are reported",
+			patch: `{"price": -5, "name": null, "stock": 3}`,
+			fieldErrs: []problem.FieldError{
+				{Field: "name", Message: "cannot be null"},
+				{Field: "price", Message: "must be greater than 0"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var patch map[string]json.RawMessage
+			if err := json.Unmarshal([]byte(tt.patch), &patch); err != nil {
+				t.Fatalf("invalid test patch: %v", err)
+			}
+
+			got, fieldErrs := productPatchRequest(patch)
+			if !reflect.DeepEqual(fieldErrs, tt.fieldErrs) {
+				t.Fatalf("field errors = %v, want %v", fieldErrs, tt.fieldErrs)
+			}
+			if tt.want != nil && !proto.Equal(got, tt.want) {
+				t.Errorf("request = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservices/api-gateway/internal/problem"
+	orderpb "ecommerce-microservices/order-service/pb"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sseHeartbeatInterval = 15 * time.Second
+	// sseWriteTimeout replaces the server WriteTimeout for each write, so a
+	// long-lived stream is not cut off after the first 15 seconds.
+	sseWriteTimeout = 10 * time.Second
+	// sseRetry tells the browser how long to wait before reconnecting.
+	sseRetry = 3000 * time.Millisecond
+)
+
+// StreamOrderEvents relays OrderService.WatchOrder as Server-Sent Events.
+// Each event carries the order sequence number as its id, so a reconnecting
+// client sends it back in Last-Event-ID and does not get the same change
+// twice.
+func (h *OrderHandler) StreamOrderEvents(c *gin.Context) {
+	orderID := c.Param("id")
+	requestInfo := fmt.Sprintf("StreamOrderEvents (ID: %s)", orderID)
+
+	if orderID == "" {
+		slog.InfoContext(c.Request.Context(), "Invalid input: Order ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Order ID is required", problem.FieldError{Field: "id", Message: "is required"})
+		return
+	}
+
+	lastEventID := int64(-1)
+	if header := c.GetHeader("Last-Event-ID"); header != "" {
+		parsed, err := strconv.ParseInt(header, 10, 64)
+		if err != nil || parsed < 0 {
+			slog.InfoContext(c.Request.Context(), "Invalid Last-Event-ID", "request", requestInfo, "last_event_id", header)
+			problem.BadRequest(c, "Last-Event-ID must be a non-negative integer", problem.FieldError{Field: "Last-Event-ID", Message: "must be a non-negative integer"})
+			return
+		}
+		lastEventID = parsed
+	}
+
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
+	stream, err := h.client.WatchOrder(ctx, &orderpb.GetOrderRequest{Id: orderID})
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+
+	// Ошибки вроде NotFound приходят только с первым сообщением, поэтому
+	// читаем его до отправки заголовков
+	first, err := stream.Recv()
+	if err != nil {
+		mapGrpcToHttpError(c, err, requestInfo)
+		return
+	}
+	if first.Sequence <= lastEventID && isTerminalOrderStatus(first.Order.GetStatus()) {
+		// 204 останавливает автоматическое переподключение EventSource
+		slog.InfoContext(c.Request.Context(), "No new events, order is in terminal status", "request", requestInfo)
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	events := make(chan *orderpb.OrderEvent)
+	streamErr := make(chan error, 1)
+	go func() {
+		for {
+			event, err := stream.Recv()
+			if err != nil {
+				streamErr <- err
+				return
+			}
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	rc := http.NewResponseController(c.Writer)
+	write := func(render func() error) bool {
+		if err := rc.SetWriteDeadline(time.Now().Add(sseWriteTimeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			slog.WarnContext(c.Request.Context(), "Failed to set write deadline", "request", requestInfo, "error", err)
+		}
+		if err := render(); err != nil {
+			slog.WarnContext(c.Request.Context(), "Failed to write event", "request", requestInfo, "error", err)
+			return false
+		}
+		if err := rc.Flush(); err != nil {
+			slog.WarnContext(c.Request.Context(), "Failed to flush event", "request", requestInfo, "error", err)
+			return false
+		}
+		return true
+	}
+	sendOrder := func(event *orderpb.OrderEvent) bool {
+		if event.Sequence <= lastEventID {
+			return true
+		}
+		lastEventID = event.Sequence
+		return write(func() error {
+			return sse.Encode(c.Writer, sse.Event{
+				Id:    strconv.FormatInt(event.Sequence, 10),
+				Event: "order",
+				Retry: uint(sseRetry.Milliseconds()),
+				Data:  event.Order,
+			})
+		})
+	}
+
+	if !sendOrder(first) {
+		return
+	}
+
+	heartbeat := time.NewTicker(sseHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case event := <-events:
+			if !sendOrder(event) {
+				return
+			}
+		case err := <-streamErr:
+			if errors.Is(err, io.EOF) {
+				slog.InfoContext(c.Request.Context(), "Stream finished, order reached terminal status", "request", requestInfo)
+				write(func() error {
+					return sse.Encode(c.Writer, sse.Event{Event: "end", Data: "order reached terminal status"})
+				})
+				return
+			}
+			slog.ErrorContext(c.Request.Context(), "gRPC stream failed", "request", requestInfo, "error", err)
+			write(func() error {
+				return sse.Encode(c.Writer, sse.Event{Event: "error", Data: "order updates are temporarily unavailable"})
+			})
+			return
+		case <-heartbeat.C:
+			if !write(func() error {
+				_, err := io.WriteString(c.Writer, ": heartbeat\n\n")
+				return err
+			}) {
+				return
+			}
+		case <-ctx.Done():
+			slog.InfoContext(c.Request.Context(), "Client disconnected", "request", requestInfo)
+			return
+		}
+	}
+}
+
+func isTerminalOrderStatus(status orderpb.OrderStatus) bool {
+	switch status {
+	case orderpb.OrderStatus_COMPLETED, orderpb.OrderStatus_CANCELLED, orderpb.OrderStatus_FAILED:
+		return true
+	}
+	return false
+}
@@ -3,15 +3,26 @@ package handlers
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
+	"ecommerce-microservices/api-gateway/internal/auth"
+	"ecommerce-microservices/api-gateway/internal/problem"
 	orderpb "ecommerce-microservices/order-service/pb"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	idempotencyKeyMetadata     = "idempotency-key"
+	idempotentReplayedMetadata = "idempotent-replayed"
 )
 
 type OrderHandler struct {
@@ -24,17 +35,17 @@ func NewOrderHandler(client orderpb.OrderServiceClient) *OrderHandler {
 
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	requestInfo := "CreateOrder"
+	userID := auth.UserID(c)
 	var reqBody struct {
-		UserID string `json:"user_id" binding:"required"`
-		Items  []struct {
+		Items []struct {
 			ProductID string `json:"product_id" binding:"required"`
 			Quantity  int32  `json:"quantity" binding:"required,gt=0"`
 		} `json:"items" binding:"required,min=1,dive"`
 	}
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		log.Printf("API Gateway: Invalid input for %s: %v", requestInfo, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		slog.InfoContext(c.Request.Context(), "Invalid input", "request", requestInfo, "error", err)
+		problem.Abort(c, problem.FromBindingError(err))
 		return
 	}
 
@@ -47,21 +58,32 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	}
 
 	grpcReq := &orderpb.CreateOrderRequest{
-		UserId: reqBody.UserID,
+		UserId: userID,
 		Items:  grpcItems,
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s for user %s with %d items", requestInfo, reqBody.UserID, len(grpcItems))
-	resp, err := h.client.CreateOrder(ctx, grpcReq)
+	// Повторы с тем же Idempotency-Key возвращают исходный заказ
+	if key := c.GetHeader(idempotencyKeyHeader); key != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, idempotencyKeyMetadata, key)
+	}
+
+	var header metadata.MD
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "user_id", userID, "items", len(grpcItems))
+	resp, err := h.client.CreateOrder(ctx, grpcReq, grpc.Header(&header))
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful, order ID: %s", requestInfo, resp.Order.Id)
+	if replayed := header.Get(idempotentReplayedMetadata); len(replayed) > 0 && replayed[0] == "true" {
+		slog.InfoContext(c.Request.Context(), "gRPC call replayed", "request", requestInfo, "order_id", resp.Order.Id)
+		c.Header(idempotentReplayedHeader, "true")
+	}
+
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "order_id", resp.Order.Id)
 	c.JSON(http.StatusCreated, resp.Order)
 }
 
@@ -70,8 +92,8 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 	requestInfo := fmt.Sprintf("GetOrderByID (ID: %s)", orderID)
 
 	if orderID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Order ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Order ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Order ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
@@ -80,14 +102,14 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s", requestInfo)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo)
 	resp, err := h.client.GetOrderByID(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
 	c.JSON(http.StatusOK, resp.Order)
 }
 
@@ -96,18 +118,19 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 	requestInfo := fmt.Sprintf("UpdateOrderStatus (ID: %s)", orderID)
 
 	if orderID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Order ID is missing", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Order ID is required"})
+		slog.InfoContext(c.Request.Context(), "Invalid input: Order ID is missing", "request", requestInfo)
+		problem.BadRequest(c, "Order ID is required", problem.FieldError{Field: "id", Message: "is required"})
 		return
 	}
 
 	var reqBody struct {
 		Status string `json:"status" binding:"required"`
+		Reason string `json:"reason"`
 	}
 
 	if err := c.ShouldBindJSON(&reqBody); err != nil {
-		log.Printf("API Gateway: Invalid input for %s: %v", requestInfo, err)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input: " + err.Error()})
+		slog.InfoContext(c.Request.Context(), "Invalid input", "request", requestInfo, "error", err)
+		problem.Abort(c, problem.FromBindingError(err))
 		return
 	}
 
@@ -116,82 +139,74 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 	if val, ok := orderpb.OrderStatus_value[statusStrUpper]; ok && orderpb.OrderStatus(val) != orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
 		grpcStatus = orderpb.OrderStatus(val)
 	} else {
-		log.Printf("API Gateway: Invalid status value for %s: '%s'", requestInfo, reqBody.Status)
+		slog.InfoContext(c.Request.Context(), "Invalid status value", "request", requestInfo, "status", reqBody.Status)
 		validStatuses := []string{}
 		for name, val := range orderpb.OrderStatus_value {
 			if orderpb.OrderStatus(val) != orderpb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
 				validStatuses = append(validStatuses, name) // Собираем валидные имена
 			}
 		}
-		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid status value: '%s'. Valid values (case-insensitive): %s", reqBody.Status, strings.Join(validStatuses, ", "))})
+		problem.BadRequest(c, fmt.Sprintf("Invalid status value: '%s'. Valid values (case-insensitive): %s", reqBody.Status, strings.Join(validStatuses, ", ")),
+			problem.FieldError{Field: "status", Message: "must be one of: " + strings.Join(validStatuses, ", ")})
 		return
 	}
 
 	grpcReq := &orderpb.UpdateOrderStatusRequest{
 		Id:     orderID,
 		Status: grpcStatus,
+		Reason: reqBody.Reason,
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s with status %s", requestInfo, grpcStatus)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "status", grpcStatus.String())
 	resp, err := h.client.UpdateOrderStatus(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful", requestInfo)
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo)
 	c.JSON(http.StatusOK, resp.Order)
 }
 
 func (h *OrderHandler) ListUserOrders(c *gin.Context) {
+	// Без user_id показываются заказы текущего пользователя, чужие доступны
+	// только support и order_admin
 	userID := c.Query("user_id")
-	requestInfo := fmt.Sprintf("ListUserOrders (User: %s)", userID)
-
 	if userID == "" {
-		log.Printf("API Gateway: Invalid input for %s: Missing 'user_id' query parameter", requestInfo)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing 'user_id' query parameter"})
-		return
+		userID = auth.UserID(c)
 	}
+	requestInfo := fmt.Sprintf("ListUserOrders (User: %s)", userID)
 
-	pageSizeStr := c.DefaultQuery("page_size", "10")
-	pageNumStr := c.DefaultQuery("page", "1")
-
-	pageSize, err1 := strconv.ParseInt(pageSizeStr, 10, 32)
-	pageNum, err2 := strconv.ParseInt(pageNumStr, 10, 32)
-
-	if err1 != nil || err2 != nil || pageSize <= 0 || pageNum <= 0 {
-		log.Printf("API Gateway: Invalid pagination parameters for %s: page_size=%s, page=%s", requestInfo, pageSizeStr, pageNumStr)
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid pagination parameters. 'page_size' and 'page' must be positive integers."})
+	paging, ok := bindListPaging(c, requestInfo)
+	if !ok {
 		return
 	}
-	if pageSize > 100 {
-		pageSize = 100
-	}
 
 	grpcReq := &orderpb.ListOrdersRequest{
-		UserId:     userID,
-		PageSize:   int32(pageSize),
-		PageNumber: int32(pageNum),
+		UserId:         userID,
+		PageSize:       int32(paging.PageSize),
+		PageToken:      paging.PageToken,
+		SkipTotalCount: !paging.IncludeTotal,
+	}
+	if paging.PageToken == "" {
+		grpcReq.PageNumber = int32(paging.Page)
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
-	log.Printf("API Gateway: Calling gRPC %s with params: %+v", requestInfo, grpcReq)
+	slog.DebugContext(c.Request.Context(), "Calling gRPC", "request", requestInfo, "params", grpcReq.String())
 	resp, err := h.client.ListUserOrders(ctx, grpcReq)
 	if err != nil {
 		mapGrpcToHttpError(c, err, requestInfo)
 		return
 	}
 
-	log.Printf("API Gateway: gRPC %s successful, found %d orders (total: %d)", requestInfo, len(resp.Orders), resp.TotalCount)
-	c.JSON(http.StatusOK, gin.H{
-		"data":      resp.Orders,
-		"total":     resp.TotalCount,
-		"page":      pageNum,
-		"page_size": pageSize,
-	})
+	slog.InfoContext(c.Request.Context(), "gRPC call successful", "request", requestInfo, "orders", len(resp.Orders), "total", resp.GetTotalCount())
+	c.JSON(http.StatusOK, paging.response(gin.H{
+		"data": resp.Orders,
+	}, resp.TotalCount, resp.NextPageToken))
 }
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"log/slog"
+	"strconv"
+
+	"ecommerce-microservices/api-gateway/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxPageSize caps page_size; larger values are lowered silently.
+const maxPageSize = 100
+
+// listPaging holds the pagination parameters of a list route:
+//
+//	page_size      items per page, 10 by default
+//	page           1-based page number
+//	page_token     next_page_token of the previous page; continues the list
+//	               after it instead of counting pages, so it cannot be
+//	               combined with page
+//	include_total  false to skip counting all items, which is slow on
+//	               large lists
+type listPaging struct {
+	PageSize     int64
+	Page         int64
+	PageToken    string
+	IncludeTotal bool
+}
+
+// bindListPaging reads the pagination parameters. If they are invalid it
+// aborts with a validation problem and returns false.
+func bindListPaging(c *gin.Context, requestInfo string) (listPaging, bool) {
+	pageSizeStr := c.DefaultQuery("page_size", "10")
+	pageNumStr := c.DefaultQuery("page", "1")
+
+	pageSize, err1 := strconv.ParseInt(pageSizeStr, 10, 32)
+	pageNum, err2 := strconv.ParseInt(pageNumStr, 10, 32)
+	if err1 != nil || err2 != nil || pageSize <= 0 || pageNum <= 0 {
+		slog.InfoContext(c.Request.Context(), "Invalid pagination parameters", "request", requestInfo, "page_size", pageSizeStr, "page", pageNumStr)
+		abortInvalidPagination(c, err1 == nil && pageSize > 0, err2 == nil && pageNum > 0)
+		return listPaging{}, false
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+
+	paging := listPaging{PageSize: pageSize, Page: pageNum, PageToken: c.Query("page_token"), IncludeTotal: true}
+	if _, ok := c.GetQuery("page"); ok && paging.PageToken != "" {
+		problem.BadRequest(c, "Parameters 'page' and 'page_token' cannot be combined",
+			problem.FieldError{Field: "page", Message: "cannot be combined with page_token"})
+		return listPaging{}, false
+	}
+	if value, ok := c.GetQuery("include_total"); ok {
+		includeTotal, err := strconv.ParseBool(value)
+		if err != nil {
+			problem.BadRequest(c, "Invalid pagination parameters",
+				problem.FieldError{Field: "include_total", Message: "must be true or false"})
+			return listPaging{}, false
+		}
+		paging.IncludeTotal = includeTotal
+	}
+	return paging, true
+}
+
+// response adds the pagination fields to the body of a list response. The
+// page number is left out for pages reached by token, and the total when it
+// was not counted.
+func (p listPaging) response(body gin.H, total *int64, nextPageToken string) gin.H {
+	body["page_size"] = p.PageSize
+	if p.PageToken == "" {
+		body["page"] = p.Page
+	}
+	if total != nil {
+		body["total"] = *total
+	}
+	if nextPageToken != "" {
+		body["next_page_token"] = nextPageToken
+	}
+	return body
+}
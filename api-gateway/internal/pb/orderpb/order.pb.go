@@ -22,15 +22,16 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// --- Сообщения ---
 type OrderStatus int32
 
 const (
-	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0 // Всегда должен быть 0 по умолчанию
+	OrderStatus_ORDER_STATUS_UNSPECIFIED OrderStatus = 0
 	OrderStatus_PENDING                  OrderStatus = 1
 	OrderStatus_COMPLETED                OrderStatus = 2
 	OrderStatus_CANCELLED                OrderStatus = 3
 	OrderStatus_FAILED                   OrderStatus = 4
+	OrderStatus_PAID                     OrderStatus = 5
+	OrderStatus_SHIPPED                  OrderStatus = 6
 )
 
 // Enum value maps for OrderStatus.
@@ -41,6 +42,8 @@ var (
 		2: "COMPLETED",
 		3: "CANCELLED",
 		4: "FAILED",
+		5: "PAID",
+		6: "SHIPPED",
 	}
 	OrderStatus_value = map[string]int32{
 		"ORDER_STATUS_UNSPECIFIED": 0,
@@ -48,6 +51,8 @@ var (
 		"COMPLETED":                2,
 		"CANCELLED":                3,
 		"FAILED":                   4,
+		"PAID":                     5,
+		"SHIPPED":                  6,
 	}
 )
 
@@ -80,9 +85,9 @@ func (OrderStatus) EnumDescriptor() ([]byte, []int) {
 
 type OrderItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`              // ID продукта
-	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`                                // Количество
-	PriceAtOrder  float64                `protobuf:"fixed64,3,opt,name=price_at_order,json=priceAtOrder,proto3" json:"price_at_order,omitempty"` // Цена на момент заказа
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
+	PriceAtOrder  float64                `protobuf:"fixed64,3,opt,name=price_at_order,json=priceAtOrder,proto3" json:"price_at_order,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -138,22 +143,99 @@ func (x *OrderItem) GetPriceAtOrder() float64 {
 	return 0
 }
 
+type StatusTransition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	From          OrderStatus            `protobuf:"varint,1,opt,name=from,proto3,enum=order.OrderStatus" json:"from,omitempty"`
+	To            OrderStatus            `protobuf:"varint,2,opt,name=to,proto3,enum=order.OrderStatus" json:"to,omitempty"`
+	Actor         string                 `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string                 `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
+	ChangedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=changed_at,json=changedAt,proto3" json:"changed_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StatusTransition) Reset() {
+	*x = StatusTransition{}
+	mi := &file_order_service_proto_order_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StatusTransition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StatusTransition) ProtoMessage() {}
+
+func (x *StatusTransition) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StatusTransition.ProtoReflect.Descriptor instead.
+func (*StatusTransition) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StatusTransition) GetFrom() OrderStatus {
+	if x != nil {
+		return x.From
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *StatusTransition) GetTo() OrderStatus {
+	if x != nil {
+		return x.To
+	}
+	return OrderStatus_ORDER_STATUS_UNSPECIFIED
+}
+
+func (x *StatusTransition) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *StatusTransition) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+func (x *StatusTransition) GetChangedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ChangedAt
+	}
+	return nil
+}
+
 type Order struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // ObjectID -> string
-	UserId      string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Items       []*OrderItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
-	TotalAmount float64                `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
-	Status      OrderStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
-	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Items         []*OrderItem           `protobuf:"bytes,3,rep,name=items,proto3" json:"items,omitempty"`
+	TotalAmount   float64                `protobuf:"fixed64,4,opt,name=total_amount,json=totalAmount,proto3" json:"total_amount,omitempty"`
+	Status        OrderStatus            `protobuf:"varint,5,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	History       []*StatusTransition    `protobuf:"bytes,8,rep,name=history,proto3" json:"history,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Order) Reset() {
 	*x = Order{}
-	mi := &file_order_service_proto_order_proto_msgTypes[1]
+	mi := &file_order_service_proto_order_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -165,7 +247,7 @@ func (x *Order) String() string {
 func (*Order) ProtoMessage() {}
 
 func (x *Order) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[1]
+	mi := &file_order_service_proto_order_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -178,7 +260,7 @@ func (x *Order) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Order.ProtoReflect.Descriptor instead.
 func (*Order) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{1}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *Order) GetId() string {
@@ -230,7 +312,13 @@ func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
-// --- Запросы/Ответы ---
+func (x *Order) GetHistory() []*StatusTransition {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
 type CreateOrderItemInput struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -241,7 +329,7 @@ type CreateOrderItemInput struct {
 
 func (x *CreateOrderItemInput) Reset() {
 	*x = CreateOrderItemInput{}
-	mi := &file_order_service_proto_order_proto_msgTypes[2]
+	mi := &file_order_service_proto_order_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -253,7 +341,7 @@ func (x *CreateOrderItemInput) String() string {
 func (*CreateOrderItemInput) ProtoMessage() {}
 
 func (x *CreateOrderItemInput) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[2]
+	mi := &file_order_service_proto_order_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -266,7 +354,7 @@ func (x *CreateOrderItemInput) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderItemInput.ProtoReflect.Descriptor instead.
 func (*CreateOrderItemInput) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{2}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *CreateOrderItemInput) GetProductId() string {
@@ -284,7 +372,8 @@ func (x *CreateOrderItemInput) GetQuantity() int32 {
 }
 
 type CreateOrderRequest struct {
-	state         protoimpl.MessageState  `protogen:"open.v1"`
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Необязателен: по умолчанию заказ создается для пользователя из подписанного identity-токена (x-identity-token)
 	UserId        string                  `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	Items         []*CreateOrderItemInput `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
@@ -293,7 +382,7 @@ type CreateOrderRequest struct {
 
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[3]
+	mi := &file_order_service_proto_order_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -305,7 +394,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[3]
+	mi := &file_order_service_proto_order_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -318,7 +407,7 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{3}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateOrderRequest) GetUserId() string {
@@ -344,7 +433,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[4]
+	mi := &file_order_service_proto_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -356,7 +445,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[4]
+	mi := &file_order_service_proto_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -369,7 +458,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{4}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOrderRequest) GetId() string {
@@ -380,16 +469,19 @@ func (x *GetOrderRequest) GetId() string {
 }
 
 type UpdateOrderStatusRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string      `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Status        OrderStatus `protobuf:"varint,2,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Id     string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Status OrderStatus            `protobuf:"varint,2,opt,name=status,proto3,enum=order.OrderStatus" json:"status,omitempty"`
+	// Устарело: actor берется из подписанного identity-токена (x-identity-token)
+	Actor         string `protobuf:"bytes,3,opt,name=actor,proto3" json:"actor,omitempty"`
+	Reason        string `protobuf:"bytes,4,opt,name=reason,proto3" json:"reason,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateOrderStatusRequest) Reset() {
 	*x = UpdateOrderStatusRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[5]
+	mi := &file_order_service_proto_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -401,7 +493,7 @@ func (x *UpdateOrderStatusRequest) String() string {
 func (*UpdateOrderStatusRequest) ProtoMessage() {}
 
 func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[5]
+	mi := &file_order_service_proto_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -414,7 +506,7 @@ func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
 func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{5}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *UpdateOrderStatusRequest) GetId() string {
@@ -431,19 +523,37 @@ func (x *UpdateOrderStatusRequest) GetStatus() OrderStatus {
 	return OrderStatus_ORDER_STATUS_UNSPECIFIED
 }
 
+func (x *UpdateOrderStatusRequest) GetActor() string {
+	if x != nil {
+		return x.Actor
+	}
+	return ""
+}
+
+func (x *UpdateOrderStatusRequest) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
 type ListOrdersRequest struct {
-	state  protoimpl.MessageState `protogen:"open.v1"`
-	UserId string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Фильтр по пользователю
-	// Пагинация
-	PageSize      int32 `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageNumber    int32 `protobuf:"varint,3,opt,name=page_number,json=pageNumber,proto3" json:"page_number,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Необязателен: по умолчанию заказы пользователя из подписанного identity-токена (x-identity-token)
+	UserId     string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PageSize   int32  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageNumber int32  `protobuf:"varint,3,opt,name=page_number,json=pageNumber,proto3" json:"page_number,omitempty"`
+	// next_page_token предыдущего ответа, page_number при этом не задается
+	PageToken string `protobuf:"bytes,4,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Не считать total_count: подсчет проходит по всем заказам пользователя
+	SkipTotalCount bool `protobuf:"varint,5,opt,name=skip_total_count,json=skipTotalCount,proto3" json:"skip_total_count,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ListOrdersRequest) Reset() {
 	*x = ListOrdersRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[6]
+	mi := &file_order_service_proto_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -455,7 +565,7 @@ func (x *ListOrdersRequest) String() string {
 func (*ListOrdersRequest) ProtoMessage() {}
 
 func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[6]
+	mi := &file_order_service_proto_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -468,7 +578,7 @@ func (x *ListOrdersRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersRequest.ProtoReflect.Descriptor instead.
 func (*ListOrdersRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{6}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *ListOrdersRequest) GetUserId() string {
@@ -492,6 +602,20 @@ func (x *ListOrdersRequest) GetPageNumber() int32 {
 	return 0
 }
 
+func (x *ListOrdersRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListOrdersRequest) GetSkipTotalCount() bool {
+	if x != nil {
+		return x.SkipTotalCount
+	}
+	return false
+}
+
 type OrderResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`
@@ -501,7 +625,7 @@ type OrderResponse struct {
 
 func (x *OrderResponse) Reset() {
 	*x = OrderResponse{}
-	mi := &file_order_service_proto_order_proto_msgTypes[7]
+	mi := &file_order_service_proto_order_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -513,7 +637,7 @@ func (x *OrderResponse) String() string {
 func (*OrderResponse) ProtoMessage() {}
 
 func (x *OrderResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[7]
+	mi := &file_order_service_proto_order_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -526,7 +650,7 @@ func (x *OrderResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use OrderResponse.ProtoReflect.Descriptor instead.
 func (*OrderResponse) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{7}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *OrderResponse) GetOrder() *Order {
@@ -536,17 +660,73 @@ func (x *OrderResponse) GetOrder() *Order {
 	return nil
 }
 
+type OrderEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Номер версии заказа: растет с каждым изменением статуса
+	Sequence      int64  `protobuf:"varint,1,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	Order         *Order `protobuf:"bytes,2,opt,name=order,proto3" json:"order,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *OrderEvent) Reset() {
+	*x = OrderEvent{}
+	mi := &file_order_service_proto_order_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *OrderEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*OrderEvent) ProtoMessage() {}
+
+func (x *OrderEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use OrderEvent.ProtoReflect.Descriptor instead.
+func (*OrderEvent) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *OrderEvent) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *OrderEvent) GetOrder() *Order {
+	if x != nil {
+		return x.Order
+	}
+	return nil
+}
+
 type ListOrdersResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Orders        []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
-	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Orders []*Order               `protobuf:"bytes,1,rep,name=orders,proto3" json:"orders,omitempty"`
+	// Не задан, если запрошен skip_total_count
+	TotalCount *int64 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3,oneof" json:"total_count,omitempty"`
+	// Пустой на последней странице
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *ListOrdersResponse) Reset() {
 	*x = ListOrdersResponse{}
-	mi := &file_order_service_proto_order_proto_msgTypes[8]
+	mi := &file_order_service_proto_order_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -558,7 +738,7 @@ func (x *ListOrdersResponse) String() string {
 func (*ListOrdersResponse) ProtoMessage() {}
 
 func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[8]
+	mi := &file_order_service_proto_order_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,7 +751,7 @@ func (x *ListOrdersResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListOrdersResponse.ProtoReflect.Descriptor instead.
 func (*ListOrdersResponse) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{8}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *ListOrdersResponse) GetOrders() []*Order {
@@ -582,12 +762,19 @@ func (x *ListOrdersResponse) GetOrders() []*Order {
 }
 
 func (x *ListOrdersResponse) GetTotalCount() int64 {
-	if x != nil {
-		return x.TotalCount
+	if x != nil && x.TotalCount != nil {
+		return *x.TotalCount
 	}
 	return 0
 }
 
+func (x *ListOrdersResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
 var File_order_service_proto_order_proto protoreflect.FileDescriptor
 
 const file_order_service_proto_order_proto_rawDesc = "" +
@@ -597,7 +784,14 @@ const file_order_service_proto_order_proto_rawDesc = "" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
 	"\bquantity\x18\x02 \x01(\x05R\bquantity\x12$\n" +
-	"\x0eprice_at_order\x18\x03 \x01(\x01R\fpriceAtOrder\"\x9d\x02\n" +
+	"\x0eprice_at_order\x18\x03 \x01(\x01R\fpriceAtOrder\"\xc7\x01\n" +
+	"\x10StatusTransition\x12&\n" +
+	"\x04from\x18\x01 \x01(\x0e2\x12.order.OrderStatusR\x04from\x12\"\n" +
+	"\x02to\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x02to\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\x129\n" +
+	"\n" +
+	"changed_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tchangedAt\"\xd0\x02\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12&\n" +
@@ -607,7 +801,8 @@ const file_order_service_proto_order_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"Q\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x121\n" +
+	"\ahistory\x18\b \x03(\v2\x17.order.StatusTransitionR\ahistory\"Q\n" +
 	"\x14CreateOrderItemInput\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
@@ -616,33 +811,48 @@ const file_order_service_proto_order_proto_rawDesc = "" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x121\n" +
 	"\x05items\x18\x02 \x03(\v2\x1b.order.CreateOrderItemInputR\x05items\"!\n" +
 	"\x0fGetOrderRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"V\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x84\x01\n" +
 	"\x18UpdateOrderStatusRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
-	"\x06status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x06status\"j\n" +
+	"\x06status\x18\x02 \x01(\x0e2\x12.order.OrderStatusR\x06status\x12\x14\n" +
+	"\x05actor\x18\x03 \x01(\tR\x05actor\x12\x16\n" +
+	"\x06reason\x18\x04 \x01(\tR\x06reason\"\xb3\x01\n" +
 	"\x11ListOrdersRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x1b\n" +
 	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1f\n" +
 	"\vpage_number\x18\x03 \x01(\x05R\n" +
-	"pageNumber\"3\n" +
+	"pageNumber\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x04 \x01(\tR\tpageToken\x12(\n" +
+	"\x10skip_total_count\x18\x05 \x01(\bR\x0eskipTotalCount\"3\n" +
 	"\rOrderResponse\x12\"\n" +
-	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"[\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\"L\n" +
+	"\n" +
+	"OrderEvent\x12\x1a\n" +
+	"\bsequence\x18\x01 \x01(\x03R\bsequence\x12\"\n" +
+	"\x05order\x18\x02 \x01(\v2\f.order.OrderR\x05order\"\x98\x01\n" +
 	"\x12ListOrdersResponse\x12$\n" +
-	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders\x12\x1f\n" +
-	"\vtotal_count\x18\x02 \x01(\x03R\n" +
-	"totalCount*b\n" +
+	"\x06orders\x18\x01 \x03(\v2\f.order.OrderR\x06orders\x12$\n" +
+	"\vtotal_count\x18\x02 \x01(\x03H\x00R\n" +
+	"totalCount\x88\x01\x01\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageTokenB\x0e\n" +
+	"\f_total_count*y\n" +
 	"\vOrderStatus\x12\x1c\n" +
 	"\x18ORDER_STATUS_UNSPECIFIED\x10\x00\x12\v\n" +
 	"\aPENDING\x10\x01\x12\r\n" +
 	"\tCOMPLETED\x10\x02\x12\r\n" +
 	"\tCANCELLED\x10\x03\x12\n" +
 	"\n" +
-	"\x06FAILED\x10\x042\x9f\x02\n" +
+	"\x06FAILED\x10\x04\x12\b\n" +
+	"\x04PAID\x10\x05\x12\v\n" +
+	"\aSHIPPED\x10\x062\xda\x02\n" +
 	"\fOrderService\x12>\n" +
 	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\x14.order.OrderResponse\x12<\n" +
 	"\fGetOrderByID\x12\x16.order.GetOrderRequest\x1a\x14.order.OrderResponse\x12J\n" +
 	"\x11UpdateOrderStatus\x12\x1f.order.UpdateOrderStatusRequest\x1a\x14.order.OrderResponse\x12E\n" +
-	"\x0eListUserOrders\x12\x18.order.ListOrdersRequest\x1a\x19.order.ListOrdersResponseB;Z9ecommerce-microservices/order-service/internal/pb;orderpbb\x06proto3"
+	"\x0eListUserOrders\x12\x18.order.ListOrdersRequest\x1a\x19.order.ListOrdersResponse\x129\n" +
+	"\n" +
+	"WatchOrder\x12\x16.order.GetOrderRequest\x1a\x11.order.OrderEvent0\x01B;Z9ecommerce-microservices/order-service/internal/pb;orderpbb\x06proto3"
 
 var (
 	file_order_service_proto_order_proto_rawDescOnce sync.Once
@@ -657,42 +867,51 @@ func file_order_service_proto_order_proto_rawDescGZIP() []byte {
 }
 
 var file_order_service_proto_order_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_order_service_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_order_service_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 11)
 var file_order_service_proto_order_proto_goTypes = []any{
 	(OrderStatus)(0),                 // 0: order.OrderStatus
 	(*OrderItem)(nil),                // 1: order.OrderItem
-	(*Order)(nil),                    // 2: order.Order
-	(*CreateOrderItemInput)(nil),     // 3: order.CreateOrderItemInput
-	(*CreateOrderRequest)(nil),       // 4: order.CreateOrderRequest
-	(*GetOrderRequest)(nil),          // 5: order.GetOrderRequest
-	(*UpdateOrderStatusRequest)(nil), // 6: order.UpdateOrderStatusRequest
-	(*ListOrdersRequest)(nil),        // 7: order.ListOrdersRequest
-	(*OrderResponse)(nil),            // 8: order.OrderResponse
-	(*ListOrdersResponse)(nil),       // 9: order.ListOrdersResponse
-	(*timestamppb.Timestamp)(nil),    // 10: google.protobuf.Timestamp
+	(*StatusTransition)(nil),         // 2: order.StatusTransition
+	(*Order)(nil),                    // 3: order.Order
+	(*CreateOrderItemInput)(nil),     // 4: order.CreateOrderItemInput
+	(*CreateOrderRequest)(nil),       // 5: order.CreateOrderRequest
+	(*GetOrderRequest)(nil),          // 6: order.GetOrderRequest
+	(*UpdateOrderStatusRequest)(nil), // 7: order.UpdateOrderStatusRequest
+	(*ListOrdersRequest)(nil),        // 8: order.ListOrdersRequest
+	(*OrderResponse)(nil),            // 9: order.OrderResponse
+	(*OrderEvent)(nil),               // 10: order.OrderEvent
+	(*ListOrdersResponse)(nil),       // 11: order.ListOrdersResponse
+	(*timestamppb.Timestamp)(nil),    // 12: google.protobuf.Timestamp
 }
 var file_order_service_proto_order_proto_depIdxs = []int32{
-	1,  // 0: order.Order.items:type_name -> order.OrderItem
-	0,  // 1: order.Order.status:type_name -> order.OrderStatus
-	10, // 2: order.Order.created_at:type_name -> google.protobuf.Timestamp
-	10, // 3: order.Order.updated_at:type_name -> google.protobuf.Timestamp
-	3,  // 4: order.CreateOrderRequest.items:type_name -> order.CreateOrderItemInput
-	0,  // 5: order.UpdateOrderStatusRequest.status:type_name -> order.OrderStatus
-	2,  // 6: order.OrderResponse.order:type_name -> order.Order
-	2,  // 7: order.ListOrdersResponse.orders:type_name -> order.Order
-	4,  // 8: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
-	5,  // 9: order.OrderService.GetOrderByID:input_type -> order.GetOrderRequest
-	6,  // 10: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
-	7,  // 11: order.OrderService.ListUserOrders:input_type -> order.ListOrdersRequest
-	8,  // 12: order.OrderService.CreateOrder:output_type -> order.OrderResponse
-	8,  // 13: order.OrderService.GetOrderByID:output_type -> order.OrderResponse
-	8,  // 14: order.OrderService.UpdateOrderStatus:output_type -> order.OrderResponse
-	9,  // 15: order.OrderService.ListUserOrders:output_type -> order.ListOrdersResponse
-	12, // [12:16] is the sub-list for method output_type
-	8,  // [8:12] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	0,  // 0: order.StatusTransition.from:type_name -> order.OrderStatus
+	0,  // 1: order.StatusTransition.to:type_name -> order.OrderStatus
+	12, // 2: order.StatusTransition.changed_at:type_name -> google.protobuf.Timestamp
+	1,  // 3: order.Order.items:type_name -> order.OrderItem
+	0,  // 4: order.Order.status:type_name -> order.OrderStatus
+	12, // 5: order.Order.created_at:type_name -> google.protobuf.Timestamp
+	12, // 6: order.Order.updated_at:type_name -> google.protobuf.Timestamp
+	2,  // 7: order.Order.history:type_name -> order.StatusTransition
+	4,  // 8: order.CreateOrderRequest.items:type_name -> order.CreateOrderItemInput
+	0,  // 9: order.UpdateOrderStatusRequest.status:type_name -> order.OrderStatus
+	3,  // 10: order.OrderResponse.order:type_name -> order.Order
+	3,  // 11: order.OrderEvent.order:type_name -> order.Order
+	3,  // 12: order.ListOrdersResponse.orders:type_name -> order.Order
+	5,  // 13: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	6,  // 14: order.OrderService.GetOrderByID:input_type -> order.GetOrderRequest
+	7,  // 15: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
+	8,  // 16: order.OrderService.ListUserOrders:input_type -> order.ListOrdersRequest
+	6,  // 17: order.OrderService.WatchOrder:input_type -> order.GetOrderRequest
+	9,  // 18: order.OrderService.CreateOrder:output_type -> order.OrderResponse
+	9,  // 19: order.OrderService.GetOrderByID:output_type -> order.OrderResponse
+	9,  // 20: order.OrderService.UpdateOrderStatus:output_type -> order.OrderResponse
+	11, // 21: order.OrderService.ListUserOrders:output_type -> order.ListOrdersResponse
+	10, // 22: order.OrderService.WatchOrder:output_type -> order.OrderEvent
+	18, // [18:23] is the sub-list for method output_type
+	13, // [13:18] is the sub-list for method input_type
+	13, // [13:13] is the sub-list for extension type_name
+	13, // [13:13] is the sub-list for extension extendee
+	0,  // [0:13] is the sub-list for field type_name
 }
 
 func init() { file_order_service_proto_order_proto_init() }
@@ -700,13 +919,14 @@ func file_order_service_proto_order_proto_init() {
 	if File_order_service_proto_order_proto != nil {
 		return
 	}
+	file_order_service_proto_order_proto_msgTypes[10].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_service_proto_order_proto_rawDesc), len(file_order_service_proto_order_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   9,
+			NumMessages:   11,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -0,0 +1,145 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Validation errors report the JSON names of fields rather than the Go
+// struct field names, so that clients can match them to their forms.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(jsonFieldName)
+	}
+}
+
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+// FromBindingError converts an error of c.ShouldBind* into a problem,
+// listing every invalid field instead of the raw validator message.
+func FromBindingError(err error) *Problem {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		p := New(http.StatusBadRequest, CodeValidationFailed, "The request has invalid fields")
+		for _, fe := range validationErrs {
+			p.Errors = append(p.Errors, FieldError{Field: fieldPath(fe), Message: validationMessage(fe)})
+		}
+		return p
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		p := New(http.StatusBadRequest, CodeValidationFailed, "The request has invalid fields")
+		p.Errors = []FieldError{{Field: jsonPath(typeErr.Field), Message: "must be " + jsonType(typeErr.Type.Kind())}}
+		return p
+	}
+
+	return New(http.StatusBadRequest, CodeMalformedBody, "The request body is not valid JSON")
+}
+
+// fieldPath drops the name of the request type from the namespace, e.g.
+// "createRequest.items[0].quantity" becomes "items[0].quantity". Anonymous
+// request structs have no type name; their namespace differs from the Go
+// struct namespace already in the first segment.
+func fieldPath(fe validator.FieldError) string {
+	root, path, found := strings.Cut(fe.Namespace(), ".")
+	if structRoot, _, _ := strings.Cut(fe.StructNamespace(), "."); found && root == structRoot {
+		return path
+	}
+	return fe.Namespace()
+}
+
+// jsonPath turns the dotted path of a JSON decoding error, e.g.
+// "items.0.quantity", into "items[0].quantity".
+func jsonPath(field string) string {
+	parts := strings.Split(field, ".")
+	var b strings.Builder
+	for i, part := range parts {
+		if _, err := strconv.Atoi(part); err == nil {
+			b.WriteString("[" + part + "]")
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(part)
+	}
+	return b.String()
+}
+
+// jsonType names the JSON type that decodes into kind.
+func jsonType(kind reflect.Kind) string {
+	switch kind {
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.String:
+		return "a string"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	case reflect.Map, reflect.Struct:
+		return "an object"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	}
+	return "a " + kind.String()
+}
+
+func validationMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "gt":
+		return "must be greater than " + fe.Param()
+	case "gte":
+		return "must be at least " + fe.Param()
+	case "lt":
+		return "must be less than " + fe.Param()
+	case "lte":
+		return "must be at most " + fe.Param()
+	case "min":
+		if unit := lengthUnit(fe.Kind()); unit != "" {
+			return fmt.Sprintf("must have at least %s %s", fe.Param(), unit)
+		}
+		return "must be at least " + fe.Param()
+	case "max":
+		if unit := lengthUnit(fe.Kind()); unit != "" {
+			return fmt.Sprintf("must have at most %s %s", fe.Param(), unit)
+		}
+		return "must be at most " + fe.Param()
+	case "oneof":
+		return "must be one of: " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	}
+	return fmt.Sprintf("failed the %q check", fe.Tag())
+}
+
+// lengthUnit names what min and max count for kinds that are checked by
+// length rather than value.
+func lengthUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package problem
+
+import (
+	"net/http"
+
+	"ecommerce-microservices/pkg/grpcerr"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// httpStatuses maps gRPC codes to HTTP statuses. Codes that are missing
+// become 500.
+var httpStatuses = map[codes.Code]int{
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.Aborted:            http.StatusConflict,
+	codes.FailedPrecondition: http.StatusConflict,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Unavailable:        http.StatusBadGateway,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+}
+
+// reasonStatuses overrides the status of specific error reasons. Stale
+// writes are made with an If-Match header, so they get 412 rather than 409.
+var reasonStatuses = map[string]int{
+	CodeVersionMismatch: http.StatusPreconditionFailed,
+}
+
+// FromGRPC converts an error returned by a backend service into a problem.
+// The code comes from the google.rpc.ErrorInfo reason and the field errors
+// from the google.rpc.BadRequest detail. Messages of internal errors are
+// not passed on; they are logged with the request ID instead.
+func FromGRPC(err error) *Problem {
+	st, ok := status.FromError(err)
+	if !ok {
+		return New(http.StatusBadGateway, CodeBadGateway, "Failed to communicate with downstream service")
+	}
+
+	httpStatus, ok := httpStatuses[st.Code()]
+	if !ok {
+		return New(http.StatusInternalServerError, CodeInternal, "Internal server error in downstream service")
+	}
+
+	code := grpcerr.Reason(err)
+	if status, ok := reasonStatuses[code]; ok {
+		httpStatus = status
+	}
+	switch {
+	case code != "":
+	case st.Code() == codes.InvalidArgument:
+		// Проверки запросов в сервисах дают тот же код, что и в gateway
+		code = CodeValidationFailed
+	default:
+		code = upperSnake(st.Code().String())
+	}
+	p := New(httpStatus, code, st.Message())
+	for _, v := range grpcerr.FieldViolations(err) {
+		p.Errors = append(p.Errors, FieldError{Field: v.Field, Message: v.Description})
+	}
+	return p
+}
@@ -0,0 +1,102 @@
+// Package problem writes error responses as RFC 7807 problem details
+// (application/problem+json).
+package problem
+
+import (
+	"net/http"
+	"strings"
+	"unicode"
+
+	"ecommerce-microservices/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContentType is the media type of problem responses.
+const ContentType = "application/problem+json"
+
+// typeBase prefixes the kebab-case code to build the problem type URI.
+const typeBase = "/problems/"
+
+// Codes of the problems raised by the gateway itself. Errors of the backend
+// services use the reason of their google.rpc.ErrorInfo detail, or the gRPC
+// code when there is none. CodeVersionMismatch is also the reason the
+// services use for stale writes.
+const (
+	CodeValidationFailed     = "VALIDATION_FAILED"
+	CodeMalformedBody        = "MALFORMED_BODY"
+	CodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+	CodePreconditionRequired = "PRECONDITION_REQUIRED"
+	CodeVersionMismatch      = "VERSION_MISMATCH"
+	CodeUnauthenticated      = "UNAUTHENTICATED"
+	CodePermissionDenied     = "PERMISSION_DENIED"
+	CodeRateLimited          = "RATE_LIMITED"
+	CodeRouteNotFound        = "ROUTE_NOT_FOUND"
+	CodeBadGateway           = "BAD_GATEWAY"
+	CodeInternal             = "INTERNAL"
+)
+
+// FieldError describes one invalid request field. Field uses the JSON
+// names of the request, e.g. "items[0].quantity".
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// Problem is an RFC 7807 problem details object, extended with the request
+// ID, a machine-readable code and the invalid fields.
+type Problem struct {
+	Type      string       `json:"type"`
+	Title     string       `json:"title"`
+	Status    int          `json:"status"`
+	Detail    string       `json:"detail,omitempty"`
+	Instance  string       `json:"instance,omitempty"`
+	RequestID string       `json:"request_id,omitempty"`
+	Code      string       `json:"code"`
+	Errors    []FieldError `json:"errors,omitempty"`
+}
+
+// New returns a problem with the given HTTP status, code and detail.
+func New(status int, code, detail string) *Problem {
+	return &Problem{
+		Type:   typeURI(code),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Abort sends p and stops the handler chain. The instance and the request
+// ID are taken from the request.
+func Abort(c *gin.Context, p *Problem) {
+	p.Instance = c.Request.URL.Path
+	p.RequestID = logging.RequestIDFromContext(c.Request.Context())
+	// Gin keeps a Content-Type that is already set
+	c.Header("Content-Type", ContentType)
+	c.AbortWithStatusJSON(p.Status, p)
+}
+
+// BadRequest aborts with a 400 validation problem.
+func BadRequest(c *gin.Context, detail string, fields ...FieldError) {
+	p := New(http.StatusBadRequest, CodeValidationFailed, detail)
+	p.Errors = fields
+	Abort(c, p)
+}
+
+// typeURI turns NOT_FOUND into /problems/not-found.
+func typeURI(code string) string {
+	return typeBase + strings.ToLower(strings.ReplaceAll(code, "_", "-"))
+}
+
+// upperSnake turns a gRPC code name such as "NotFound" into "NOT_FOUND".
+func upperSnake(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			b.WriteByte('_')
+		}
+		b.WriteRune(unicode.ToUpper(r))
+	}
+	return b.String()
+}
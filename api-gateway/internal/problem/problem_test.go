@@ -0,0 +1,237 @@
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"ecommerce-microservices/pkg/logging"
+
+	"github.com/gin-gonic/gin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAbort(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/orders?debug=1", nil)
+	c.Request = req.WithContext(logging.WithRequestID(req.Context(), "req-1"))
+
+	p := New(http.StatusBadRequest, CodeValidationFailed, "The request has invalid fields")
+	p.Errors = []FieldError{{Field: "items[0].quantity", Message: "must be greater than 0"}}
+	Abort(c, p)
+
+	if !c.IsAborted() {
+		t.Error("handler chain was not aborted")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusBadRequest)
+	}
+	if got := w.Header().Get("Content-Type"); got != ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, ContentType)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	want := map[string]any{
+		"type":       "/problems/validation-failed",
+		"title":      "Bad Request",
+		"status":     float64(http.StatusBadRequest),
+		"detail":     "The request has invalid fields",
+		"instance":   "/api/v1/orders",
+		"request_id": "req-1",
+		"code":       CodeValidationFailed,
+		"errors": []any{
+			map[string]any{"field": "items[0].quantity", "message": "must be greater than 0"},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("body = %v, want %v", got, want)
+	}
+}
+
+func TestAbortOmitsEmptyMembers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+	Abort(c, New(http.StatusUnauthorized, CodeUnauthenticated, ""))
+
+	var got map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("body is not JSON: %v", err)
+	}
+	for _, member := range []string{"detail", "request_id", "errors"} {
+		if _, ok := got[member]; ok {
+			t.Errorf("body has empty member %q", member)
+		}
+	}
+}
+
+func TestTypeURI(t *testing.T) {
+	tests := map[string]string{
+		CodeValidationFailed: "/problems/validation-failed",
+		"NOT_FOUND":          "/problems/not-found",
+		"INTERNAL":           "/problems/internal",
+	}
+	for code, want := range tests {
+		if got := typeURI(code); got != want {
+			t.Errorf("typeURI(%q) = %q, want %q", code, got, want)
+		}
+	}
+}
+
+func TestFromGRPC(t *testing.T) {
+	withDetails := func(st *status.Status, details ...*errdetails.ErrorInfo) error {
+		for _, d := range details {
+			st, _ = st.WithDetails(d)
+		}
+		return st.Err()
+	}
+	badRequest, _ := status.New(codes.InvalidArgument, "Invalid item data").WithDetails(&errdetails.BadRequest{
+		FieldViolations: []*errdetails.BadRequest_FieldViolation{
+			{Field: "items[0].quantity", Description: "Invalid item data"},
+		},
+	})
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantDetail string
+		wantErrors []FieldError
+	}{
+		{
+			name:       "reason is the code",
+			err:        withDetails(status.New(codes.NotFound, "order 1 not found"), &errdetails.ErrorInfo{Reason: "ORDER_NOT_FOUND"}),
+			wantStatus: http.StatusNotFound,
+			wantCode:   "ORDER_NOT_FOUND",
+			wantDetail: "order 1 not found",
+		},
+		{
+			name:       "gRPC code without a reason",
+			err:        status.Error(codes.FailedPrecondition, "Insufficient stock"),
+			wantStatus: http.StatusConflict,
+			wantCode:   "FAILED_PRECONDITION",
+			wantDetail: "Insufficient stock",
+		},
+		{
+			name:       "field violations",
+			err:        badRequest.Err(),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   CodeValidationFailed,
+			wantDetail: "Invalid item data",
+			wantErrors: []FieldError{{Field: "items[0].quantity", Message: "Invalid item data"}},
+		},
+		{
+			name:       "unavailable service",
+			err:        status.Error(codes.Unavailable, "connection refused"),
+			wantStatus: http.StatusBadGateway,
+			wantCode:   "UNAVAILABLE",
+			wantDetail: "connection refused",
+		},
+		{
+			// Сообщения внутренних ошибок не уходят клиенту
+			name:       "internal error",
+			err:        status.Error(codes.Internal, "failed to insert order: connection reset"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   CodeInternal,
+			wantDetail: "Internal server error in downstream service",
+		},
+		{
+			name:       "not a gRPC error",
+			err:        errors.New("dial tcp: connection refused"),
+			wantStatus: http.StatusBadGateway,
+			wantCode:   CodeBadGateway,
+			wantDetail: "Failed to communicate with downstream service",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := FromGRPC(tt.err)
+			if p.Status != tt.wantStatus || p.Code != tt.wantCode || p.Detail != tt.wantDetail {
+				t.Errorf("FromGRPC() = %d %s %q, want %d %s %q", p.Status, p.Code, p.Detail, tt.wantStatus, tt.wantCode, tt.wantDetail)
+			}
+			if p.Type != typeURI(p.Code) || p.Title != http.StatusText(p.Status) {
+				t.Errorf("FromGRPC() type = %q, title = %q", p.Type, p.Title)
+			}
+			if !reflect.DeepEqual(p.Errors, tt.wantErrors) {
+				t.Errorf("FromGRPC() errors = %v, want %v", p.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
+
+func TestFromBindingError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	type item struct {
+		ProductID string `json:"product_id" binding:"required"`
+		Quantity  int    `json:"quantity" binding:"gt=0"`
+	}
+	type createRequest struct {
+		Items []item `json:"items" binding:"required,min=1,dive"`
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantCode   string
+		wantErrors []FieldError
+	}{
+		{
+			name:     "invalid fields",
+			body:     `{"items": [{"quantity": 0}]}`,
+			wantCode: CodeValidationFailed,
+			wantErrors: []FieldError{
+				{Field: "items[0].product_id", Message: "is required"},
+				{Field: "items[0].quantity", Message: "must be greater than 0"},
+			},
+		},
+		{
+			name:       "wrong JSON type",
+			body:       `{"items": [{"product_id": "p1", "quantity": "two"}]}`,
+			wantCode:   CodeValidationFailed,
+			wantErrors: []FieldError{{Field: "items[0].quantity", Message: "must be an integer"}},
+		},
+		{
+			name:     "malformed JSON",
+			body:     `{"items": [`,
+			wantCode: CodeMalformedBody,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", "application/json")
+
+			var req createRequest
+			err := c.ShouldBindJSON(&req)
+			if err == nil {
+				t.Fatal("ShouldBindJSON() error = nil")
+			}
+			p := FromBindingError(err)
+			if p.Status != http.StatusBadRequest || p.Code != tt.wantCode {
+				t.Errorf("FromBindingError() = %d %s, want 400 %s", p.Status, p.Code, tt.wantCode)
+			}
+			if !reflect.DeepEqual(p.Errors, tt.wantErrors) {
+				t.Errorf("FromBindingError() errors = %v, want %v", p.Errors, tt.wantErrors)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Tiers every configuration must have. Clients with a configured API key
+// can be put into any other tier except TierIP.
+const (
+	TierAnonymous     = "anonymous"
+	TierAuthenticated = "authenticated"
+	// TierIP limits every request by IP address before authentication, so
+	// that requests with missing or invalid credentials are limited too. A
+	// configuration without it gets the default one.
+	TierIP = "ip"
+)
+
+// Limit allows Requests per Period on average with bursts of up to Burst
+// requests. A zero Burst means Requests.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+	Burst    int
+}
+
+func (l Limit) Capacity() int {
+	if l.Burst > 0 {
+		return l.Burst
+	}
+	return l.Requests
+}
+
+func (l Limit) RatePerSecond() float64 {
+	return float64(l.Requests) / l.Period.Seconds()
+}
+
+func (l Limit) validate() error {
+	if l.Requests <= 0 || l.Period <= 0 || l.Burst < 0 {
+		return fmt.Errorf("invalid limit %d/%s (burst %d)", l.Requests, l.Period, l.Burst)
+	}
+	return nil
+}
+
+func (l *Limit) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Requests int    `json:"requests"`
+		Period   string `json:"period"`
+		Burst    int    `json:"burst"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	period, err := time.ParseDuration(raw.Period)
+	if err != nil {
+		return fmt.Errorf("invalid period %q: %w", raw.Period, err)
+	}
+	*l = Limit{Requests: raw.Requests, Period: period, Burst: raw.Burst}
+	return nil
+}
+
+// TierConfig holds the limits of one tier. Routes are keyed like the access
+// policy ("POST /api/v1/orders") and have their own buckets; all other
+// routes share the Default bucket.
+type TierConfig struct {
+	Default Limit            `json:"default"`
+	Routes  map[string]Limit `json:"routes"`
+}
+
+func (t TierConfig) limitFor(route string) (Limit, string) {
+	if limit, ok := t.Routes[route]; ok {
+		return limit, route
+	}
+	return t.Default, "default"
+}
+
+type Config struct {
+	Tiers map[string]TierConfig `json:"tiers"`
+	// APIKeys maps an API key to the name of its tier.
+	APIKeys map[string]string `json:"api_keys"`
+}
+
+// DefaultConfig is used when no configuration file is given.
+func DefaultConfig() Config {
+	return Config{
+		Tiers: map[string]TierConfig{
+			// Общий предел на IP, выше пределов пользователей за NAT
+			TierIP: {
+				Default: Limit{Requests: 600, Period: time.Minute, Burst: 200},
+			},
+			TierAnonymous: {
+				Default: Limit{Requests: 60, Period: time.Minute, Burst: 30},
+			},
+			TierAuthenticated: {
+				Default: Limit{Requests: 120, Period: time.Minute, Burst: 60},
+				Routes: map[string]Limit{
+					"POST /api/v1/orders": {Requests: 10, Period: time.Minute, Burst: 5},
+				},
+			},
+		},
+	}
+}
+
+// LoadConfig reads the configuration from a JSON file.
+func LoadConfig(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to read rate limit config %s: %w", path, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to parse rate limit config %s: %w", path, err)
+	}
+	if _, ok := cfg.Tiers[TierIP]; !ok && cfg.Tiers != nil {
+		cfg.Tiers[TierIP] = DefaultConfig().Tiers[TierIP]
+	}
+	if err := cfg.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid rate limit config %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func (c Config) Validate() error {
+	for _, name := range []string{TierAnonymous, TierAuthenticated, TierIP} {
+		if _, ok := c.Tiers[name]; !ok {
+			return fmt.Errorf("tier %q is required", name)
+		}
+	}
+	for name, tier := range c.Tiers {
+		if err := tier.Default.validate(); err != nil {
+			return fmt.Errorf("tier %q default: %w", name, err)
+		}
+		for route, limit := range tier.Routes {
+			if err := limit.validate(); err != nil {
+				return fmt.Errorf("tier %q route %q: %w", name, route, err)
+			}
+		}
+	}
+	for _, tier := range c.APIKeys {
+		if _, ok := c.Tiers[tier]; !ok {
+			return fmt.Errorf("API key refers to unknown tier %q", tier)
+		}
+		if tier == TierIP {
+			return fmt.Errorf("API key cannot refer to tier %q", TierIP)
+		}
+	}
+	return nil
+}
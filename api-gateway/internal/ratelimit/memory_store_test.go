@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// fakeClock is a clock that only moves when the test advances it.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func newTestStore() (*MemoryStore, *fakeClock) {
+	clock := &fakeClock{now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)}
+	store := NewMemoryStore()
+	store.now = clock.Now
+	return store, clock
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	// Один токен в секунду, burst 3
+	limit := Limit{Requests: 10, Period: 10 * time.Second, Burst: 3}
+
+	type step struct {
+		advance    time.Duration
+		allowed    bool
+		remaining  int
+		retryAfter time.Duration
+		reset      time.Duration
+	}
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "burst is used up",
+			steps: []step{
+				{allowed: true, remaining: 2, reset: time.Second},
+				{allowed: true, remaining: 1, reset: 2 * time.Second},
+				{allowed: true, remaining: 0, reset: 3 * time.Second},
+				{allowed: false, remaining: 0, retryAfter: time.Second, reset: 3 * time.Second},
+			},
+		},
+		{
+			name: "tokens refill with time",
+			steps: []step{
+				{allowed: true, remaining: 2, reset: time.Second},
+				{allowed: true, remaining: 1, reset: 2 * time.Second},
+				{allowed: true, remaining: 0, reset: 3 * time.Second},
+				{advance: 500 * time.Millisecond, allowed: false, remaining: 0, retryAfter: 500 * time.Millisecond, reset: 2500 * time.Millisecond},
+				{advance: 500 * time.Millisecond, allowed: true, remaining: 0, reset: 3 * time.Second},
+				{advance: 2 * time.Second, allowed: true, remaining: 1, reset: 2 * time.Second},
+			},
+		},
+		{
+			name: "refill stops at capacity",
+			steps: []step{
+				{allowed: true, remaining: 2, reset: time.Second},
+				{advance: time.Hour, allowed: true, remaining: 2, reset: time.Second},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, clock := newTestStore()
+			for i, s := range tt.steps {
+				clock.Advance(s.advance)
+				got, err := store.Take(context.Background(), "client", limit)
+				if err != nil {
+					t.Fatalf("step %d: Take() error = %v", i, err)
+				}
+				want := Result{Allowed: s.allowed, Limit: 3, Remaining: s.remaining, Reset: s.reset, RetryAfter: s.retryAfter}
+				if got != want {
+					t.Errorf("step %d: Take() = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	store, _ := newTestStore()
+	limit := Limit{Requests: 1, Period: time.Minute}
+
+	for _, key := range []string{"a", "b"} {
+		got, err := store.Take(context.Background(), key, limit)
+		if err != nil {
+			t.Fatalf("Take(%q) error = %v", key, err)
+		}
+		if !got.Allowed {
+			t.Errorf("Take(%q) was rejected, want a fresh bucket per key", key)
+		}
+	}
+	if got, _ := store.Take(context.Background(), "a", limit); got.Allowed {
+		t.Error("second Take(\"a\") was allowed, want the bucket of a to be empty")
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	// Bucket пустеет за один запрос и наполняется за 2 минуты
+	limit := Limit{Requests: 1, Period: 2 * time.Minute}
+
+	tests := []struct {
+		name    string
+		advance time.Duration
+		kept    bool
+	}{
+		{"before the sweep interval", sweepInterval / 2, true},
+		{"not yet refilled", sweepInterval, true},
+		{"refilled", 2 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, clock := newTestStore()
+			// Первый запрос проводит sweep и запоминает его время
+			if _, err := store.Take(context.Background(), "idle", limit); err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+
+			clock.Advance(tt.advance)
+			if _, err := store.Take(context.Background(), "other", limit); err != nil {
+				t.Fatalf("Take() error = %v", err)
+			}
+			if _, kept := store.buckets["idle"]; kept != tt.kept {
+				t.Errorf("bucket kept = %v, want %v", kept, tt.kept)
+			}
+		})
+	}
+}
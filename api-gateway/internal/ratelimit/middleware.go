@@ -0,0 +1,98 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"ecommerce-microservices/api-gateway/internal/problem"
+	"ecommerce-microservices/pkg/identity"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader identifies clients with a configured API key.
+const APIKeyHeader = "X-API-Key"
+
+// storeTimeout bounds a store call, so a slow store does not stall requests.
+const storeTimeout = 100 * time.Millisecond
+
+// Middleware limits requests per client with token buckets. A client is
+// identified by a configured API key, else by the authenticated user, else by
+// its IP address. It must run after the auth middleware. If the store fails,
+// the request is let through.
+func Middleware(store Store, cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tierName, client := identifyClient(c, cfg)
+		if take(c, store, cfg, tierName, client) {
+			c.Next()
+		}
+	}
+}
+
+// IPMiddleware limits requests per IP address with the TierIP buckets. It
+// runs before the auth middleware, so that clients cannot avoid limits by
+// sending no or invalid credentials.
+func IPMiddleware(store Store, cfg Config) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if take(c, store, cfg, TierIP, "ip:"+c.ClientIP()) {
+			c.Next()
+		}
+	}
+}
+
+// take counts the request against the bucket of client in tierName and sets
+// the RateLimit headers. It aborts the request and returns false if the
+// bucket is empty.
+func take(c *gin.Context, store Store, cfg Config, tierName, client string) bool {
+	tier := cfg.Tiers[tierName]
+	route := c.Request.Method + " " + c.FullPath()
+	limit, bucket := tier.limitFor(route)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), storeTimeout)
+	result, err := store.Take(ctx, tierName+"|"+bucket+"|"+client, limit)
+	cancel()
+	if err != nil {
+		slog.ErrorContext(c.Request.Context(), "Rate limit store failed, allowing request", "route", route, "error", err)
+		return true
+	}
+
+	c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+	c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=%d;burst=%d", limit.Requests, ceilSeconds(limit.Period), limit.Capacity()))
+
+	if !result.Allowed {
+		slog.WarnContext(c.Request.Context(), "Rate limit exceeded", "client", client, "tier", tierName, "route", route)
+		c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+		problem.Abort(c, problem.New(http.StatusTooManyRequests, problem.CodeRateLimited, "Rate limit exceeded, retry later"))
+		return false
+	}
+	return true
+}
+
+// identifyClient returns the tier and the bucket key of the caller. API keys
+// that are not configured are ignored, otherwise a client could get a fresh
+// bucket for every request.
+func identifyClient(c *gin.Context, cfg Config) (tier string, client string) {
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		if tier, ok := cfg.APIKeys[key]; ok {
+			sum := sha256.Sum256([]byte(key))
+			return tier, "key:" + hex.EncodeToString(sum[:8])
+		}
+	}
+	if caller, ok := identity.FromContext(c.Request.Context()); ok {
+		return TierAuthenticated, "user:" + caller.UserID
+	}
+	return TierAnonymous, "ip:" + c.ClientIP()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
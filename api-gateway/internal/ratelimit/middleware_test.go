@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"ecommerce-microservices/api-gateway/internal/problem"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIPMiddlewareLimitsUnauthenticatedRequests(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := DefaultConfig()
+	cfg.Tiers[TierIP] = TierConfig{Default: Limit{Requests: 2, Period: time.Minute}}
+	store, _ := newTestStore()
+
+	router := gin.New()
+	router.GET("/api/v1/orders",
+		IPMiddleware(store, cfg),
+		// Как auth.Middleware с неверным токеном
+		func(c *gin.Context) {
+			problem.Abort(c, problem.New(http.StatusUnauthorized, problem.CodeUnauthenticated, "Invalid token"))
+		},
+	)
+
+	tests := []struct {
+		remoteAddr string
+		want       int
+	}{
+		{"192.0.2.1:1000", http.StatusUnauthorized},
+		{"192.0.2.1:1001", http.StatusUnauthorized},
+		{"192.0.2.1:1002", http.StatusTooManyRequests},
+		// У другого адреса свой bucket
+		{"192.0.2.2:1000", http.StatusUnauthorized},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/orders", nil)
+		req.RemoteAddr = tt.remoteAddr
+		req.Header.Set("Authorization", "Bearer invalid")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.want {
+			t.Errorf("request %d from %s: status = %d, want %d", i, tt.remoteAddr, w.Code, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigAddsIPTier(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ratelimit.json")
+	data := `{"tiers": {
+		"anonymous": {"default": {"requests": 10, "period": "1m"}},
+		"authenticated": {"default": {"requests": 20, "period": "1m"}}
+	}}`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got, want := cfg.Tiers[TierIP].Default, DefaultConfig().Tiers[TierIP].Default; got != want {
+		t.Errorf("ip tier = %+v, want default %+v", got, want)
+	}
+}
+
+func TestConfigValidateRejectsAPIKeyInIPTier(t *testing.T) {
+	cfg := DefaultConfig()
+	cfg.APIKeys = map[string]string{"secret": TierIP}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() error = nil, want error")
+	}
+}
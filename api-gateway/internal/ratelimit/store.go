@@ -0,0 +1,22 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result is the state of a bucket after a request was counted.
+type Result struct {
+	Allowed   bool
+	Limit     int           // емкость bucket
+	Remaining int           // сколько запросов можно сделать сразу
+	Reset     time.Duration // через сколько bucket снова будет полон
+	// RetryAfter is how long a rejected client has to wait for a token.
+	RetryAfter time.Duration
+}
+
+// Store keeps token buckets. Take must refill and take a token atomically,
+// so that several gateway instances can share a store such as Redis.
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
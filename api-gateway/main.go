@@ -3,56 +3,137 @@ package main
 import (
 	"context"
 	"errors"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"ecommerce-microservices/api-gateway/internal/auth"
 	"ecommerce-microservices/api-gateway/internal/client"
 	"ecommerce-microservices/api-gateway/internal/handlers"
+	"ecommerce-microservices/api-gateway/internal/metrics"
+	"ecommerce-microservices/api-gateway/internal/problem"
+	"ecommerce-microservices/api-gateway/internal/ratelimit"
+	"ecommerce-microservices/api-gateway/internal/requestlog"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/logging"
+	"ecommerce-microservices/pkg/tracing"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
-	log.Printf("Using fallback for env var %s: %s", key, fallback)
+	slog.Info("Using fallback for env var", "key", key, "value", fallback)
 	return fallback
 }
 
 func main() {
+	if _, err := logging.Setup(logging.Config{
+		Service: "api-gateway",
+		Format:  os.Getenv("LOG_FORMAT"),
+		Level:   os.Getenv("LOG_LEVEL"),
+	}); err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
+	}
+
 	// Адреса gRPC сервисов из переменных окружения
 	inventoryAddr := getEnv("INVENTORY_SERVICE_ADDR", "localhost:50051")
 	orderAddr := getEnv("ORDER_SERVICE_ADDR", "localhost:50052")
 	gatewayPort := getEnv("GATEWAY_PORT", "8080")
 
-	log.Println("API Gateway: Initializing gRPC clients...")
-	serviceClients, err := client.NewServiceClients(inventoryAddr, orderAddr)
+	authCfg := auth.Config{
+		HMACSecret: getEnv("JWT_HS256_SECRET", ""),
+		JWKSFile:   getEnv("JWT_JWKS_FILE", ""),
+		Issuer:     getEnv("JWT_ISSUER", ""),
+		Audience:   getEnv("JWT_AUDIENCE", ""),
+	}
+	tokenVerifier, err := auth.NewVerifier(authCfg)
+	if err != nil {
+		logging.Fatal("Failed to configure JWT authentication", "error", err)
+	}
+	// Проверенный пользователь передается сервисам в подписанном токене
+	identitySigner, err := identity.NewSigner(os.Getenv("INTERNAL_AUTH_SECRET"))
+	if err != nil {
+		logging.Fatal("Invalid INTERNAL_AUTH_SECRET", "error", err)
+	}
+
+	rateLimitCfg := ratelimit.DefaultConfig()
+	if path := getEnv("RATE_LIMIT_CONFIG", ""); path != "" {
+		rateLimitCfg, err = ratelimit.LoadConfig(path)
+		if err != nil {
+			logging.Fatal("Failed to load rate limit config", "error", err)
+		}
+	}
+
+	sampleRatio, err := strconv.ParseFloat(getEnv("TRACES_SAMPLE_RATIO", "1"), 64)
+	if err != nil || sampleRatio < 0 || sampleRatio > 1 {
+		logging.Fatal("Invalid TRACES_SAMPLE_RATIO, expected a number from 0 to 1")
+	}
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName:  "api-gateway",
+		Exporter:     getEnv("TRACES_EXPORTER", tracing.ExporterNone),
+		OTLPEndpoint: getEnv("TRACES_OTLP_ENDPOINT", ""),
+		FilePath:     getEnv("TRACES_FILE", "traces.json"),
+		SampleRatio:  sampleRatio,
+	})
+	if err != nil {
+		logging.Fatal("Failed to initialize tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
+
+	slog.Info("Initializing gRPC clients")
+	serviceClients, err := client.NewServiceClients(inventoryAddr, orderAddr, identitySigner)
 	if err != nil {
 		// Это фатально, Gateway не может работать без бэкендов
-		log.Fatalf("API Gateway: Failed to create gRPC service clients: %v", err)
+		logging.Fatal("Failed to create gRPC service clients", "error", err)
 	}
 	defer serviceClients.Close()
-	log.Println("API Gateway: gRPC clients initialized.")
+	slog.Info("gRPC clients initialized")
 
 	ginMode := getEnv("GIN_MODE", "release")
 	gin.SetMode(ginMode)
 
 	router := gin.New()
 
-	router.Use(gin.Logger())
-	router.Use(gin.Recovery())
+	// IP клиента для rate limiting берется из X-Forwarded-For только от доверенных прокси
+	var trustedProxies []string
+	if proxies := getEnv("TRUSTED_PROXIES", ""); proxies != "" {
+		trustedProxies = strings.Split(proxies, ",")
+	}
+	if err := router.SetTrustedProxies(trustedProxies); err != nil {
+		logging.Fatal("Invalid TRUSTED_PROXIES", "error", err)
+	}
+
+	router.Use(requestlog.Middleware())
+	router.Use(gin.CustomRecovery(func(c *gin.Context, _ any) {
+		problem.Abort(c, problem.New(http.StatusInternalServerError, problem.CodeInternal, "Internal server error"))
+	}))
+	router.Use(metrics.Middleware())
+	router.Use(otelgin.Middleware("api-gateway", otelgin.WithFilter(func(r *http.Request) bool {
+		return r.URL.Path != "/metrics" && r.URL.Path != "/health"
+	})))
 
 	router.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"*"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Idempotency-Key", "If-Match", "Last-Event-ID", ratelimit.APIKeyHeader, requestlog.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", "ETag", "Idempotent-Replayed", requestlog.RequestIDHeader, "RateLimit-Limit", "RateLimit-Remaining", "RateLimit-Reset", "RateLimit-Policy", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
@@ -65,60 +146,95 @@ func main() {
 		c.JSON(http.StatusOK, gin.H{"status": "API Gateway UP"})
 	})
 
-	// Группа роутов /api/v1
-	apiV1 := router.Group("/api/v1")
+	router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+	router.NoRoute(func(c *gin.Context) {
+		problem.Abort(c, problem.New(http.StatusNotFound, problem.CodeRouteNotFound, "No route matches "+c.Request.Method+" "+c.Request.URL.Path))
+	})
+
+	// Группа роутов /api/v1, доступ к каждому роуту описан в auth.RoutePolicy.
+	// Лимит по IP идет до auth, чтобы ограничивать и запросы с неверным
+	// токеном, а лимит по клиенту после auth, чтобы считать по пользователю
+	rateLimitStore := ratelimit.NewMemoryStore()
+	apiV1 := router.Group("/api/v1",
+		ratelimit.IPMiddleware(rateLimitStore, rateLimitCfg),
+		auth.Middleware(tokenVerifier, auth.RoutePolicy),
+		ratelimit.Middleware(rateLimitStore, rateLimitCfg),
+	)
 	{
 		products := apiV1.Group("/products")
 		{
-			log.Printf("API Gateway: Registering route POST /api/v1/products")
+			slog.Debug("Registering route", "route", "POST /api/v1/products")
 			products.POST("", invHandler.CreateProduct) // POST /api/v1/products
 
-			log.Printf("API Gateway: Registering route GET /api/v1/products/:id")
+			slog.Debug("Registering route", "route", "GET /api/v1/products/search")
+			products.GET("/search", invHandler.SearchProducts) // GET /api/v1/products/search?q=
+
+			slog.Debug("Registering route", "route", "GET /api/v1/products/facets")
+			products.GET("/facets", invHandler.GetProductFacets) // GET /api/v1/products/facets
+
+			slog.Debug("Registering route", "route", "GET /api/v1/products/:id")
 			products.GET("/:id", invHandler.GetProductByID) // GET /api/v1/products/{product_id}
 
-			log.Printf("API Gateway: Registering route PUT /api/v1/products/:id")
+			slog.Debug("Registering route", "route", "PUT /api/v1/products/:id")
 			products.PUT("/:id", invHandler.UpdateProduct) // PUT /api/v1/products/{product_id}
 
-			log.Printf("API Gateway: Registering route DELETE /api/v1/products/:id")
+			slog.Debug("Registering route", "route", "PATCH /api/v1/products/:id")
+			products.PATCH("/:id", invHandler.PatchProduct) // PATCH /api/v1/products/{product_id}
+
+			slog.Debug("Registering route", "route", "DELETE /api/v1/products/:id")
 			products.DELETE("/:id", invHandler.DeleteProduct) // DELETE /api/v1/products/{product_id}
 
-			log.Printf("API Gateway: Registering route GET /api/v1/products")
+			slog.Debug("Registering route", "route", "POST /api/v1/products/:id/restore")
+			products.POST("/:id/restore", invHandler.RestoreProduct) // POST /api/v1/products/{product_id}/restore
+
+			slog.Debug("Registering route", "route", "GET /api/v1/products")
 			products.GET("", invHandler.ListProducts) // GET /api/v1/products
 		}
 
 		//РОУТЫ ДЛЯ КАТЕГОРИЙ
 		categories := apiV1.Group("/categories")
 		{
-			log.Printf("API Gateway: Registering route POST /api/v1/categories")
+			slog.Debug("Registering route", "route", "POST /api/v1/categories")
 			categories.POST("", invHandler.CreateCategory) // POST /api/v1/categories
 
-			log.Printf("API Gateway: Registering route GET /api/v1/categories/:id")
+			slog.Debug("Registering route", "route", "GET /api/v1/categories/tree")
+			categories.GET("/tree", invHandler.GetCategoryTree) // GET /api/v1/categories/tree
+
+			slog.Debug("Registering route", "route", "GET /api/v1/categories/:id")
 			categories.GET("/:id", invHandler.GetCategoryByID) // GET /api/v1/categories/{category_id}
 
-			log.Printf("API Gateway: Registering route PUT /api/v1/categories/:id")
+			slog.Debug("Registering route", "route", "PUT /api/v1/categories/:id")
 			categories.PUT("/:id", invHandler.UpdateCategory) // PUT /api/v1/categories/{category_id}
 
-			log.Printf("API Gateway: Registering route DELETE /api/v1/categories/:id")
+			slog.Debug("Registering route", "route", "DELETE /api/v1/categories/:id")
 			categories.DELETE("/:id", invHandler.DeleteCategory) // DELETE /api/v1/categories/{category_id}
 
-			log.Printf("API Gateway: Registering route GET /api/v1/categories")
+			slog.Debug("Registering route", "route", "POST /api/v1/categories/:id/restore")
+			categories.POST("/:id/restore", invHandler.RestoreCategory) // POST /api/v1/categories/{category_id}/restore
+
+			slog.Debug("Registering route", "route", "GET /api/v1/categories")
 			categories.GET("", invHandler.ListCategories) // GET /api/v1/categories
 		}
 
 		// Роуты для Order
+		// Покупатель видит только свои заказы, user ID берется из токена
 		orders := apiV1.Group("/orders")
 		{
-			log.Printf("API Gateway: Registering route POST /api/v1/orders")
+			slog.Debug("Registering route", "route", "POST /api/v1/orders")
 			orders.POST("", ordHandler.CreateOrder) // POST /api/v1/orders
 
-			log.Printf("API Gateway: Registering route GET /api/v1/orders/:id")
+			slog.Debug("Registering route", "route", "GET /api/v1/orders/:id")
 			orders.GET("/:id", ordHandler.GetOrderByID) // GET /api/v1/orders/{order_id}
 
-			log.Printf("API Gateway: Registering route PATCH /api/v1/orders/:id")
+			slog.Debug("Registering route", "route", "GET /api/v1/orders/:id/events")
+			orders.GET("/:id/events", ordHandler.StreamOrderEvents) // GET /api/v1/orders/{order_id}/events (SSE)
+
+			slog.Debug("Registering route", "route", "PATCH /api/v1/orders/:id")
 			orders.PATCH("/:id", ordHandler.UpdateOrderStatus) // PATCH /api/v1/orders/{order_id} (для статуса)
 
-			log.Printf("API Gateway: Registering route GET /api/v1/orders")
-			orders.GET("", ordHandler.ListUserOrders) // GET /api/v1/orders?user_id=...
+			slog.Debug("Registering route", "route", "GET /api/v1/orders")
+			orders.GET("", ordHandler.ListUserOrders) // GET /api/v1/orders[?user_id=... для support и order_admin]
 		}
 	}
 
@@ -132,24 +248,24 @@ func main() {
 	}
 
 	go func() {
-		log.Printf("API Gateway: Starting HTTP server on %s", serverAddr)
+		slog.Info("Starting HTTP server", "addr", serverAddr)
 		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			log.Fatalf("API Gateway: ListenAndServe error: %s\n", err)
+			logging.Fatal("ListenAndServe error", "error", err)
 		}
-		log.Println("API Gateway: HTTP server stopped serving.")
+		slog.Info("HTTP server stopped serving")
 	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-quit
-	log.Printf("API Gateway: Received signal %v, shutting down server...", sig)
+	slog.Info("Received signal, shutting down server", "signal", sig.String())
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
-		log.Fatalf("API Gateway: Server forced to shutdown: %v", err)
+		logging.Fatal("Server forced to shutdown", "error", err)
 	}
 
-	log.Println("API Gateway: Server exiting")
+	slog.Info("Server exiting")
 }
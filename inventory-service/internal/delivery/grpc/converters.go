@@ -11,16 +11,22 @@ func ProductToProto(p *domain.Product) *pb.Product {
 	if p == nil {
 		return nil
 	}
-	return &pb.Product{
-		Id:          p.ID.Hex(),
-		Name:        p.Name,
-		Description: p.Description,
-		Price:       p.Price,
-		Stock:       int32(p.Stock),
-		CategoryId:  p.CategoryID,
-		CreatedAt:   timestamppb.New(p.CreatedAt),
-		UpdatedAt:   timestamppb.New(p.UpdatedAt),
+	product := &pb.Product{
+		Id:           p.ID.Hex(),
+		Name:         p.Name,
+		Description:  p.Description,
+		Price:        p.Price,
+		Stock:        int32(p.Stock),
+		CategoryId:   p.CategoryID,
+		CreatedAt:    timestamppb.New(p.CreatedAt),
+		UpdatedAt:    timestamppb.New(p.UpdatedAt),
+		Version:      p.Version,
+		StockVersion: p.StockVersion,
 	}
+	if p.DeletedAt != nil {
+		product.DeletedAt = timestamppb.New(*p.DeletedAt)
+	}
+	return product
 }
 
 func ProductsToProto(products []*domain.Product) []*pb.Product {
@@ -34,16 +40,109 @@ func ProductsToProto(products []*domain.Product) []*pb.Product {
 	return protoProducts
 }
 
+// SearchHitsToProto converts search hits, highlighting terms in the name
+// and description of each product.
+func SearchHitsToProto(hits []domain.ProductSearchHit, terms []string) []*pb.ProductSearchHit {
+	protoHits := make([]*pb.ProductSearchHit, len(hits))
+	for i, hit := range hits {
+		highlights := map[string]string{}
+		if name, ok := highlight(hit.Product.Name, terms); ok {
+			highlights[domain.ProductFieldName] = name
+		}
+		if description, ok := highlight(hit.Product.Description, terms); ok {
+			highlights[domain.ProductFieldDescription] = description
+		}
+		protoHits[i] = &pb.ProductSearchHit{
+			Product:    ProductToProto(hit.Product),
+			Score:      hit.Score,
+			Highlights: highlights,
+		}
+	}
+	return protoHits
+}
+
+func ProductFacetsToProto(f *domain.ProductFacets) *pb.GetProductFacetsResponse {
+	resp := &pb.GetProductFacetsResponse{
+		Categories:      make([]*pb.CategoryFacet, len(f.Categories)),
+		InStockCount:    f.InStock,
+		OutOfStockCount: f.OutOfStock,
+		PriceBuckets:    make([]*pb.PriceBucket, len(f.PriceBuckets)),
+	}
+	for i, c := range f.Categories {
+		resp.Categories[i] = &pb.CategoryFacet{CategoryId: c.CategoryID, Count: c.Count}
+	}
+	for i, b := range f.PriceBuckets {
+		resp.PriceBuckets[i] = &pb.PriceBucket{Min: b.Min, Max: b.Max, Count: b.Count}
+	}
+	return resp
+}
+
 func CategoryToProto(cat *domain.Category) *pb.Category {
 	if cat == nil {
 		return nil
 	}
-	return &pb.Category{
+	category := &pb.Category{
 		Id:        cat.ID.Hex(),
 		Name:      cat.Name,
 		CreatedAt: timestamppb.New(cat.CreatedAt),
 		UpdatedAt: timestamppb.New(cat.UpdatedAt),
+		Version:   cat.Version,
+		ParentId:  cat.ParentID,
+		Path:      cat.Path,
+		Slug:      cat.Slug,
+	}
+	if cat.DeletedAt != nil {
+		category.DeletedAt = timestamppb.New(*cat.DeletedAt)
 	}
+	return category
+}
+
+func BreadcrumbsToProto(path []*domain.Category) []*pb.Breadcrumb {
+	breadcrumbs := make([]*pb.Breadcrumb, len(path))
+	for i, c := range path {
+		breadcrumbs[i] = &pb.Breadcrumb{Id: c.ID.Hex(), Name: c.Name, Slug: c.Slug}
+	}
+	return breadcrumbs
+}
+
+// CategoryTreeToProto arranges categories into trees. With rootID set the
+// category rootID is the only root; otherwise the roots are the categories
+// without a parent among categories. Below maxDepth levels (0 for no limit)
+// children are left out.
+func CategoryTreeToProto(categories []*domain.Category, rootID string, maxDepth int) []*pb.CategoryTreeNode {
+	ids := make(map[string]bool, len(categories))
+	for _, c := range categories {
+		ids[c.ID.Hex()] = true
+	}
+	children := make(map[string][]*domain.Category)
+	var roots []*domain.Category
+	for _, c := range categories {
+		switch {
+		case rootID != "" && c.ID.Hex() == rootID:
+			roots = append(roots, c)
+		case rootID == "" && !ids[c.ParentID]:
+			roots = append(roots, c)
+		default:
+			children[c.ParentID] = append(children[c.ParentID], c)
+		}
+	}
+
+	var build func(c *domain.Category, depth int) *pb.CategoryTreeNode
+	build = func(c *domain.Category, depth int) *pb.CategoryTreeNode {
+		node := &pb.CategoryTreeNode{Category: CategoryToProto(c)}
+		if maxDepth > 0 && depth >= maxDepth {
+			return node
+		}
+		for _, child := range children[c.ID.Hex()] {
+			node.Children = append(node.Children, build(child, depth+1))
+		}
+		return node
+	}
+	nodes := make([]*pb.CategoryTreeNode, len(roots))
+	for i, root := range roots {
+		nodes[i] = build(root, 0)
+	}
+	return nodes
 }
 
 func CategoriesToProto(cats []*domain.Category) []*pb.Category {
@@ -56,3 +155,36 @@ func CategoriesToProto(cats []*domain.Category) []*pb.Category {
 	}
 	return protoCats
 }
+
+func ReservationStatusToProto(st domain.ReservationStatus) pb.ReservationStatus {
+	switch st {
+	case domain.ReservationReserved:
+		return pb.ReservationStatus_RESERVED
+	case domain.ReservationCommitted:
+		return pb.ReservationStatus_COMMITTED
+	case domain.ReservationReleased:
+		return pb.ReservationStatus_RELEASED
+	default:
+		return pb.ReservationStatus_RESERVATION_STATUS_UNSPECIFIED
+	}
+}
+
+func ReservationToProto(r *domain.Reservation) *pb.Reservation {
+	if r == nil {
+		return nil
+	}
+	items := make([]*pb.StockItem, len(r.Items))
+	for i, item := range r.Items {
+		items[i] = &pb.StockItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+		}
+	}
+	return &pb.Reservation{
+		Id:        r.ID.Hex(),
+		Items:     items,
+		Status:    ReservationStatusToProto(r.Status),
+		CreatedAt: timestamppb.New(r.CreatedAt),
+		UpdatedAt: timestamppb.New(r.UpdatedAt),
+	}
+}
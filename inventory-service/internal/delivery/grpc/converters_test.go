@@ -0,0 +1,96 @@
+package grpc
+
+import (
+	"strings"
+	"testing"
+
+	"ecommerce-microservices/inventory-service/internal/domain"
+	pb "ecommerce-microservices/inventory-service/pb"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testCategories builds categories from "name:parent" pairs, in order. A
+// parent is referenced by name and must come before its children.
+func testCategories(pairs ...string) ([]*domain.Category, map[string]string) {
+	ids := make(map[string]string)
+	categories := make([]*domain.Category, 0, len(pairs))
+	for _, pair := range pairs {
+		name, parent, _ := strings.Cut(pair, ":")
+		c := &domain.Category{ID: primitive.NewObjectID(), Name: name}
+		if parent != "" {
+			c.ParentID = ids[parent]
+			if c.ParentID == "" {
+				// Родитель не входит в выборку
+				c.ParentID = primitive.NewObjectID().Hex()
+			}
+		}
+		ids[name] = c.ID.Hex()
+		categories = append(categories, c)
+	}
+	return categories, ids
+}
+
+// treeString renders nodes as "a(b,c(d))".
+func treeString(nodes []*pb.CategoryTreeNode) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.Category.Name
+		if len(node.Children) > 0 {
+			parts[i] += "(" + treeString(node.Children) + ")"
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func TestCategoryTreeToProto(t *testing.T) {
+	categories, ids := testCategories(
+		"electronics:",
+		"phones:electronics",
+		"laptops:electronics",
+		"cases:phones",
+		"garden:",
+		"tools:missing",
+	)
+
+	tests := []struct {
+		name     string
+		rootID   string
+		maxDepth int
+		want     string
+	}{
+		{"whole forest", "", 0, "electronics(phones(cases),laptops),garden,tools"},
+		{"one level of children", "", 1, "electronics(phones,laptops),garden,tools"},
+		{"negative depth has no limit", "", -1, "electronics(phones(cases),laptops),garden,tools"},
+		{"subtree", ids["phones"], 0, "phones(cases)"},
+		{"subtree with depth", ids["electronics"], 1, "electronics(phones,laptops)"},
+		{"unknown root", primitive.NewObjectID().Hex(), 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := treeString(CategoryTreeToProto(categories, tt.rootID, tt.maxDepth)); got != tt.want {
+				t.Errorf("CategoryTreeToProto() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBreadcrumbsToProto(t *testing.T) {
+	path, ids := testCategories("electronics:", "phones:electronics")
+	path[0].Slug = "electronics"
+	path[1].Slug = "phones"
+
+	got := BreadcrumbsToProto(path)
+	if len(got) != len(path) {
+		t.Fatalf("got %d breadcrumbs, want %d", len(got), len(path))
+	}
+	for i, name := range []string{"electronics", "phones"} {
+		if got[i].Id != ids[name] || got[i].Name != name || got[i].Slug != name {
+			t.Errorf("breadcrumb %d = %v, want %s", i, got[i], name)
+		}
+	}
+	if got := BreadcrumbsToProto(nil); len(got) != 0 {
+		t.Errorf("BreadcrumbsToProto(nil) = %v, want empty", got)
+	}
+}
@@ -0,0 +1,23 @@
+package grpc
+
+import (
+	"ecommerce-microservices/inventory-service/internal/domain"
+	"ecommerce-microservices/pkg/grpcerr"
+
+	"google.golang.org/grpc/codes"
+)
+
+// errorTranslator maps the errors returned by the stores to gRPC statuses.
+var errorTranslator = grpcerr.NewTranslator("inventory-service",
+	grpcerr.Rule{Err: domain.ErrNotFound, Code: codes.NotFound, Reason: "NOT_FOUND"},
+	grpcerr.Rule{Err: domain.ErrInvalidID, Code: codes.InvalidArgument, Reason: "INVALID_ID"},
+	grpcerr.Rule{Err: domain.ErrConflict, Code: codes.AlreadyExists, Reason: "ALREADY_EXISTS"},
+	grpcerr.Rule{Err: domain.ErrVersionMismatch, Code: codes.Aborted, Reason: "VERSION_MISMATCH"},
+	grpcerr.Rule{Err: domain.ErrInsufficientStock, Code: codes.FailedPrecondition, Reason: "INSUFFICIENT_STOCK"},
+	grpcerr.Rule{Err: domain.ErrInvalidStatus, Code: codes.FailedPrecondition, Reason: "INVALID_RESERVATION_STATUS"},
+	grpcerr.Rule{Err: domain.ErrReservationReleased, Code: codes.FailedPrecondition, Reason: "RESERVATION_RELEASED"},
+	grpcerr.Rule{Err: domain.ErrCategoryCycle, Code: codes.InvalidArgument, Reason: "CATEGORY_CYCLE"},
+	grpcerr.Rule{Err: domain.ErrUnknownCategory, Code: codes.InvalidArgument, Reason: "UNKNOWN_CATEGORY"},
+	grpcerr.Rule{Err: domain.ErrCategoryNotEmpty, Code: codes.FailedPrecondition, Reason: "CATEGORY_NOT_EMPTY"},
+	grpcerr.Rule{Err: domain.ErrCategoryDeleted, Code: codes.FailedPrecondition, Reason: "CATEGORY_DELETED"},
+)
@@ -0,0 +1,81 @@
+package grpc
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+// minPrefixLength is the shortest term that also marks longer words
+// starting with it. Shorter terms only mark words equal to them.
+const minPrefixLength = 3
+
+// highlightTerms returns the lower-cased words of a search query that
+// should be highlighted. Excluded words (-word) are dropped and a plural
+// "s" is stripped, so that "shoes" also marks "shoe".
+func highlightTerms(query string) []string {
+	var terms []string
+	for _, field := range strings.Fields(query) {
+		if strings.HasPrefix(field, "-") {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(field, isNotWordRune) {
+			word = strings.ToLower(word)
+			if stem, ok := strings.CutSuffix(word, "s"); ok && len(stem) >= minPrefixLength && !strings.HasSuffix(stem, "s") {
+				word = stem
+			}
+			terms = append(terms, word)
+		}
+	}
+	return terms
+}
+
+// highlight HTML-escapes text and wraps the words starting with one of terms
+// in <em></em>. It reports whether anything was marked. MongoDB matches by
+// stem, so this is an approximation: a word it matched may stay unmarked.
+func highlight(text string, terms []string) (string, bool) {
+	var b strings.Builder
+	matched := false
+	rest := text
+	for rest != "" {
+		start := strings.IndexFunc(rest, isWordRune)
+		if start < 0 {
+			break
+		}
+		end := strings.IndexFunc(rest[start:], isNotWordRune)
+		if end < 0 {
+			end = len(rest)
+		} else {
+			end += start
+		}
+
+		b.WriteString(html.EscapeString(rest[:start]))
+		word := rest[start:end]
+		if matchesTerm(strings.ToLower(word), terms) {
+			b.WriteString("<em>" + html.EscapeString(word) + "</em>")
+			matched = true
+		} else {
+			b.WriteString(html.EscapeString(word))
+		}
+		rest = rest[end:]
+	}
+	b.WriteString(html.EscapeString(rest))
+	return b.String(), matched
+}
+
+func matchesTerm(word string, terms []string) bool {
+	for _, term := range terms {
+		if word == term || len(term) >= minPrefixLength && strings.HasPrefix(word, term) {
+			return true
+		}
+	}
+	return false
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func isNotWordRune(r rune) bool {
+	return !isWordRune(r)
+}
@@ -0,0 +1,97 @@
+package grpc
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHighlightTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"Running Shoes", []string{"running", "shoe"}},
+		{"shoes -leather", []string{"shoe"}},
+		{"glass", []string{"glass"}},
+		{"bus", []string{"bus"}},
+		{"usb-c cable", []string{"usb", "c", "cable"}},
+		{`"wireless mouse"`, []string{"wireless", "mouse"}},
+		{"  ", nil},
+		{"-only -excluded", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			if got := highlightTerms(tt.query); !slices.Equal(got, tt.want) {
+				t.Errorf("highlightTerms(%q) = %q, want %q", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		terms   []string
+		want    string
+		matched bool
+	}{
+		{
+			name:    "whole word",
+			text:    "Red shoe",
+			terms:   []string{"shoe"},
+			want:    "Red <em>shoe</em>",
+			matched: true,
+		},
+		{
+			name:    "prefix of a longer word",
+			text:    "Running shoes for trails",
+			terms:   []string{"run", "shoe"},
+			want:    "<em>Running</em> <em>shoes</em> for trails",
+			matched: true,
+		},
+		{
+			// "tv" короче minPrefixLength, поэтому "tvs" не отмечается
+			name:    "short term only matches whole words",
+			text:    "TV stand, tvs",
+			terms:   []string{"tv"},
+			want:    "<em>TV</em> stand, tvs",
+			matched: true,
+		},
+		{
+			name:  "no match",
+			text:  "Blue jacket",
+			terms: []string{"shoe"},
+			want:  "Blue jacket",
+		},
+		{
+			name:    "text is escaped",
+			text:    `<b>Tom & Jerry's</b> "mug"`,
+			terms:   []string{"mug"},
+			want:    `&lt;b&gt;Tom &amp; Jerry&#39;s&lt;/b&gt; &#34;<em>mug</em>&#34;`,
+			matched: true,
+		},
+		{
+			name:    "unicode words",
+			text:    "Кожаная куртка",
+			terms:   []string{"кож"},
+			want:    "<em>Кожаная</em> куртка",
+			matched: true,
+		},
+		{
+			name: "empty text",
+			text: "",
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, matched := highlight(tt.text, tt.terms)
+			if got != tt.want || matched != tt.matched {
+				t.Errorf("highlight(%q) = %q, %v, want %q, %v", tt.text, got, matched, tt.want, tt.matched)
+			}
+		})
+	}
+}
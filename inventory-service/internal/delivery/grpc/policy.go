@@ -0,0 +1,57 @@
+package grpc
+
+import (
+	"context"
+	pb "ecommerce-microservices/inventory-service/pb"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/rbac"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// MethodPolicy lists the roles allowed to call each InventoryService method.
+// The catalog is public for reading; stock reservations are only made by
+// other services.
+var MethodPolicy = rbac.Policy{
+	pb.InventoryService_GetProductByID_FullMethodName:   rbac.Public,
+	pb.InventoryService_ListProducts_FullMethodName:     rbac.Public,
+	pb.InventoryService_GetProductsByIDs_FullMethodName: rbac.Public,
+	pb.InventoryService_SearchProducts_FullMethodName:   rbac.Public,
+	pb.InventoryService_GetProductFacets_FullMethodName: rbac.Public,
+	pb.InventoryService_CreateProduct_FullMethodName:    rbac.AnyOf(rbac.CatalogAdmin),
+	pb.InventoryService_UpdateProduct_FullMethodName:    rbac.AnyOf(rbac.CatalogAdmin),
+	pb.InventoryService_DeleteProduct_FullMethodName:    rbac.AnyOf(rbac.CatalogAdmin),
+	pb.InventoryService_RestoreProduct_FullMethodName:   rbac.AnyOf(rbac.CatalogAdmin),
+
+	pb.InventoryService_GetCategoryByID_FullMethodName: rbac.Public,
+	pb.InventoryService_ListCategories_FullMethodName:  rbac.Public,
+	pb.InventoryService_GetCategoryTree_FullMethodName: rbac.Public,
+	pb.InventoryService_CreateCategory_FullMethodName:  rbac.AnyOf(rbac.CatalogAdmin),
+	pb.InventoryService_UpdateCategory_FullMethodName:  rbac.AnyOf(rbac.CatalogAdmin),
+	pb.InventoryService_DeleteCategory_FullMethodName:  rbac.AnyOf(rbac.CatalogAdmin),
+	pb.InventoryService_RestoreCategory_FullMethodName: rbac.AnyOf(rbac.CatalogAdmin),
+
+	pb.InventoryService_ReserveStock_FullMethodName:       rbac.AnyOf(rbac.Service),
+	pb.InventoryService_CommitReservation_FullMethodName:  rbac.AnyOf(rbac.Service),
+	pb.InventoryService_ReleaseReservation_FullMethodName: rbac.AnyOf(rbac.Service),
+}
+
+// trashReaders may see soft-deleted products and categories.
+var trashReaders = []rbac.Role{rbac.CatalogAdmin}
+
+// authorizeIncludeDeleted checks the include_deleted flag of the public read
+// methods, which the method policy cannot do.
+func authorizeIncludeDeleted(ctx context.Context, includeDeleted bool) error {
+	if !includeDeleted {
+		return nil
+	}
+	caller, ok := identity.FromContext(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "Deleted items require an authenticated caller")
+	}
+	if !rbac.HasAnyRole(caller, trashReaders...) {
+		return status.Errorf(codes.PermissionDenied, "Caller %s is not allowed to see deleted items", caller.UserID)
+	}
+	return nil
+}
@@ -5,34 +5,38 @@ import (
 	"ecommerce-microservices/inventory-service/internal/domain"
 	repo "ecommerce-microservices/inventory-service/internal/repository"
 	pb "ecommerce-microservices/inventory-service/pb"
+	"ecommerce-microservices/pkg/grpcerr"
+	"ecommerce-microservices/pkg/pagination"
 	"errors"
-	"go.mongodb.org/mongo-driver/bson"
-	"go.mongodb.org/mongo-driver/mongo"
+	"fmt"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"math"
+	"slices"
 	"strings"
-	// "log"
+	"time"
+	"unicode/utf8"
 )
 
 type InventoryServer struct {
 	pb.UnimplementedInventoryServiceServer
-	productStore  *repo.MongoProductStore
-	categoryStore *repo.MongoCategoryStore
+	productStore     *repo.MongoProductStore
+	categoryStore    *repo.MongoCategoryStore
+	reservationStore *repo.MongoReservationStore
 }
 
-func NewInventoryServer(ps *repo.MongoProductStore, cs *repo.MongoCategoryStore) *InventoryServer {
+func NewInventoryServer(ps *repo.MongoProductStore, cs *repo.MongoCategoryStore, rs *repo.MongoReservationStore) *InventoryServer {
 	return &InventoryServer{
-		productStore:  ps,
-		categoryStore: cs,
+		productStore:     ps,
+		categoryStore:    cs,
+		reservationStore: rs,
 	}
 }
 
 func (s *InventoryServer) CreateProduct(ctx context.Context, req *pb.CreateProductRequest) (*pb.ProductResponse, error) {
-	if req.Name == "" || req.Price <= 0 || req.CategoryId == "" {
-		return nil, status.Error(codes.InvalidArgument, "Name, positive price, and category ID are required")
-	}
-
 	product := &domain.Product{
 		Name:        req.Name,
 		Description: req.Description,
@@ -40,10 +44,16 @@ func (s *InventoryServer) CreateProduct(ctx context.Context, req *pb.CreateProdu
 		Stock:       int(req.Stock),
 		CategoryID:  req.CategoryId,
 	}
+	if err := validateProduct(product, domain.ProductUpdatableFields); err != nil {
+		return nil, err
+	}
+	if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+		return nil, err
+	}
 
 	err := s.productStore.Create(ctx, product)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "Failed to create product: %v", err)
+		return nil, errorTranslator.Error(err, "Failed to create product")
 	}
 
 	return &pb.ProductResponse{Product: ProductToProto(product)}, nil
@@ -51,18 +61,20 @@ func (s *InventoryServer) CreateProduct(ctx context.Context, req *pb.CreateProdu
 
 func (s *InventoryServer) GetProductByID(ctx context.Context, req *pb.GetProductRequest) (*pb.ProductResponse, error) {
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "Product ID is required")
+		return nil, grpcerr.InvalidArgumentf("id", "Product ID is required")
 	}
 
-	product, err := s.productStore.GetByID(ctx, req.Id)
+	if err := authorizeIncludeDeleted(ctx, req.IncludeDeleted); err != nil {
+		return nil, err
+	}
+
+	get := s.productStore.GetByID
+	if req.IncludeDeleted {
+		get = s.productStore.GetByIDIncludingDeleted
+	}
+	product, err := get(ctx, req.Id)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) || strings.Contains(err.Error(), "not found") {
-			return nil, status.Errorf(codes.NotFound, "Product with ID %s not found", req.Id)
-		}
-		if strings.Contains(err.Error(), "invalid id format") {
-			return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID format: %s", req.Id)
-		}
-		return nil, status.Errorf(codes.Internal, "Failed to get product: %v", err)
+		return nil, errorTranslator.Error(err, "Failed to get product")
 	}
 
 	return &pb.ProductResponse{Product: ProductToProto(product)}, nil
@@ -70,29 +82,40 @@ func (s *InventoryServer) GetProductByID(ctx context.Context, req *pb.GetProduct
 
 func (s *InventoryServer) UpdateProduct(ctx context.Context, req *pb.UpdateProductRequest) (*pb.ProductResponse, error) {
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "Product ID is required for update")
+		return nil, grpcerr.InvalidArgumentf("id", "Product ID is required for update")
+	}
+	if req.Version == nil {
+		return nil, grpcerr.InvalidArgumentf("version", "Product version is required for update")
+	}
+	fields, err := productUpdateFields(req.GetUpdateMask())
+	if err != nil {
+		return nil, err
 	}
-	if req.Name == "" || req.Price <= 0 || req.CategoryId == "" {
-		return nil, status.Error(codes.InvalidArgument, "Name, positive price, and category ID are required")
+	if req.StockVersion == nil && slices.Contains(fields, domain.ProductFieldStock) {
+		return nil, grpcerr.InvalidArgumentf("stock_version", "Stock version is required to update the stock")
 	}
 
 	product := &domain.Product{
-		Name:        req.Name,
-		Description: req.Description,
-		Price:       req.Price,
-		Stock:       int(req.Stock),
-		CategoryID:  req.CategoryId,
+		Name:         req.Name,
+		Description:  req.Description,
+		Price:        req.Price,
+		Stock:        int(req.Stock),
+		CategoryID:   req.CategoryId,
+		Version:      req.GetVersion(),
+		StockVersion: req.GetStockVersion(),
+	}
+	if err := validateProduct(product, fields); err != nil {
+		return nil, err
+	}
+	if slices.Contains(fields, domain.ProductFieldCategoryID) {
+		if err := s.checkCategory(ctx, product.CategoryID); err != nil {
+			return nil, err
+		}
 	}
 
-	err := s.productStore.Update(ctx, req.Id, product)
+	err = s.productStore.Update(ctx, req.Id, product, fields)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return nil, status.Errorf(codes.NotFound, "Product with ID %s not found for update", req.Id)
-		}
-		if strings.Contains(err.Error(), "invalid id format") {
-			return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID format: %s", req.Id)
-		}
-		return nil, status.Errorf(codes.Internal, "Failed to update product: %v", err)
+		return nil, errorTranslator.Error(err, "Failed to update product")
 	}
 
 	updatedProduct, err := s.productStore.GetByID(ctx, req.Id)
@@ -103,25 +126,60 @@ func (s *InventoryServer) UpdateProduct(ctx context.Context, req *pb.UpdateProdu
 	return &pb.ProductResponse{Product: ProductToProto(updatedProduct)}, nil
 }
 
+// checkCategory makes sure that the category a product is put into exists.
+// A category deleted concurrently is not noticed; its products then show
+// up with the reject or reassign modes of the next DeleteCategory.
+func (s *InventoryServer) checkCategory(ctx context.Context, id string) error {
+	_, err := s.categoryStore.Reference(ctx, id, domain.ProductFieldCategoryID)
+	if err != nil {
+		return errorTranslator.Error(err, "Failed to check category")
+	}
+	return nil
+}
+
+// DeleteProduct soft-deletes the product. It can be restored until the
+// purge job removes it.
 func (s *InventoryServer) DeleteProduct(ctx context.Context, req *pb.DeleteProductRequest) (*emptypb.Empty, error) {
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "Product ID is required")
+		return nil, grpcerr.InvalidArgumentf("id", "Product ID is required")
 	}
 
 	err := s.productStore.Delete(ctx, req.Id)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return nil, status.Errorf(codes.NotFound, "Product with ID %s not found for deletion", req.Id)
-		}
-		if strings.Contains(err.Error(), "invalid id format") {
-			return nil, status.Errorf(codes.InvalidArgument, "Invalid product ID format: %s", req.Id)
-		}
-		return nil, status.Errorf(codes.Internal, "Failed to delete product: %v", err)
+		return nil, errorTranslator.Error(err, "Failed to delete product")
 	}
 
 	return &emptypb.Empty{}, nil
 }
 
+// RestoreProduct brings back a soft-deleted product. Its category must
+// exist; a product deleted together with its category is restored with
+// RestoreCategory.
+func (s *InventoryServer) RestoreProduct(ctx context.Context, req *pb.RestoreProductRequest) (*pb.ProductResponse, error) {
+	if req.Id == "" {
+		return nil, grpcerr.InvalidArgumentf("id", "Product ID is required")
+	}
+
+	product, err := s.productStore.GetByIDIncludingDeleted(ctx, req.Id)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to get product")
+	}
+	_, err = s.categoryStore.GetByID(ctx, product.CategoryID)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidID) {
+		err = domain.NewError(domain.ErrCategoryDeleted, domain.ResourceCategory, product.CategoryID,
+			"category %s of product %s is deleted, restore the category first", product.CategoryID, req.Id)
+	}
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to check category")
+	}
+
+	product, err = s.productStore.Restore(ctx, req.Id)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to restore product")
+	}
+	return &pb.ProductResponse{Product: ProductToProto(product)}, nil
+}
+
 func (s *InventoryServer) ListProducts(ctx context.Context, req *pb.ListProductsRequest) (*pb.ListProductsResponse, error) {
 	limit := int64(req.PageSize)
 	if limit <= 0 {
@@ -135,71 +193,194 @@ func (s *InventoryServer) ListProducts(ctx context.Context, req *pb.ListProducts
 		offset = 0
 	}
 
-	filter := bson.M{}
-	if req.CategoryIdFilter != "" {
-		filter["category_id"] = req.CategoryIdFilter
+	filter, sort, err := productListQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeIncludeDeleted(ctx, req.IncludeDeleted); err != nil {
+		return nil, err
 	}
+	filter.IncludeDeleted = req.IncludeDeleted
 
-	products, total, err := s.productStore.List(ctx, filter, limit, offset)
+	fingerprint := pagination.Fingerprint(filter, sort, req.IncludeDescendants)
+	page, err := listPage(req.PageToken, req.PageNumber, fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	if req.IncludeDescendants {
+		if err := s.addDescendantCategories(ctx, &filter); err != nil {
+			return nil, err
+		}
+	}
+	page.Limit = limit
+	page.Offset = offset
+	page.CountTotal = !req.SkipTotalCount
+
+	products, next, total, err := s.productStore.List(ctx, filter, sort, page)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to list products: %v", err)
 	}
 
-	return &pb.ListProductsResponse{
-		Products:   ProductsToProto(products),
+	resp := &pb.ListProductsResponse{Products: ProductsToProto(products)}
+	if page.CountTotal {
+		resp.TotalCount = &total
+	}
+	if next != nil {
+		if resp.NextPageToken, err = pagination.EncodeToken(*next, fingerprint); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to list products: %v", err)
+		}
+	}
+	return resp, nil
+}
+
+// listPage starts the page of a list request. A page token continues the
+// list it was issued for and cannot be combined with a page number.
+func listPage(pageToken string, pageNumber int32, fingerprint string) (pagination.Page, error) {
+	if pageToken == "" {
+		return pagination.Page{}, nil
+	}
+	if pageNumber > 1 {
+		return pagination.Page{}, grpcerr.InvalidArgumentf("page_number", "Page number cannot be combined with a page token")
+	}
+	after, err := pagination.DecodeToken(pageToken, fingerprint)
+	if err != nil {
+		return pagination.Page{}, grpcerr.InvalidArgumentf("page_token", "Page token is invalid or was issued for different filters")
+	}
+	return pagination.Page{After: &after}, nil
+}
+
+// maxSearchQueryLength limits the length of a SearchProducts query.
+const maxSearchQueryLength = 200
+
+func (s *InventoryServer) SearchProducts(ctx context.Context, req *pb.SearchProductsRequest) (*pb.SearchProductsResponse, error) {
+	query := strings.TrimSpace(req.Query)
+	if query == "" {
+		return nil, grpcerr.InvalidArgumentf("query", "Search query is required")
+	}
+	if len(query) > maxSearchQueryLength {
+		return nil, grpcerr.InvalidArgumentf("query", "Search query must be at most %d characters", maxSearchQueryLength)
+	}
+
+	limit := int64(req.PageSize)
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	offset := int64(req.PageNumber-1) * limit
+	if offset < 0 {
+		offset = 0
+	}
+
+	hits, total, err := s.productStore.Search(ctx, query, limit, offset)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to search products")
+	}
+
+	return &pb.SearchProductsResponse{
+		Hits:       SearchHitsToProto(hits, highlightTerms(query)),
 		TotalCount: total,
 	}, nil
 }
 
+// maxProductsPerBatch limits the number of IDs in one GetProductsByIDs call.
+const maxProductsPerBatch = 100
+
+func (s *InventoryServer) GetProductsByIDs(ctx context.Context, req *pb.GetProductsByIDsRequest) (*pb.GetProductsByIDsResponse, error) {
+	if len(req.Ids) == 0 {
+		return nil, grpcerr.InvalidArgumentf("ids", "At least one product ID is required")
+	}
+	if len(req.Ids) > maxProductsPerBatch {
+		return nil, grpcerr.InvalidArgumentf("ids", "At most %d product IDs can be requested at once", maxProductsPerBatch)
+	}
+
+	products, missing, err := s.productStore.GetByIDs(ctx, req.Ids)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to get products: %v", err)
+	}
+
+	return &pb.GetProductsByIDsResponse{
+		Products:   ProductsToProto(products),
+		MissingIds: missing,
+	}, nil
+}
+
 func (s *InventoryServer) CreateCategory(ctx context.Context, req *pb.CreateCategoryRequest) (*pb.CategoryResponse, error) {
 	if req.Name == "" {
-		return nil, status.Error(codes.InvalidArgument, "Category name is required")
+		return nil, grpcerr.InvalidArgumentf("name", "Category name is required")
 	}
-	category := &domain.Category{Name: req.Name}
-	err := s.categoryStore.Create(ctx, category)
+	slug, err := categorySlug(req.Slug, req.Name)
 	if err != nil {
-		if strings.Contains(err.Error(), "already exists") {
-			return nil, status.Errorf(codes.AlreadyExists, "Category '%s' already exists", req.Name)
-		}
-		return nil, status.Errorf(codes.Internal, "Failed to create category: %v", err)
+		return nil, err
+	}
+	category := &domain.Category{Name: req.Name, Slug: slug, ParentID: req.ParentId}
+	err = s.categoryStore.Create(ctx, category)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to create category")
 	}
 	return &pb.CategoryResponse{Category: CategoryToProto(category)}, nil
 }
 
 func (s *InventoryServer) GetCategoryByID(ctx context.Context, req *pb.GetCategoryRequest) (*pb.CategoryResponse, error) {
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "Category ID is required")
+		return nil, grpcerr.InvalidArgumentf("id", "Category ID is required")
+	}
+	if err := authorizeIncludeDeleted(ctx, req.IncludeDeleted); err != nil {
+		return nil, err
+	}
+	get := s.categoryStore.GetByID
+	if req.IncludeDeleted {
+		get = s.categoryStore.GetByIDIncludingDeleted
 	}
-	category, err := s.categoryStore.GetByID(ctx, req.Id)
+	category, err := get(ctx, req.Id)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) || strings.Contains(err.Error(), "not found") {
-			return nil, status.Errorf(codes.NotFound, "Category with ID %s not found", req.Id)
-		}
-		if strings.Contains(err.Error(), "invalid id format") {
-			return nil, status.Errorf(codes.InvalidArgument, "Invalid category ID format: %s", req.Id)
-		}
-		return nil, status.Errorf(codes.Internal, "Failed to get category: %v", err)
+		return nil, errorTranslator.Error(err, "Failed to get category")
 	}
-	return &pb.CategoryResponse{Category: CategoryToProto(category)}, nil
+	ancestors, err := s.categoryStore.Ancestors(ctx, category)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to get category ancestors")
+	}
+	return &pb.CategoryResponse{
+		Category:    CategoryToProto(category),
+		Breadcrumbs: BreadcrumbsToProto(append(ancestors, category)),
+	}, nil
 }
 
 func (s *InventoryServer) UpdateCategory(ctx context.Context, req *pb.UpdateCategoryRequest) (*pb.CategoryResponse, error) {
-	if req.Id == "" || req.Name == "" {
-		return nil, status.Error(codes.InvalidArgument, "Category ID and name are required for update")
+	var violations []grpcerr.FieldViolation
+	if req.Id == "" {
+		violations = append(violations, grpcerr.FieldViolation{Field: "id", Description: "Category ID is required"})
 	}
-	category := &domain.Category{Name: req.Name}
-	err := s.categoryStore.Update(ctx, req.Id, category)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return nil, status.Errorf(codes.NotFound, "Category with ID %s not found for update", req.Id)
-		}
-		if strings.Contains(err.Error(), "invalid id format") {
-			return nil, status.Errorf(codes.InvalidArgument, "Invalid category ID format: %s", req.Id)
+	if req.Name == "" {
+		violations = append(violations, grpcerr.FieldViolation{Field: "name", Description: "Category name is required"})
+	}
+	if req.Version == nil {
+		violations = append(violations, grpcerr.FieldViolation{Field: "version", Description: "Category version is required"})
+	}
+	if len(violations) > 0 {
+		return nil, grpcerr.InvalidArgument("Category ID, name and version are required for update", violations...)
+	}
+	category := &domain.Category{Name: req.Name, Version: req.GetVersion()}
+	fields := []string{domain.CategoryFieldName}
+	if req.Slug != nil {
+		slug, err := categorySlug(req.GetSlug(), "")
+		if err != nil {
+			return nil, err
 		}
-		if strings.Contains(err.Error(), "already exists") {
-			return nil, status.Errorf(codes.AlreadyExists, "Category name '%s' already exists", req.Name)
+		category.Slug = slug
+		fields = append(fields, domain.CategoryFieldSlug)
+	}
+	if req.ParentId != nil {
+		if req.GetParentId() == req.Id {
+			return nil, grpcerr.InvalidArgumentf("parent_id", "Category cannot be its own parent")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to update category: %v", err)
+		category.ParentID = req.GetParentId()
+		fields = append(fields, domain.CategoryFieldParentID)
+	}
+	err := s.categoryStore.Update(ctx, req.Id, category, fields)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to update category")
 	}
 	updatedCategory, err := s.categoryStore.GetByID(ctx, req.Id)
 	if err != nil {
@@ -210,25 +391,392 @@ func (s *InventoryServer) UpdateCategory(ctx context.Context, req *pb.UpdateCate
 
 func (s *InventoryServer) DeleteCategory(ctx context.Context, req *pb.DeleteCategoryRequest) (*emptypb.Empty, error) {
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "Category ID is required")
+		return nil, grpcerr.InvalidArgumentf("id", "Category ID is required")
 	}
-	err := s.categoryStore.Delete(ctx, req.Id)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			return nil, status.Errorf(codes.NotFound, "Category with ID %s not found for deletion", req.Id)
-		}
-		if strings.Contains(err.Error(), "invalid id format") {
-			return nil, status.Errorf(codes.InvalidArgument, "Invalid category ID format: %s", req.Id)
+	var mode domain.CategoryDeleteMode
+	switch req.Mode {
+	case pb.DeleteCategoryMode_DELETE_CATEGORY_MODE_UNSPECIFIED, pb.DeleteCategoryMode_DELETE_CATEGORY_MODE_REJECT:
+		mode = domain.CategoryDeleteReject
+	case pb.DeleteCategoryMode_DELETE_CATEGORY_MODE_REASSIGN:
+		if req.ReassignToCategoryId == "" {
+			return nil, grpcerr.InvalidArgumentf("reassign_to_category_id", "Target category is required to reassign products")
 		}
-		return nil, status.Errorf(codes.Internal, "Failed to delete category: %v", err)
+		mode = domain.CategoryDeleteReassign
+	case pb.DeleteCategoryMode_DELETE_CATEGORY_MODE_CASCADE:
+		mode = domain.CategoryDeleteCascade
+	default:
+		return nil, grpcerr.InvalidArgumentf("mode", "Unknown delete mode %d", req.Mode)
+	}
+	if mode != domain.CategoryDeleteReassign && req.ReassignToCategoryId != "" {
+		return nil, grpcerr.InvalidArgumentf("reassign_to_category_id", "Target category can only be set to reassign products")
+	}
+
+	err := s.categoryStore.Delete(ctx, req.Id, mode, req.ReassignToCategoryId)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to delete category")
 	}
 	return &emptypb.Empty{}, nil
 }
 
+// GetCategoryTree returns the category tree below req.RootId, or the whole
+// forest of root categories. Categories whose parent no longer exists are
+// returned as roots.
+func (s *InventoryServer) GetCategoryTree(ctx context.Context, req *pb.GetCategoryTreeRequest) (*pb.GetCategoryTreeResponse, error) {
+	if req.MaxDepth < 0 {
+		return nil, grpcerr.InvalidArgumentf("max_depth", "Maximum depth must not be negative")
+	}
+	categories, err := s.categoryStore.Subtree(ctx, req.RootId)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to get category tree")
+	}
+	return &pb.GetCategoryTreeResponse{Roots: CategoryTreeToProto(categories, req.RootId, int(req.MaxDepth))}, nil
+}
+
+// RestoreCategory brings back a soft-deleted category together with the
+// subcategories and products deleted with it.
+func (s *InventoryServer) RestoreCategory(ctx context.Context, req *pb.RestoreCategoryRequest) (*pb.CategoryResponse, error) {
+	if req.Id == "" {
+		return nil, grpcerr.InvalidArgumentf("id", "Category ID is required")
+	}
+	category, err := s.categoryStore.Restore(ctx, req.Id)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to restore category")
+	}
+	return &pb.CategoryResponse{Category: CategoryToProto(category)}, nil
+}
+
 func (s *InventoryServer) ListCategories(ctx context.Context, req *pb.ListCategoriesRequest) (*pb.ListCategoriesResponse, error) {
-	categories, err := s.categoryStore.List(ctx)
+	if err := authorizeIncludeDeleted(ctx, req.IncludeDeleted); err != nil {
+		return nil, err
+	}
+	categories, err := s.categoryStore.List(ctx, req.IncludeDeleted)
 	if err != nil {
 		return nil, status.Errorf(codes.Internal, "Failed to list categories: %v", err)
 	}
 	return &pb.ListCategoriesResponse{Categories: CategoriesToProto(categories)}, nil
 }
+
+func (s *InventoryServer) ReserveStock(ctx context.Context, req *pb.ReserveStockRequest) (*pb.ReservationResponse, error) {
+	if len(req.Items) == 0 {
+		return nil, grpcerr.InvalidArgumentf("items", "At least one item is required")
+	}
+
+	items := make([]domain.StockItem, 0, len(req.Items))
+	productIDs := make(map[string]bool)
+	for i, item := range req.Items {
+		if item.ProductId == "" {
+			return nil, grpcerr.InvalidArgumentf(fmt.Sprintf("items[%d].product_id", i), "Invalid item data: ProductID '%s', Quantity %d", item.ProductId, item.Quantity)
+		}
+		if item.Quantity <= 0 {
+			return nil, grpcerr.InvalidArgumentf(fmt.Sprintf("items[%d].quantity", i), "Invalid item data: ProductID '%s', Quantity %d", item.ProductId, item.Quantity)
+		}
+		if productIDs[item.ProductId] {
+			return nil, grpcerr.InvalidArgumentf(fmt.Sprintf("items[%d].product_id", i), "Duplicate product ID in reservation: %s", item.ProductId)
+		}
+		productIDs[item.ProductId] = true
+		items = append(items, domain.StockItem{ProductID: item.ProductId, Quantity: int(item.Quantity)})
+	}
+
+	reservation := &domain.Reservation{
+		Items:  items,
+		Status: domain.ReservationReserved,
+	}
+	// Сток и резерв записываются в одной транзакции: без резерва сток не списывается
+	err := s.reservationStore.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.productStore.ReserveStock(ctx, items); err != nil {
+			return err
+		}
+		return s.reservationStore.Create(ctx, reservation)
+	})
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to reserve stock")
+	}
+
+	return &pb.ReservationResponse{Reservation: ReservationToProto(reservation)}, nil
+}
+
+func (s *InventoryServer) CommitReservation(ctx context.Context, req *pb.CommitReservationRequest) (*pb.ReservationResponse, error) {
+	if req.ReservationId == "" {
+		return nil, grpcerr.InvalidArgumentf("reservation_id", "Reservation ID is required")
+	}
+
+	err := s.reservationStore.TransitionStatus(ctx, req.ReservationId, domain.ReservationCommitted, domain.ReservationReserved)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to update reservation")
+	}
+
+	reservation, err := s.reservationStore.GetByID(ctx, req.ReservationId)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to retrieve committed reservation: %v", err)
+	}
+	return &pb.ReservationResponse{Reservation: ReservationToProto(reservation)}, nil
+}
+
+func (s *InventoryServer) ReleaseReservation(ctx context.Context, req *pb.ReleaseReservationRequest) (*pb.ReservationResponse, error) {
+	if req.ReservationId == "" {
+		return nil, grpcerr.InvalidArgumentf("reservation_id", "Reservation ID is required")
+	}
+
+	// Статус и сток меняются в одной транзакции, поэтому сток возвращается
+	// ровно один раз. Подтвержденный резерв тоже можно вернуть, например при
+	// отмене заказа.
+	var reservation *domain.Reservation
+	err := s.reservationStore.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.reservationStore.TransitionStatus(ctx, req.ReservationId, domain.ReservationReleased, domain.ReservationReserved, domain.ReservationCommitted)
+		if err != nil {
+			return err
+		}
+		reservation, err = s.reservationStore.GetByID(ctx, req.ReservationId)
+		if err != nil {
+			return err
+		}
+		return s.productStore.ReleaseStock(ctx, reservation.Items)
+	})
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to release reservation")
+	}
+
+	return &pb.ReservationResponse{Reservation: ReservationToProto(reservation)}, nil
+}
+
+// maxListCategories limits the number of categories in one ListProducts
+// filter.
+const maxListCategories = 50
+
+// maxNamePrefixLength limits the name prefix of a ListProducts filter.
+const maxNamePrefixLength = 100
+
+// productSortFields maps the sort fields of the API to product fields.
+var productSortFields = map[pb.ProductSortField]string{
+	pb.ProductSortField_PRODUCT_SORT_FIELD_UNSPECIFIED: domain.ProductFieldCreatedAt,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_CREATED_AT:  domain.ProductFieldCreatedAt,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_UPDATED_AT:  domain.ProductFieldUpdatedAt,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_PRICE:       domain.ProductFieldPrice,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_NAME:        domain.ProductFieldName,
+	pb.ProductSortField_PRODUCT_SORT_FIELD_STOCK:       domain.ProductFieldStock,
+}
+
+// productListQuery validates the filter and sort order of a ListProducts
+// request, reporting every invalid field at once.
+func productListQuery(req *pb.ListProductsRequest) (domain.ProductFilter, domain.ProductSort, error) {
+	categoryIDs := req.CategoryIds
+	if req.CategoryIdFilter != "" && !slices.Contains(categoryIDs, req.CategoryIdFilter) {
+		categoryIDs = append(slices.Clip(categoryIDs), req.CategoryIdFilter)
+	}
+	filter, violations := productFilter(&pb.ProductFilter{
+		CategoryIds:   categoryIDs,
+		MinPrice:      req.MinPrice,
+		MaxPrice:      req.MaxPrice,
+		InStockOnly:   req.InStockOnly,
+		NamePrefix:    req.NamePrefix,
+		CreatedAfter:  req.CreatedAfter,
+		CreatedBefore: req.CreatedBefore,
+		UpdatedAfter:  req.UpdatedAfter,
+		UpdatedBefore: req.UpdatedBefore,
+	}, "")
+	invalid := func(field, description string) {
+		violations = append(violations, grpcerr.FieldViolation{Field: field, Description: description})
+	}
+
+	sort := domain.ProductSort{}
+	field, ok := productSortFields[req.SortBy]
+	if !ok {
+		invalid("sort_by", fmt.Sprintf("Unknown sort field %d", req.SortBy))
+	}
+	sort.Field = field
+	switch req.SortOrder {
+	case pb.SortOrder_SORT_ORDER_UNSPECIFIED:
+		// Новые и недавно измененные товары - первыми
+		sort.Descending = field == domain.ProductFieldCreatedAt || field == domain.ProductFieldUpdatedAt
+	case pb.SortOrder_SORT_ORDER_ASC:
+	case pb.SortOrder_SORT_ORDER_DESC:
+		sort.Descending = true
+	default:
+		invalid("sort_order", fmt.Sprintf("Unknown sort order %d", req.SortOrder))
+	}
+
+	if len(violations) > 0 {
+		return domain.ProductFilter{}, domain.ProductSort{}, grpcerr.InvalidArgument("Invalid product list filter or sort order", violations...)
+	}
+	return filter, sort, nil
+}
+
+// productFilter converts f, returning the violations of its fields. Field
+// names start with prefix, e.g. "filter.".
+func productFilter(f *pb.ProductFilter, prefix string) (domain.ProductFilter, []grpcerr.FieldViolation) {
+	var violations []grpcerr.FieldViolation
+	invalid := func(field, description string) {
+		violations = append(violations, grpcerr.FieldViolation{Field: prefix + field, Description: description})
+	}
+
+	filter := domain.ProductFilter{
+		CategoryIDs: f.GetCategoryIds(),
+		MinPrice:    f.MinPrice,
+		MaxPrice:    f.MaxPrice,
+		InStockOnly: f.GetInStockOnly(),
+		NamePrefix:  f.GetNamePrefix(),
+	}
+
+	for i, id := range filter.CategoryIDs {
+		if id == "" {
+			invalid(fmt.Sprintf("category_ids[%d]", i), "Category ID must not be empty")
+		}
+	}
+	if len(filter.CategoryIDs) > maxListCategories {
+		invalid("category_ids", fmt.Sprintf("At most %d categories can be requested at once", maxListCategories))
+	}
+
+	if filter.MinPrice != nil && *filter.MinPrice < 0 {
+		invalid("min_price", "Minimum price must not be negative")
+	}
+	if filter.MaxPrice != nil && *filter.MaxPrice < 0 {
+		invalid("max_price", "Maximum price must not be negative")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		invalid("max_price", "Maximum price must not be less than minimum price")
+	}
+	if len(filter.NamePrefix) > maxNamePrefixLength {
+		invalid("name_prefix", fmt.Sprintf("Name prefix must be at most %d characters", maxNamePrefixLength))
+	}
+
+	timeBound := func(field string, ts *timestamppb.Timestamp) time.Time {
+		if ts == nil {
+			return time.Time{}
+		}
+		if err := ts.CheckValid(); err != nil {
+			invalid(field, "Timestamp is out of range")
+			return time.Time{}
+		}
+		return ts.AsTime()
+	}
+	filter.CreatedAfter = timeBound("created_after", f.GetCreatedAfter())
+	filter.CreatedBefore = timeBound("created_before", f.GetCreatedBefore())
+	filter.UpdatedAfter = timeBound("updated_after", f.GetUpdatedAfter())
+	filter.UpdatedBefore = timeBound("updated_before", f.GetUpdatedBefore())
+	if !filter.CreatedBefore.IsZero() && !filter.CreatedAfter.Before(filter.CreatedBefore) {
+		invalid("created_before", "Must be later than created_after")
+	}
+	if !filter.UpdatedBefore.IsZero() && !filter.UpdatedAfter.Before(filter.UpdatedBefore) {
+		invalid("updated_before", "Must be later than updated_after")
+	}
+	return filter, violations
+}
+
+// defaultPriceBoundaries are the price buckets of GetProductFacets when the
+// request sets none.
+var defaultPriceBoundaries = []float64{10, 50, 100, 500, 1000}
+
+// maxPriceBoundaries limits the number of price buckets.
+const maxPriceBoundaries = 20
+
+func (s *InventoryServer) GetProductFacets(ctx context.Context, req *pb.GetProductFacetsRequest) (*pb.GetProductFacetsResponse, error) {
+	filter, violations := productFilter(req.Filter, "filter.")
+
+	boundaries := req.PriceBoundaries
+	if len(boundaries) == 0 {
+		boundaries = defaultPriceBoundaries
+	}
+	if len(boundaries) > maxPriceBoundaries {
+		violations = append(violations, grpcerr.FieldViolation{
+			Field:       "price_boundaries",
+			Description: fmt.Sprintf("At most %d price boundaries are allowed", maxPriceBoundaries),
+		})
+	}
+	for i, b := range boundaries {
+		if b <= 0 || math.IsInf(b, 0) || math.IsNaN(b) || i > 0 && b <= boundaries[i-1] {
+			violations = append(violations, grpcerr.FieldViolation{
+				Field:       fmt.Sprintf("price_boundaries[%d]", i),
+				Description: "Price boundaries must be positive and strictly increasing",
+			})
+		}
+	}
+	if len(violations) > 0 {
+		return nil, grpcerr.InvalidArgument("Invalid product facet filter or price boundaries", violations...)
+	}
+
+	if req.Filter.GetIncludeDescendants() {
+		if err := s.addDescendantCategories(ctx, &filter); err != nil {
+			return nil, err
+		}
+	}
+
+	facets, err := s.productStore.Facets(ctx, filter, boundaries)
+	if err != nil {
+		return nil, errorTranslator.Error(err, "Failed to get product facets")
+	}
+	return ProductFacetsToProto(facets), nil
+}
+
+// addDescendantCategories extends the category filter to the subcategories
+// of the requested categories.
+func (s *InventoryServer) addDescendantCategories(ctx context.Context, filter *domain.ProductFilter) error {
+	if len(filter.CategoryIDs) == 0 {
+		return nil
+	}
+	ids, err := s.categoryStore.WithDescendants(ctx, filter.CategoryIDs)
+	if err != nil {
+		return errorTranslator.Error(err, "Failed to resolve subcategories")
+	}
+	filter.CategoryIDs = ids
+	return nil
+}
+
+// maxSlugLength limits the length of category slugs.
+const maxSlugLength = 100
+
+// categorySlug checks a requested slug, or derives one from name if slug
+// is empty and name is set.
+func categorySlug(slug, name string) (string, error) {
+	if slug == "" && name != "" {
+		slug = domain.Slugify(name)
+		for len(slug) > maxSlugLength {
+			_, size := utf8.DecodeLastRuneInString(slug)
+			slug = strings.TrimRight(slug[:len(slug)-size], "-")
+		}
+	}
+	switch {
+	case slug == "":
+		return "", grpcerr.InvalidArgumentf("slug", "Slug is required when the name has no letters or digits")
+	case len(slug) > maxSlugLength:
+		return "", grpcerr.InvalidArgumentf("slug", "Slug must be at most %d characters", maxSlugLength)
+	case slug != domain.Slugify(slug):
+		return "", grpcerr.InvalidArgumentf("slug", "Slug must be lower-case letters and digits separated by single hyphens")
+	}
+	return slug, nil
+}
+
+// productUpdateFields returns the fields named in the update mask, or all
+// updatable fields if the mask is empty.
+func productUpdateFields(mask *fieldmaskpb.FieldMask) ([]string, error) {
+	if len(mask.GetPaths()) == 0 {
+		return domain.ProductUpdatableFields, nil
+	}
+	mask.Normalize()
+	for _, path := range mask.GetPaths() {
+		if !slices.Contains(domain.ProductUpdatableFields, path) {
+			return nil, grpcerr.InvalidArgumentf("update_mask", "Field %q cannot be updated", path)
+		}
+	}
+	return mask.GetPaths(), nil
+}
+
+// validateProduct checks the given fields of p.
+func validateProduct(p *domain.Product, fields []string) error {
+	var violations []grpcerr.FieldViolation
+	for _, field := range fields {
+		switch {
+		case field == domain.ProductFieldName && p.Name == "":
+			violations = append(violations, grpcerr.FieldViolation{Field: field, Description: "Name is required"})
+		case field == domain.ProductFieldPrice && p.Price <= 0:
+			violations = append(violations, grpcerr.FieldViolation{Field: field, Description: "Price must be positive"})
+		case field == domain.ProductFieldStock && p.Stock < 0:
+			violations = append(violations, grpcerr.FieldViolation{Field: field, Description: "Stock must not be negative"})
+		case field == domain.ProductFieldCategoryID && p.CategoryID == "":
+			violations = append(violations, grpcerr.FieldViolation{Field: field, Description: "Category ID is required"})
+		}
+	}
+	if len(violations) > 0 {
+		return grpcerr.InvalidArgument("Name, positive price, non-negative stock and category ID are required", violations...)
+	}
+	return nil
+}
@@ -1,14 +1,72 @@
 package domain
 
 import (
+	"strings"
 	"time"
+	"unicode"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Category fields that can be changed by an update, named as in the API.
+const (
+	CategoryFieldName     = "name"
+	CategoryFieldSlug     = "slug"
+	CategoryFieldParentID = "parent_id"
+)
+
+// Categories form a tree. Path lists the IDs of all ancestors from the root
+// down to the parent, so the descendants of a category are the categories
+// whose path contains its ID. Root categories have no parent and an empty
+// path.
 type Category struct {
 	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name      string             `json:"name" bson:"name" binding:"required"`
+	Slug      string             `json:"slug" bson:"slug,omitempty"`
+	ParentID  string             `json:"parent_id" bson:"parent_id,omitempty"`
+	Path      []string           `json:"path" bson:"path"`
 	CreatedAt time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt time.Time          `json:"updated_at" bson:"updated_at"`
+	Version   int64              `json:"version" bson:"version"`
+	// DeletedAt is set when the category is soft-deleted. Categories
+	// deleted together with their subtree share the same DeletedAt, which is
+	// how a restore finds them again. Soft-deleted categories are hidden
+	// from reads unless asked for.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
+
+// CategoryDeleteMode decides what happens to the products and
+// subcategories of a deleted category.
+type CategoryDeleteMode int
+
+const (
+	// CategoryDeleteReject only deletes categories without products and
+	// subcategories.
+	CategoryDeleteReject CategoryDeleteMode = iota
+	// CategoryDeleteReassign moves the products and subcategories to
+	// another category.
+	CategoryDeleteReassign
+	// CategoryDeleteCascade soft-deletes the category, its subcategories
+	// and all their products.
+	CategoryDeleteCascade
+)
+
+// Slugify turns a name into a URL-friendly slug: lower-case letters and
+// digits separated by single hyphens, e.g. "Home & Garden" becomes
+// "home-garden".
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	return b.String()
 }
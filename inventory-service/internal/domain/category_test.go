@@ -0,0 +1,28 @@
+package domain
+
+import "testing"
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Electronics", "electronics"},
+		{"Home & Garden", "home-garden"},
+		{"  Leading and trailing  ", "leading-and-trailing"},
+		{"USB-C  Cables", "usb-c-cables"},
+		{"4K TVs", "4k-tvs"},
+		{"Café Crème", "café-crème"},
+		{"Одежда и обувь", "одежда-и-обувь"},
+		{"---", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Slugify(tt.name); got != tt.want {
+				t.Errorf("Slugify(%q) = %q, want %q", tt.name, got, tt.want)
+			}
+		})
+	}
+}
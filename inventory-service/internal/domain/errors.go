@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by the stores. Check them with errors.Is; the
+// stores wrap them in *Error to say which resource they refer to.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrInvalidID         = errors.New("invalid id")
+	ErrConflict          = errors.New("conflict")
+	ErrVersionMismatch   = errors.New("version mismatch")
+	ErrInsufficientStock = errors.New("insufficient stock")
+	ErrInvalidStatus     = errors.New("invalid status")
+	ErrCategoryCycle     = errors.New("category cycle")
+	ErrCategoryNotEmpty  = errors.New("category not empty")
+	ErrUnknownCategory   = errors.New("unknown category")
+	ErrCategoryDeleted   = errors.New("category deleted")
+
+	// ErrReservationReleased is returned instead of ErrInvalidStatus when
+	// the reservation was already released.
+	ErrReservationReleased = errors.New("reservation released")
+)
+
+// Resource types used in errors.
+const (
+	ResourceProduct     = "product"
+	ResourceCategory    = "category"
+	ResourceReservation = "reservation"
+)
+
+// Error ties one of the sentinel errors to the resource it was returned for.
+type Error struct {
+	kind     error
+	resource string
+	id       string
+	field    string
+	msg      string
+}
+
+// NewError returns an error of the given kind about the resource with id.
+func NewError(kind error, resource, id, format string, args ...any) *Error {
+	return &Error{kind: kind, resource: resource, id: id, msg: fmt.Sprintf(format, args...)}
+}
+
+// NotFoundError reports that the resource with id does not exist.
+func NotFoundError(resource, id string) *Error {
+	return NewError(ErrNotFound, resource, id, "%s %s not found", resource, id)
+}
+
+// InvalidIDError reports an id that is not a valid ObjectID.
+func InvalidIDError(resource, id string) *Error {
+	return NewError(ErrInvalidID, resource, id, "invalid %s id format: %q", resource, id).WithField("id")
+}
+
+// UnknownCategoryError reports that the category a request field refers to
+// does not exist.
+func UnknownCategoryError(id, field string) *Error {
+	return NewError(ErrUnknownCategory, ResourceCategory, id, "category %s does not exist", id).WithField(field)
+}
+
+// WithField records the request field the error is about.
+func (e *Error) WithField(field string) *Error {
+	e.field = field
+	return e
+}
+
+func (e *Error) Error() string { return e.msg }
+
+func (e *Error) Unwrap() error { return e.kind }
+
+// ResourceType returns the kind of resource, e.g. "product".
+func (e *Error) ResourceType() string { return e.resource }
+
+// ResourceName returns the ID of the resource.
+func (e *Error) ResourceName() string { return e.id }
+
+// Field returns the request field the error is about, if any.
+func (e *Error) Field() string { return e.field }
@@ -0,0 +1,30 @@
+package domain
+
+// EventSource identifies this service in published events.
+const EventSource = "inventory-service"
+
+const (
+	EventProductCreated  = "ProductCreated"
+	EventProductUpdated  = "ProductUpdated"
+	EventProductDeleted  = "ProductDeleted"
+	EventProductRestored = "ProductRestored"
+	EventStockChanged    = "StockChanged"
+	EventProductPurged   = "ProductPurged"
+)
+
+type ProductDeletedPayload struct {
+	ProductID string `json:"product_id"`
+}
+
+// ProductPurgedPayload tells consumers that a deleted product is gone for
+// good and can no longer be restored.
+type ProductPurgedPayload struct {
+	ProductID string `json:"product_id"`
+}
+
+// StockChangedPayload carries the change and the resulting stock level.
+type StockChangedPayload struct {
+	ProductID string `json:"product_id"`
+	Delta     int    `json:"delta"`
+	Stock     int    `json:"stock"`
+}
@@ -6,6 +6,27 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Product fields, named as in the API. The timestamps can only be sorted
+// by; the others can also be updated one by one.
+const (
+	ProductFieldName        = "name"
+	ProductFieldDescription = "description"
+	ProductFieldPrice       = "price"
+	ProductFieldStock       = "stock"
+	ProductFieldCategoryID  = "category_id"
+	ProductFieldCreatedAt   = "created_at"
+	ProductFieldUpdatedAt   = "updated_at"
+)
+
+// ProductUpdatableFields lists the fields changed by a full update.
+var ProductUpdatableFields = []string{
+	ProductFieldName,
+	ProductFieldDescription,
+	ProductFieldPrice,
+	ProductFieldStock,
+	ProductFieldCategoryID,
+}
+
 type Product struct {
 	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
 	Name        string             `json:"name" bson:"name" binding:"required"`
@@ -15,4 +36,79 @@ type Product struct {
 	CategoryID  string             `json:"category_id" bson:"category_id" binding:"required"`
 	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
 	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+	// Version grows with every change made by an admin and guards updates
+	// against lost writes. Reservations and releases change the stock without
+	// it, so busy products can still be edited. Documents created before
+	// versioning have 0.
+	Version int64 `json:"version" bson:"version"`
+	// StockVersion grows with every stock change, reservations and releases
+	// included. Updates that set the stock are checked against it, so they
+	// cannot overwrite stock that was reserved after the client read it.
+	StockVersion int64 `json:"stock_version" bson:"stock_version"`
+	// DeletedAt is set when the product is soft-deleted. Soft-deleted
+	// products are hidden from reads unless asked for, can be restored and
+	// are purged once the retention has passed.
+	DeletedAt *time.Time `json:"deleted_at,omitempty" bson:"deleted_at,omitempty"`
+}
+
+// ProductSortFields lists the fields products can be sorted by.
+var ProductSortFields = []string{
+	ProductFieldCreatedAt,
+	ProductFieldUpdatedAt,
+	ProductFieldPrice,
+	ProductFieldName,
+	ProductFieldStock,
+}
+
+// ProductFilter selects products in a list. Zero fields do not filter.
+// Price bounds and *After are inclusive, *Before is exclusive.
+type ProductFilter struct {
+	CategoryIDs   []string
+	MinPrice      *float64
+	MaxPrice      *float64
+	InStockOnly   bool
+	NamePrefix    string // без учета регистра
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+	// IncludeDeleted also matches soft-deleted products.
+	IncludeDeleted bool
+}
+
+// ProductSort orders a product list by one of ProductSortFields. Products
+// with equal values are ordered by ID in the same direction.
+type ProductSort struct {
+	Field      string
+	Descending bool
+}
+
+// ProductFacets summarizes the products matching a filter.
+type ProductFacets struct {
+	// Categories are ordered by count, largest first.
+	Categories   []CategoryCount
+	InStock      int64
+	OutOfStock   int64
+	PriceBuckets []PriceBucket
+}
+
+// CategoryCount is the number of matching products in a category.
+type CategoryCount struct {
+	CategoryID string
+	Count      int64
+}
+
+// PriceBucket counts the products with Min <= price < Max. Max is nil for
+// the last bucket, which has no upper bound.
+type PriceBucket struct {
+	Min   float64
+	Max   *float64
+	Count int64
+}
+
+// ProductSearchHit is a product found by full-text search. Score is the
+// relevance given by the text index; higher is better.
+type ProductSearchHit struct {
+	Product *Product
+	Score   float64
 }
@@ -3,35 +3,84 @@ package repository
 import (
 	"context"
 	"ecommerce-microservices/inventory-service/internal/domain"
+	"ecommerce-microservices/pkg/metrics"
+	"ecommerce-microservices/pkg/outbox"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const categoryCollectionName = "categories"
 
+// MongoCategoryStore keeps the category tree as materialized paths (see
+// domain.Category). Moving a category rewrites the paths of its whole
+// subtree in one transaction. Deleting a category can change its products;
+// their events are written to the outbox like those of MongoProductStore.
 type MongoCategoryStore struct {
 	collection *mongo.Collection
+	products   *mongo.Collection
+	outbox     *outbox.Store
 }
 
+var categoryStoreMetrics = metrics.NewStoreMetrics("category")
+
 func NewMongoCategoryStore(db *mongo.Database) *MongoCategoryStore {
 	collection := db.Collection(categoryCollectionName)
-	return &MongoCategoryStore{collection: collection}
+	return &MongoCategoryStore{
+		collection: collection,
+		products:   db.Collection(productCollectionName),
+		outbox:     outbox.NewStore(db),
+	}
 }
 
-func (s *MongoCategoryStore) Create(ctx context.Context, category *domain.Category) error {
+// EnsureIndexes creates the indexes the store relies on. It is safe to call
+// on every startup.
+func (s *MongoCategoryStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Категории, созданные до появления slug, его не имеют
+			Keys: bson.D{{Key: "slug", Value: 1}},
+			Options: options.Index().SetUnique(true).
+				SetPartialFilterExpression(bson.M{"slug": bson.M{"$type": "string"}}),
+		},
+		{Keys: bson.D{{Key: "parent_id", Value: 1}, {Key: "name", Value: 1}}},
+		{Keys: bson.D{{Key: "path", Value: 1}}},
+		deletedAtIndex(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create category indexes: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoCategoryStore) Create(ctx context.Context, category *domain.Category) (err error) {
+	defer categoryStoreMetrics.Observe("Create", time.Now(), &err)
+
 	category.CreatedAt = time.Now()
 	category.UpdatedAt = time.Now()
+	category.Version = 1
+
+	category.Path = []string{}
+	if category.ParentID != "" {
+		parent, err := s.Reference(ctx, category.ParentID, "parent_id")
+		if err != nil {
+			return err
+		}
+		category.Path = childPath(parent)
+	}
 
+	category.ID = primitive.NilObjectID
 	result, err := s.collection.InsertOne(ctx, category)
 	if err != nil {
 		if mongo.IsDuplicateKeyError(err) {
-			return fmt.Errorf("category with name '%s' already exists", category.Name)
+			return slugConflictError("", category.Slug)
 		}
 		return fmt.Errorf("failed to insert category: %w", err)
 	}
@@ -39,77 +88,603 @@ func (s *MongoCategoryStore) Create(ctx context.Context, category *domain.Catego
 	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
 		category.ID = oid
 	}
-	log.Printf("Inserted category with ID: %v", result.InsertedID)
+	slog.DebugContext(ctx, "Inserted category", "category_id", category.ID.Hex())
 	return nil
 }
 
-func (s *MongoCategoryStore) GetByID(ctx context.Context, id string) (*domain.Category, error) {
+func (s *MongoCategoryStore) GetByID(ctx context.Context, id string) (_ *domain.Category, err error) {
+	defer categoryStoreMetrics.Observe("GetByID", time.Now(), &err)
+	return s.getByID(ctx, id, notDeleted(bson.M{}))
+}
+
+// GetByIDIncludingDeleted is GetByID that also finds soft-deleted
+// categories.
+func (s *MongoCategoryStore) GetByIDIncludingDeleted(ctx context.Context, id string) (_ *domain.Category, err error) {
+	defer categoryStoreMetrics.Observe("GetByIDIncludingDeleted", time.Now(), &err)
+	return s.getByID(ctx, id, bson.M{})
+}
+
+func (s *MongoCategoryStore) getByID(ctx context.Context, id string, filter bson.M) (*domain.Category, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid id format: %w", err)
+		return nil, domain.InvalidIDError(domain.ResourceCategory, id)
 	}
+	filter["_id"] = objID
 
 	var category domain.Category
-	err = s.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&category)
+	err = s.collection.FindOne(ctx, filter).Decode(&category)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, fmt.Errorf("category not found") // Simple not found error
+			return nil, domain.NotFoundError(domain.ResourceCategory, id)
 		}
 		return nil, fmt.Errorf("failed to find category: %w", err)
 	}
 	return &category, nil
 }
 
-func (s *MongoCategoryStore) Update(ctx context.Context, id string, category *domain.Category) error {
+// Update copies the given fields (domain.CategoryField*) from category. It
+// only applies if the category is still at category.Version, otherwise it
+// returns domain.ErrVersionMismatch. Changing the parent moves the whole
+// subtree; moving a category below itself returns domain.ErrCategoryCycle.
+func (s *MongoCategoryStore) Update(ctx context.Context, id string, category *domain.Category, fields []string) (err error) {
+	defer categoryStoreMetrics.Observe("Update", time.Now(), &err)
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid id format: %w", err)
+		return domain.InvalidIDError(domain.ResourceCategory, id)
 	}
 
-	filter := bson.M{"_id": objID}
-	update := bson.M{
-		"$set": bson.M{
-			"name":       category.Name,
-			"updated_at": time.Now(),
-		},
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		set := bson.M{"updated_at": time.Now()}
+		var movedPath []string
+		for _, field := range fields {
+			switch field {
+			case domain.CategoryFieldName:
+				set["name"] = category.Name
+			case domain.CategoryFieldSlug:
+				set["slug"] = category.Slug
+			case domain.CategoryFieldParentID:
+				path := []string{}
+				if category.ParentID != "" {
+					parent, err := s.Reference(ctx, category.ParentID, "parent_id")
+					if err != nil {
+						return err
+					}
+					if parent.ID == objID || slices.Contains(parent.Path, id) {
+						return domain.NewError(domain.ErrCategoryCycle, domain.ResourceCategory, id,
+							"category %s cannot be moved below itself", id).WithField("parent_id")
+					}
+					// Запись в нового родителя делает встречные переносы в параллельных
+					// транзакциях конфликтующими, иначе обе проверки на цикл проходят
+					if err := s.touch(ctx, parent.ID); err != nil {
+						return err
+					}
+					path = childPath(parent)
+				}
+				set["parent_id"] = category.ParentID
+				set["path"] = path
+				movedPath = path
+			default:
+				return fmt.Errorf("category field %q cannot be updated", field)
+			}
+		}
+
+		filter := notDeleted(bson.M{"_id": objID, "version": versionFilter(category.Version)})
+		update := bson.M{
+			"$set": set,
+			"$inc": bson.M{"version": 1},
+		}
+		result, err := s.collection.UpdateOne(ctx, filter, update)
+		if err != nil {
+			if mongo.IsDuplicateKeyError(err) {
+				return slugConflictError(id, category.Slug)
+			}
+			return fmt.Errorf("failed to update category: %w", err)
+		}
+		if result.MatchedCount == 0 {
+			count, err := s.collection.CountDocuments(ctx, notDeleted(bson.M{"_id": objID}))
+			if err != nil {
+				return fmt.Errorf("failed to check category: %w", err)
+			}
+			if count == 0 {
+				return domain.NotFoundError(domain.ResourceCategory, id)
+			}
+			return versionMismatchError(domain.ResourceCategory, id, category.Version)
+		}
+
+		if movedPath != nil {
+			return s.movePaths(ctx, id, append(slices.Clip(movedPath), id))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	slog.DebugContext(ctx, "Updated category", "category_id", id)
+	return nil
+}
 
-	result, err := s.collection.UpdateOne(ctx, filter, update)
+// movePaths rewrites the paths of all descendants of category id,
+// replacing the part up to and including id with prefix.
+func (s *MongoCategoryStore) movePaths(ctx context.Context, id string, prefix []string) error {
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"path": bson.M{"$concatArrays": bson.A{
+			prefix,
+			bson.M{"$slice": bson.A{
+				"$path",
+				bson.M{"$add": bson.A{bson.M{"$indexOfArray": bson.A{"$path", id}}, 1}},
+				bson.M{"$max": bson.A{bson.M{"$size": "$path"}, 1}},
+			}},
+		}},
+		"updated_at": time.Now(),
+		"version":    bson.M{"$add": bson.A{bson.M{"$ifNull": bson.A{"$version", 0}}, 1}},
+	}}}}
+	result, err := s.collection.UpdateMany(ctx, bson.M{"path": id}, update)
 	if err != nil {
-		if mongo.IsDuplicateKeyError(err) {
-			return fmt.Errorf("category with name '%s' already exists", category.Name)
-		}
-		return fmt.Errorf("failed to update category: %w", err)
+		return fmt.Errorf("failed to move subcategories: %w", err)
+	}
+	slog.DebugContext(ctx, "Moved subcategories", "category_id", id, "count", result.ModifiedCount)
+	return nil
+}
+
+// Reference loads the category that the request field refers to. If it
+// does not exist, the error is domain.ErrUnknownCategory for that field.
+func (s *MongoCategoryStore) Reference(ctx context.Context, id, field string) (*domain.Category, error) {
+	category, err := s.GetByID(ctx, id)
+	if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidID) {
+		return nil, domain.UnknownCategoryError(id, field)
 	}
+	return category, err
+}
 
+// touch bumps the version of category id inside the current transaction.
+// A move reads its new parent and the ancestors in the parent's path; writing
+// to the parent makes a concurrent move of the parent or of any of those
+// ancestors conflict with this one, so two opposite moves cannot both commit
+// and create a cycle.
+func (s *MongoCategoryStore) touch(ctx context.Context, id primitive.ObjectID) error {
+	result, err := s.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": id}), bson.M{"$inc": bson.M{"version": 1}})
+	if err != nil {
+		return fmt.Errorf("failed to lock parent category: %w", err)
+	}
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("category not found to update")
+		return domain.UnknownCategoryError(id.Hex(), "parent_id")
 	}
-	log.Printf("Updated category ID: %s, Matched: %d, Modified: %d", id, result.MatchedCount, result.ModifiedCount)
 	return nil
 }
 
-func (s *MongoCategoryStore) Delete(ctx context.Context, id string) error {
-	objID, err := primitive.ObjectIDFromHex(id)
+// childPath returns the path of a child of parent.
+func childPath(parent *domain.Category) []string {
+	return append(slices.Clip(parent.Path), parent.ID.Hex())
+}
+
+func slugConflictError(id, slug string) error {
+	return domain.NewError(domain.ErrConflict, domain.ResourceCategory, id, "category with slug '%s' already exists", slug).WithField("slug")
+}
+
+// Delete soft-deletes the category; mode decides what happens to its
+// products and subcategories first. reassignTo is the target category of
+// domain.CategoryDeleteReassign. With domain.CategoryDeleteReject a category
+// that is not empty is kept and domain.ErrCategoryNotEmpty is returned.
+// A deleted category keeps its slug until it is purged.
+func (s *MongoCategoryStore) Delete(ctx context.Context, id string, mode domain.CategoryDeleteMode, reassignTo string) (err error) {
+	defer categoryStoreMetrics.Observe("Delete", time.Now(), &err)
+
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		category, err := s.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		switch mode {
+		case domain.CategoryDeleteReject:
+			err = s.checkEmpty(ctx, id)
+		case domain.CategoryDeleteReassign:
+			err = s.reassign(ctx, category, reassignTo)
+		case domain.CategoryDeleteCascade:
+		default:
+			err = fmt.Errorf("unknown category delete mode %d", mode)
+		}
+		if err != nil {
+			return err
+		}
+		// После reject и reassign поддерево уже пустое
+		return s.softDeleteSubtree(ctx, category)
+	})
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Deleted category", "category_id", id, "mode", mode)
+	return nil
+}
+
+// Restore brings back a soft-deleted category together with the
+// subcategories and products that were deleted with it. Its parent must not
+// be deleted. Restoring a category that is not deleted changes nothing.
+func (s *MongoCategoryStore) Restore(ctx context.Context, id string) (_ *domain.Category, err error) {
+	defer categoryStoreMetrics.Observe("Restore", time.Now(), &err)
+
+	var restored *domain.Category
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		category, err := s.GetByIDIncludingDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+		if category.DeletedAt == nil {
+			restored = category
+			return nil
+		}
+		if category.ParentID != "" {
+			_, err := s.GetByID(ctx, category.ParentID)
+			if errors.Is(err, domain.ErrNotFound) {
+				return domain.NewError(domain.ErrCategoryDeleted, domain.ResourceCategory, category.ParentID,
+					"parent category %s is deleted, restore it first", category.ParentID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		// Вместе с категорией удалялось то, что имеет ту же deleted_at
+		deletedAt := *category.DeletedAt
+		now := time.Now()
+		restore := bson.M{
+			"$unset": bson.M{"deleted_at": ""},
+			"$set":   bson.M{"updated_at": now},
+			"$inc":   bson.M{"version": 1},
+		}
+
+		var docs []struct {
+			ID primitive.ObjectID `bson:"_id"`
+		}
+		cursor, err := s.collection.Find(ctx, bson.M{"path": id, "deleted_at": deletedAt}, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return fmt.Errorf("failed to find subcategories: %w", err)
+		}
+		err = cursor.All(ctx, &docs)
+		cursor.Close(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to decode subcategories: %w", err)
+		}
+		objIDs := []primitive.ObjectID{category.ID}
+		ids := []string{id}
+		for _, doc := range docs {
+			objIDs = append(objIDs, doc.ID)
+			ids = append(ids, doc.ID.Hex())
+		}
+		if _, err := s.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": objIDs}}, restore); err != nil {
+			return fmt.Errorf("failed to restore categories: %w", err)
+		}
+
+		productFilter := bson.M{"category_id": bson.M{"$in": ids}, "deleted_at": deletedAt}
+		cursor, err = s.products.Find(ctx, productFilter, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return fmt.Errorf("failed to find category products: %w", err)
+		}
+		docs = nil
+		err = cursor.All(ctx, &docs)
+		cursor.Close(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to decode category products: %w", err)
+		}
+		productIDs := make([]primitive.ObjectID, len(docs))
+		for i, doc := range docs {
+			productIDs[i] = doc.ID
+		}
+		if len(productIDs) > 0 {
+			if _, err := s.products.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": productIDs}}, restore); err != nil {
+				return fmt.Errorf("failed to restore category products: %w", err)
+			}
+			if err := s.addProductEvents(ctx, domain.EventProductRestored, productIDs); err != nil {
+				return err
+			}
+		}
+
+		restored, err = s.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		slog.DebugContext(ctx, "Restored category subtree", "category_id", id, "categories", len(ids), "products", len(productIDs))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// Purge removes categories that were soft-deleted before the given time.
+// Categories of deleted products that MongoProductStore.Purge kept are kept
+// as well, so those products can still be restored with their category. So
+// is every ancestor of a category that stays, so that no path refers to a
+// removed category.
+func (s *MongoCategoryStore) Purge(ctx context.Context, before time.Time) (_ int64, err error) {
+	defer categoryStoreMetrics.Observe("Purge", time.Now(), &err)
+
+	// Товары с активными резервами переживают purge, их категории тоже
+	used, err := s.products.Distinct(ctx, "category_id", bson.M{"deleted_at": bson.M{"$type": "date"}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to find categories of deleted products: %w", err)
+	}
+	keep := hexObjectIDs(used)
+
+	// Distinct по массиву path возвращает отдельные ID предков
+	ancestors, err := s.collection.Distinct(ctx, "path", bson.M{"$or": bson.A{
+		bson.M{"deleted_at": bson.M{"$not": bson.M{"$lt": before}}},
+		bson.M{"_id": bson.M{"$in": keep}},
+	}})
 	if err != nil {
-		return fmt.Errorf("invalid id format: %w", err)
+		return 0, fmt.Errorf("failed to find ancestors of kept categories: %w", err)
 	}
+	keep = append(keep, hexObjectIDs(ancestors)...)
 
-	filter := bson.M{"_id": objID}
-	result, err := s.collection.DeleteOne(ctx, filter)
+	result, err := s.collection.DeleteMany(ctx, bson.M{
+		"deleted_at": bson.M{"$lt": before},
+		"_id":        bson.M{"$nin": keep},
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete category: %w", err)
+		return 0, fmt.Errorf("failed to purge categories: %w", err)
 	}
+	return result.DeletedCount, nil
+}
 
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("category not found to delete")
+// hexObjectIDs converts the hex IDs returned by Distinct to ObjectIDs,
+// skipping values that are not valid IDs.
+func hexObjectIDs(values []any) []primitive.ObjectID {
+	ids := make([]primitive.ObjectID, 0, len(values))
+	for _, raw := range values {
+		if id, ok := raw.(string); ok {
+			if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+				ids = append(ids, objID)
+			}
+		}
+	}
+	return ids
+}
+
+// checkEmpty returns domain.ErrCategoryNotEmpty if the category has
+// products or subcategories.
+func (s *MongoCategoryStore) checkEmpty(ctx context.Context, id string) error {
+	products, err := s.products.CountDocuments(ctx, notDeleted(bson.M{"category_id": id}), options.Count().SetLimit(1))
+	if err != nil {
+		return fmt.Errorf("failed to count category products: %w", err)
+	}
+	children, err := s.collection.CountDocuments(ctx, notDeleted(bson.M{"parent_id": id}), options.Count().SetLimit(1))
+	if err != nil {
+		return fmt.Errorf("failed to count subcategories: %w", err)
+	}
+	if products > 0 || children > 0 {
+		return domain.NewError(domain.ErrCategoryNotEmpty, domain.ResourceCategory, id,
+			"category %s still has products or subcategories, reassign or cascade them", id)
 	}
-	log.Printf("Deleted category ID: %s, Count: %d", id, result.DeletedCount)
 	return nil
 }
 
-func (s *MongoCategoryStore) List(ctx context.Context) ([]*domain.Category, error) {
-	cursor, err := s.collection.Find(ctx, bson.M{})
+// reassign moves the products and direct subcategories of category to the
+// category targetID. The subcategories keep their own subtrees.
+func (s *MongoCategoryStore) reassign(ctx context.Context, category *domain.Category, targetID string) error {
+	id := category.ID.Hex()
+	target, err := s.Reference(ctx, targetID, "reassign_to_category_id")
+	if err != nil {
+		return err
+	}
+	if target.ID == category.ID || slices.Contains(target.Path, id) {
+		return domain.NewError(domain.ErrCategoryCycle, domain.ResourceCategory, id,
+			"category %s cannot be reassigned to itself or its subcategory %s", id, targetID).WithField("reassign_to_category_id")
+	}
+
+	_, err = s.collection.UpdateMany(ctx, bson.M{"parent_id": id}, bson.M{
+		"$set": bson.M{"parent_id": targetID, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reassign subcategories: %w", err)
+	}
+	if err := s.movePaths(ctx, id, childPath(target)); err != nil {
+		return err
+	}
+
+	productIDs, err := s.productIDs(ctx, []string{id})
+	if err != nil || len(productIDs) == 0 {
+		return err
+	}
+	_, err = s.products.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": productIDs}}, bson.M{
+		"$set": bson.M{"category_id": targetID, "updated_at": time.Now()},
+		"$inc": bson.M{"version": 1},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reassign products: %w", err)
+	}
+
+	if err := s.addProductEvents(ctx, domain.EventProductUpdated, productIDs); err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Reassigned category products", "category_id", id, "target_id", targetID, "count", len(productIDs))
+	return nil
+}
+
+// addProductEvents writes an event of eventType carrying the current state
+// of each of the products.
+func (s *MongoCategoryStore) addProductEvents(ctx context.Context, eventType string, productIDs []primitive.ObjectID) error {
+	cursor, err := s.products.Find(ctx, bson.M{"_id": bson.M{"$in": productIDs}})
+	if err != nil {
+		return fmt.Errorf("failed to read changed products: %w", err)
+	}
+	defer cursor.Close(ctx)
+	var products []domain.Product
+	if err := cursor.All(ctx, &products); err != nil {
+		return fmt.Errorf("failed to decode changed products: %w", err)
+	}
+	for _, p := range products {
+		if err := addProductEvent(ctx, s.outbox, eventType, p.ID.Hex(), p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// softDeleteSubtree marks category, its subcategories and all their
+// products as deleted.
+func (s *MongoCategoryStore) softDeleteSubtree(ctx context.Context, category *domain.Category) error {
+	id := category.ID.Hex()
+	now := time.Now()
+	deleted := bson.M{
+		"$set": bson.M{"deleted_at": now, "updated_at": now},
+		"$inc": bson.M{"version": 1},
+	}
+
+	cursor, err := s.collection.Find(ctx, notDeleted(bson.M{"path": id}), options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return fmt.Errorf("failed to find subcategories: %w", err)
+	}
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	err = cursor.All(ctx, &docs)
+	cursor.Close(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to decode subcategories: %w", err)
+	}
+	objIDs := []primitive.ObjectID{category.ID}
+	ids := []string{id}
+	for _, doc := range docs {
+		objIDs = append(objIDs, doc.ID)
+		ids = append(ids, doc.ID.Hex())
+	}
+
+	if _, err := s.collection.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": objIDs}}, deleted); err != nil {
+		return fmt.Errorf("failed to delete categories: %w", err)
+	}
+
+	productIDs, err := s.productIDs(ctx, ids)
+	if err != nil || len(productIDs) == 0 {
+		return err
+	}
+	if _, err := s.products.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": productIDs}}, deleted); err != nil {
+		return fmt.Errorf("failed to delete category products: %w", err)
+	}
+	for _, productID := range productIDs {
+		id := productID.Hex()
+		if err := addProductEvent(ctx, s.outbox, domain.EventProductDeleted, id, domain.ProductDeletedPayload{ProductID: id}); err != nil {
+			return err
+		}
+	}
+	slog.DebugContext(ctx, "Soft-deleted category subtree", "category_id", id, "categories", len(ids), "products", len(productIDs))
+	return nil
+}
+
+// productIDs returns the IDs of the products in the given categories.
+func (s *MongoCategoryStore) productIDs(ctx context.Context, categoryIDs []string) ([]primitive.ObjectID, error) {
+	cursor, err := s.products.Find(ctx, notDeleted(bson.M{"category_id": bson.M{"$in": categoryIDs}}), options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find category products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err := cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode category products: %w", err)
+	}
+	ids := make([]primitive.ObjectID, len(docs))
+	for i, doc := range docs {
+		ids[i] = doc.ID
+	}
+	return ids, nil
+}
+
+// List returns all categories ordered by name. Soft-deleted categories are
+// only included if asked for.
+func (s *MongoCategoryStore) List(ctx context.Context, includeDeleted bool) (_ []*domain.Category, err error) {
+	defer categoryStoreMetrics.Observe("List", time.Now(), &err)
+	if includeDeleted {
+		return s.find(ctx, bson.M{})
+	}
+	return s.find(ctx, notDeleted(bson.M{}))
+}
+
+// Subtree returns the category rootID and all its descendants, or every
+// category if rootID is empty.
+func (s *MongoCategoryStore) Subtree(ctx context.Context, rootID string) (_ []*domain.Category, err error) {
+	defer categoryStoreMetrics.Observe("Subtree", time.Now(), &err)
+
+	if rootID == "" {
+		return s.find(ctx, notDeleted(bson.M{}))
+	}
+	root, err := s.GetByID(ctx, rootID)
+	if err != nil {
+		return nil, err
+	}
+	descendants, err := s.find(ctx, notDeleted(bson.M{"path": rootID}))
+	if err != nil {
+		return nil, err
+	}
+	return append([]*domain.Category{root}, descendants...), nil
+}
+
+// Ancestors returns the ancestors of category from the root down to its
+// parent. Ancestors that no longer exist are left out.
+func (s *MongoCategoryStore) Ancestors(ctx context.Context, category *domain.Category) (_ []*domain.Category, err error) {
+	defer categoryStoreMetrics.Observe("Ancestors", time.Now(), &err)
+
+	objIDs := make([]primitive.ObjectID, 0, len(category.Path))
+	for _, id := range category.Path {
+		if objID, err := primitive.ObjectIDFromHex(id); err == nil {
+			objIDs = append(objIDs, objID)
+		}
+	}
+	if len(objIDs) == 0 {
+		return []*domain.Category{}, nil
+	}
+	found, err := s.find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": objIDs}}))
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[string]*domain.Category, len(found))
+	for _, c := range found {
+		byID[c.ID.Hex()] = c
+	}
+	ancestors := make([]*domain.Category, 0, len(found))
+	for _, id := range category.Path {
+		if c, ok := byID[id]; ok {
+			ancestors = append(ancestors, c)
+		}
+	}
+	return ancestors, nil
+}
+
+// WithDescendants returns ids followed by the IDs of all their descendants,
+// without duplicates.
+func (s *MongoCategoryStore) WithDescendants(ctx context.Context, ids []string) (_ []string, err error) {
+	defer categoryStoreMetrics.Observe("WithDescendants", time.Now(), &err)
+
+	cursor, err := s.collection.Find(ctx, notDeleted(bson.M{"path": bson.M{"$in": ids}}), options.Find().SetProjection(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to find subcategories: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var docs []struct {
+		ID primitive.ObjectID `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, fmt.Errorf("failed to decode subcategories: %w", err)
+	}
+
+	result := slices.Clone(ids)
+	for _, doc := range docs {
+		if id := doc.ID.Hex(); !slices.Contains(result, id) {
+			result = append(result, id)
+		}
+	}
+	return result, nil
+}
+
+// find returns the categories matching filter, ordered by name.
+func (s *MongoCategoryStore) find(ctx context.Context, filter bson.M) ([]*domain.Category, error) {
+	cursor, err := s.collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}))
 	if err != nil {
 		return nil, fmt.Errorf("failed to list categories: %w", err)
 	}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestCategoryStorePurgeKeepsAncestors(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("purge", func(mt *mtest.T) {
+		store := NewMongoCategoryStore(mt.DB)
+		used := primitive.NewObjectID()   // категория товара с резервом
+		parent := primitive.NewObjectID() // предок оставшейся категории
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{used.Hex(), "not-an-id"}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "values", Value: bson.A{parent.Hex()}}),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 3}),
+		)
+
+		purged, err := store.Purge(context.Background(), time.Now())
+		if err != nil {
+			mt.Fatalf("Purge() error = %v", err)
+		}
+		if purged != 3 {
+			mt.Errorf("Purge() = %d, want 3", purged)
+		}
+
+		mt.GetStartedEvent() // distinct по товарам
+		ancestors := mt.GetStartedEvent()
+		if key := ancestors.Command.Lookup("key").StringValue(); ancestors.CommandName != "distinct" || key != "path" {
+			mt.Fatalf("second command = %s of %q, want distinct of path", ancestors.CommandName, key)
+		}
+
+		del := mt.GetStartedEvent()
+		if del.CommandName != "delete" {
+			mt.Fatalf("third command = %s, want delete", del.CommandName)
+		}
+		q := del.Command.Lookup("deletes").Array().Index(0).Value().Document().Lookup("q").Document()
+		values, err := q.Lookup("_id", "$nin").Array().Values()
+		if err != nil {
+			mt.Fatalf("$nin: %v", err)
+		}
+		var kept []primitive.ObjectID
+		for _, v := range values {
+			kept = append(kept, v.ObjectID())
+		}
+		if want := []primitive.ObjectID{used, parent}; !slices.Equal(kept, want) {
+			mt.Errorf("kept = %v, want %v", kept, want)
+		}
+	})
+}
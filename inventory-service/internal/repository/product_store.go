@@ -3,9 +3,16 @@ package repository
 import (
 	"context"
 	"ecommerce-microservices/inventory-service/internal/domain"
+	"ecommerce-microservices/pkg/events"
+	"ecommerce-microservices/pkg/metrics"
+	"ecommerce-microservices/pkg/outbox"
+	"ecommerce-microservices/pkg/pagination"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"math"
+	"regexp"
+	"slices"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,108 +21,602 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const productCollectionName = "products"
+const (
+	productCollectionName = "products"
+	productTextIndexName  = "products_text"
+)
 
+// MongoProductStore writes a domain event to the outbox in the same
+// transaction as every product change.
 type MongoProductStore struct {
-	collection *mongo.Collection
+	collection   *mongo.Collection
+	reservations *mongo.Collection
+	outbox       *outbox.Store
 }
 
+var productStoreMetrics = metrics.NewStoreMetrics("product")
+
 func NewMongoProductStore(db *mongo.Database) *MongoProductStore {
 	collection := db.Collection(productCollectionName)
-	return &MongoProductStore{collection: collection}
+	return &MongoProductStore{
+		collection:   collection,
+		reservations: db.Collection(reservationCollectionName),
+		outbox:       outbox.NewStore(db),
+	}
+}
+
+// EnsureIndexes creates the indexes the store relies on. It is safe to call
+// on every startup.
+func (s *MongoProductStore) EnsureIndexes(ctx context.Context) error {
+	indexes := []mongo.IndexModel{{
+		Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}},
+		// Совпадение в названии важнее, чем в описании
+		Options: options.Index().
+			SetName(productTextIndexName).
+			SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "description", Value: 2}}),
+	}}
+	// Для каждой сортировки List: по всему каталогу и внутри категорий.
+	// Индексы читаются в обе стороны, поэтому хватает возрастающих
+	for _, field := range domain.ProductSortFields {
+		indexes = append(indexes,
+			mongo.IndexModel{Keys: bson.D{{Key: field, Value: 1}, {Key: "_id", Value: 1}}},
+			mongo.IndexModel{Keys: bson.D{{Key: "category_id", Value: 1}, {Key: field, Value: 1}, {Key: "_id", Value: 1}}},
+		)
+	}
+	indexes = append(indexes, deletedAtIndex())
+	_, err := s.collection.Indexes().CreateMany(ctx, indexes)
+	if err != nil {
+		return fmt.Errorf("failed to create product indexes: %w", err)
+	}
+	return nil
 }
 
-func (s *MongoProductStore) Create(ctx context.Context, product *domain.Product) error {
+func (s *MongoProductStore) Create(ctx context.Context, product *domain.Product) (err error) {
+	defer productStoreMetrics.Observe("Create", time.Now(), &err)
+
 	product.CreatedAt = time.Now()
 	product.UpdatedAt = time.Now()
+	product.Version = 1
 
-	result, err := s.collection.InsertOne(ctx, product)
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		product.ID = primitive.NilObjectID
+		result, err := s.collection.InsertOne(ctx, product)
+		if err != nil {
+			return fmt.Errorf("failed to insert product: %w", err)
+		}
+		if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+			product.ID = oid
+		}
+		return s.addEvent(ctx, domain.EventProductCreated, product.ID.Hex(), product)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to insert product: %w", err)
+		return err
 	}
-
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		product.ID = oid
-	}
-	log.Printf("Inserted product with ID: %v", result.InsertedID)
+	slog.DebugContext(ctx, "Inserted product", "product_id", product.ID.Hex())
 	return nil
 }
 
-func (s *MongoProductStore) GetByID(ctx context.Context, id string) (*domain.Product, error) {
+func (s *MongoProductStore) GetByID(ctx context.Context, id string) (_ *domain.Product, err error) {
+	defer productStoreMetrics.Observe("GetByID", time.Now(), &err)
+	return s.getByID(ctx, id, notDeleted(bson.M{}))
+}
+
+// GetByIDIncludingDeleted is GetByID that also finds soft-deleted products.
+func (s *MongoProductStore) GetByIDIncludingDeleted(ctx context.Context, id string) (_ *domain.Product, err error) {
+	defer productStoreMetrics.Observe("GetByIDIncludingDeleted", time.Now(), &err)
+	return s.getByID(ctx, id, bson.M{})
+}
+
+func (s *MongoProductStore) getByID(ctx context.Context, id string, filter bson.M) (*domain.Product, error) {
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid id format: %w", err)
+		return nil, domain.InvalidIDError(domain.ResourceProduct, id)
 	}
+	filter["_id"] = objID
 
 	var product domain.Product
-	err = s.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&product)
+	err = s.collection.FindOne(ctx, filter).Decode(&product)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, fmt.Errorf("product not found")
+			return nil, domain.NotFoundError(domain.ResourceProduct, id)
 		}
 		return nil, fmt.Errorf("failed to find product: %w", err)
 	}
 	return &product, nil
 }
 
-func (s *MongoProductStore) Update(ctx context.Context, id string, product *domain.Product) error {
+// GetByIDs loads all requested products with a single $in query. IDs that
+// are malformed or do not match a product are returned in missing, in the
+// order they were requested.
+func (s *MongoProductStore) GetByIDs(ctx context.Context, ids []string) (_ []*domain.Product, _ []string, err error) {
+	defer productStoreMetrics.Observe("GetByIDs", time.Now(), &err)
+
+	var missing []string
+	objIDs := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		objID, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			missing = append(missing, id)
+			continue
+		}
+		objIDs = append(objIDs, objID)
+	}
+
+	products := []*domain.Product{}
+	if len(objIDs) > 0 {
+		cursor, err := s.collection.Find(ctx, notDeleted(bson.M{"_id": bson.M{"$in": objIDs}}))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to find products: %w", err)
+		}
+		defer cursor.Close(ctx)
+
+		if err = cursor.All(ctx, &products); err != nil {
+			return nil, nil, fmt.Errorf("failed to decode products: %w", err)
+		}
+	}
+
+	found := make(map[primitive.ObjectID]bool, len(products))
+	for _, p := range products {
+		found[p.ID] = true
+	}
+	for _, objID := range objIDs {
+		if !found[objID] {
+			missing = append(missing, objID.Hex())
+		}
+	}
+	return products, missing, nil
+}
+
+// Update copies the given fields (see domain.ProductUpdatableFields) from
+// product, leaving the others untouched. It only applies if the product is
+// still at product.Version, otherwise it returns domain.ErrVersionMismatch.
+// The version is incremented in the same update. If the stock is among the
+// fields, the stock must also still be at product.StockVersion.
+func (s *MongoProductStore) Update(ctx context.Context, id string, product *domain.Product, fields []string) (err error) {
+	defer productStoreMetrics.Observe("Update", time.Now(), &err)
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid id format: %w", err)
+		return domain.InvalidIDError(domain.ResourceProduct, id)
+	}
+
+	set := bson.M{"updated_at": time.Now()}
+	for _, field := range fields {
+		switch field {
+		case domain.ProductFieldName:
+			set["name"] = product.Name
+		case domain.ProductFieldDescription:
+			set["description"] = product.Description
+		case domain.ProductFieldPrice:
+			set["price"] = product.Price
+		case domain.ProductFieldStock:
+			set["stock"] = product.Stock
+		case domain.ProductFieldCategoryID:
+			set["category_id"] = product.CategoryID
+		default:
+			return fmt.Errorf("product field %q cannot be updated", field)
+		}
 	}
 
-	filter := bson.M{"_id": objID}
+	filter := notDeleted(bson.M{"_id": objID, "version": versionFilter(product.Version)})
+	inc := bson.M{"version": 1}
+	if slices.Contains(fields, domain.ProductFieldStock) {
+		// Резервы меняют сток без version, поэтому перезапись стока сверяется
+		// с его собственной версией
+		filter["stock_version"] = versionFilter(product.StockVersion)
+		inc["stock_version"] = 1
+	}
 	update := bson.M{
-		"$set": bson.M{
-			"name":        product.Name,
-			"description": product.Description,
-			"price":       product.Price,
-			"stock":       product.Stock,
-			"category_id": product.CategoryID,
-			"updated_at":  time.Now(),
-		},
+		"$set": set,
+		"$inc": inc,
+	}
+
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		var updated domain.Product
+		var before struct {
+			Stock int `bson:"stock"`
+		}
+		err := s.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().
+			SetProjection(bson.M{"stock": 1}).
+			SetReturnDocument(options.Before)).Decode(&before)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				count, err := s.collection.CountDocuments(ctx, notDeleted(bson.M{"_id": objID}))
+				if err != nil {
+					return fmt.Errorf("failed to check product: %w", err)
+				}
+				if count == 0 {
+					return domain.NotFoundError(domain.ResourceProduct, id)
+				}
+				return versionMismatchError(domain.ResourceProduct, id, product.Version)
+			}
+			return fmt.Errorf("failed to update product: %w", err)
+		}
+		if err := s.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&updated); err != nil {
+			return fmt.Errorf("failed to read updated product: %w", err)
+		}
+
+		if err := s.addEvent(ctx, domain.EventProductUpdated, id, updated); err != nil {
+			return err
+		}
+		if updated.Stock != before.Stock {
+			return s.addEvent(ctx, domain.EventStockChanged, id, domain.StockChangedPayload{
+				ProductID: id,
+				Delta:     updated.Stock - before.Stock,
+				Stock:     updated.Stock,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
+	slog.DebugContext(ctx, "Updated product", "product_id", id)
+	return nil
+}
 
-	result, err := s.collection.UpdateOne(ctx, filter, update)
+// Delete soft-deletes the product: it is hidden from reads and stock
+// changes until it is restored or purged.
+func (s *MongoProductStore) Delete(ctx context.Context, id string) (err error) {
+	defer productStoreMetrics.Observe("Delete", time.Now(), &err)
+
+	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("failed to update product: %w", err)
+		return domain.InvalidIDError(domain.ResourceProduct, id)
 	}
 
-	if result.MatchedCount == 0 {
-		return fmt.Errorf("product not found to update")
+	now := time.Now()
+	update := bson.M{
+		"$set": bson.M{"deleted_at": now, "updated_at": now},
+		"$inc": bson.M{"version": 1},
 	}
-	log.Printf("Updated product ID: %s, Matched: %d, Modified: %d", id, result.MatchedCount, result.ModifiedCount)
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		result, err := s.collection.UpdateOne(ctx, notDeleted(bson.M{"_id": objID}), update)
+		if err != nil {
+			return fmt.Errorf("failed to delete product: %w", err)
+		}
+		if result.MatchedCount == 0 {
+			return domain.NotFoundError(domain.ResourceProduct, id)
+		}
+		return s.addEvent(ctx, domain.EventProductDeleted, id, domain.ProductDeletedPayload{ProductID: id})
+	})
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Deleted product", "product_id", id)
 	return nil
 }
 
-func (s *MongoProductStore) Delete(ctx context.Context, id string) error {
+// Restore brings back a soft-deleted product. Restoring a product that is
+// not deleted changes nothing. The caller checks that the category of the
+// product still exists.
+func (s *MongoProductStore) Restore(ctx context.Context, id string) (_ *domain.Product, err error) {
+	defer productStoreMetrics.Observe("Restore", time.Now(), &err)
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid id format: %w", err)
+		return nil, domain.InvalidIDError(domain.ResourceProduct, id)
 	}
 
-	filter := bson.M{"_id": objID}
-	result, err := s.collection.DeleteOne(ctx, filter)
+	var product domain.Product
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		err := s.collection.FindOneAndUpdate(ctx,
+			bson.M{"_id": objID, "deleted_at": bson.M{"$ne": nil}},
+			bson.M{
+				"$unset": bson.M{"deleted_at": ""},
+				"$set":   bson.M{"updated_at": time.Now()},
+				"$inc":   bson.M{"version": 1},
+			},
+			options.FindOneAndUpdate().SetReturnDocument(options.After),
+		).Decode(&product)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			live, err := s.getByID(ctx, id, notDeleted(bson.M{}))
+			if err != nil {
+				return err
+			}
+			product = *live
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to restore product: %w", err)
+		}
+		return s.addEvent(ctx, domain.EventProductRestored, id, product)
+	})
 	if err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
+		return nil, err
 	}
+	slog.DebugContext(ctx, "Restored product", "product_id", id)
+	return &product, nil
+}
+
+// Purge removes products that were soft-deleted before the given time and
+// records a ProductPurged event for each. Products that active reservations
+// still refer to are kept, since releasing such a reservation returns stock
+// to them; a later purge removes them once the reservations are done.
+func (s *MongoProductStore) Purge(ctx context.Context, before time.Time) (purged int64, err error) {
+	defer productStoreMetrics.Observe("Purge", time.Now(), &err)
 
-	if result.DeletedCount == 0 {
-		return fmt.Errorf("product not found to delete")
+	expired := bson.M{"deleted_at": bson.M{"$lt": before}}
+	ids, err := s.collection.Distinct(ctx, "_id", expired)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find deleted products: %w", err)
 	}
-	log.Printf("Deleted product ID: %s, Count: %d", id, result.DeletedCount)
-	return nil
+
+	for _, raw := range ids {
+		objID, ok := raw.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		id := objID.Hex()
+		var deleted bool
+		err := s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+			deleted = false
+			active, err := s.reservations.CountDocuments(ctx, bson.M{
+				"items.product_id": id,
+				"status":           bson.M{"$in": domain.ActiveReservationStatuses},
+			}, options.Count().SetLimit(1))
+			if err != nil {
+				return fmt.Errorf("failed to count reservations of product: %w", err)
+			}
+			if active > 0 {
+				return nil
+			}
+			result, err := s.collection.DeleteOne(ctx, bson.M{"_id": objID, "deleted_at": bson.M{"$lt": before}})
+			if err != nil {
+				return fmt.Errorf("failed to purge product: %w", err)
+			}
+			if result.DeletedCount == 0 {
+				return nil
+			}
+			deleted = true
+			return s.addEvent(ctx, domain.EventProductPurged, id, domain.ProductPurgedPayload{ProductID: id})
+		})
+		if err != nil {
+			return purged, err
+		}
+		if deleted {
+			purged++
+		} else {
+			slog.DebugContext(ctx, "Kept deleted product with active reservations", "product_id", id)
+		}
+	}
+	return purged, nil
+}
+
+// List returns a page of the products matching filter in the given order.
+// If there are more products, next is the cursor to continue after the
+// page. total is the number of all matching products, or 0 if
+// page.CountTotal is not set.
+func (s *MongoProductStore) List(ctx context.Context, filter domain.ProductFilter, sort domain.ProductSort, page pagination.Page) (_ []*domain.Product, next *pagination.Cursor, total int64, err error) {
+	defer productStoreMetrics.Observe("List", time.Now(), &err)
+
+	field := productSortField(sort)
+	// Лишний товар показывает, есть ли следующая страница
+	findOptions := options.Find().SetSort(pagination.Sort(field, sort.Descending))
+	if page.Limit > 0 {
+		findOptions.SetLimit(page.Limit + 1)
+	}
+	if page.After == nil && page.Offset > 0 {
+		findOptions.SetSkip(page.Offset)
+	}
+	query := productFilterBSON(filter)
+
+	if page.CountTotal {
+		total, err = s.collection.CountDocuments(ctx, query)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to count products: %w", err)
+		}
+	}
+
+	cursor, err := s.collection.Find(ctx, pagination.After(query, field, sort.Descending, page.After), findOptions)
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to list products: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	products := []*domain.Product{}
+	if err = cursor.All(ctx, &products); err != nil {
+		return nil, nil, 0, fmt.Errorf("failed to decode products: %w", err)
+	}
+
+	if page.Limit > 0 && int64(len(products)) > page.Limit {
+		products = products[:page.Limit]
+		last := products[len(products)-1]
+		next = &pagination.Cursor{Value: productSortValue(last, field), ID: last.ID}
+	}
+	return products, next, total, nil
 }
 
-func (s *MongoProductStore) List(ctx context.Context, filter bson.M, limit, offset int64) ([]*domain.Product, int64, error) {
-	findOptions := options.Find()
+// Facets counts the products matching filter per category, by
+// availability and per price bucket. priceBoundaries are the ascending,
+// positive lower bounds of all buckets but the first, which starts at 0.
+func (s *MongoProductStore) Facets(ctx context.Context, filter domain.ProductFilter, priceBoundaries []float64) (_ *domain.ProductFacets, err error) {
+	defer productStoreMetrics.Observe("Facets", time.Now(), &err)
+
+	// $bucket требует границы всех документов; последняя корзина открыта
+	boundaries := bson.A{0.0}
+	for _, b := range priceBoundaries {
+		boundaries = append(boundaries, b)
+	}
+	boundaries = append(boundaries, math.Inf(1))
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: productFilterBSON(filter)}},
+		{{Key: "$facet", Value: bson.M{
+			"categories": bson.A{
+				bson.M{"$group": bson.M{"_id": "$category_id", "count": bson.M{"$sum": 1}}},
+				bson.M{"$sort": bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}},
+			},
+			"stock": bson.A{
+				bson.M{"$group": bson.M{
+					"_id":          nil,
+					"in_stock":     bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$stock", 0}}, 1, 0}}},
+					"out_of_stock": bson.M{"$sum": bson.M{"$cond": bson.A{bson.M{"$gt": bson.A{"$stock", 0}}, 0, 1}}},
+				}},
+			},
+			"prices": bson.A{
+				// Цены вне границ (например, отрицательные у старых записей) не считаются
+				bson.M{"$bucket": bson.M{
+					"groupBy":    "$price",
+					"boundaries": boundaries,
+					"default":    "other",
+					"output":     bson.M{"count": bson.M{"$sum": 1}},
+				}},
+			},
+		}}},
+	}
+
+	cursor, err := s.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate product facets: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Categories []struct {
+			ID    string `bson:"_id"`
+			Count int64  `bson:"count"`
+		} `bson:"categories"`
+		Stock []struct {
+			InStock    int64 `bson:"in_stock"`
+			OutOfStock int64 `bson:"out_of_stock"`
+		} `bson:"stock"`
+		Prices []struct {
+			ID    any   `bson:"_id"`
+			Count int64 `bson:"count"`
+		} `bson:"prices"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, fmt.Errorf("failed to decode product facets: %w", err)
+	}
+
+	facets := &domain.ProductFacets{Categories: []domain.CategoryCount{}}
+	// $bucket не возвращает пустые корзины, поэтому они создаются заранее
+	bucketIndex := make(map[float64]int, len(boundaries)-1)
+	for i := 0; i < len(boundaries)-1; i++ {
+		bucket := domain.PriceBucket{Min: boundaries[i].(float64)}
+		if i < len(priceBoundaries) {
+			bucket.Max = &priceBoundaries[i]
+		}
+		bucketIndex[bucket.Min] = i
+		facets.PriceBuckets = append(facets.PriceBuckets, bucket)
+	}
+	if len(results) == 0 {
+		return facets, nil
+	}
+
+	result := results[0]
+	for _, c := range result.Categories {
+		facets.Categories = append(facets.Categories, domain.CategoryCount{CategoryID: c.ID, Count: c.Count})
+	}
+	if len(result.Stock) > 0 {
+		facets.InStock = result.Stock[0].InStock
+		facets.OutOfStock = result.Stock[0].OutOfStock
+	}
+	for _, p := range result.Prices {
+		if lower, ok := p.ID.(float64); ok {
+			if i, ok := bucketIndex[lower]; ok {
+				facets.PriceBuckets[i].Count = p.Count
+			}
+		}
+	}
+	return facets, nil
+}
+
+func productFilterBSON(filter domain.ProductFilter) bson.M {
+	query := bson.M{}
+	if !filter.IncludeDeleted {
+		notDeleted(query)
+	}
+	switch len(filter.CategoryIDs) {
+	case 0:
+	case 1:
+		query["category_id"] = filter.CategoryIDs[0]
+	default:
+		query["category_id"] = bson.M{"$in": filter.CategoryIDs}
+	}
+
+	price := bson.M{}
+	if filter.MinPrice != nil {
+		price["$gte"] = *filter.MinPrice
+	}
+	if filter.MaxPrice != nil {
+		price["$lte"] = *filter.MaxPrice
+	}
+	if len(price) > 0 {
+		query["price"] = price
+	}
+
+	if filter.InStockOnly {
+		query["stock"] = bson.M{"$gt": 0}
+	}
+	if filter.NamePrefix != "" {
+		query["name"] = primitive.Regex{Pattern: "^" + regexp.QuoteMeta(filter.NamePrefix), Options: "i"}
+	}
+	if r := timeRange(filter.CreatedAfter, filter.CreatedBefore); r != nil {
+		query["created_at"] = r
+	}
+	if r := timeRange(filter.UpdatedAfter, filter.UpdatedBefore); r != nil {
+		query["updated_at"] = r
+	}
+	return query
+}
+
+// timeRange matches times in [after, before), ignoring zero bounds. It
+// returns nil if both are zero.
+func timeRange(after, before time.Time) bson.M {
+	r := bson.M{}
+	if !after.IsZero() {
+		r["$gte"] = after
+	}
+	if !before.IsZero() {
+		r["$lt"] = before
+	}
+	if len(r) == 0 {
+		return nil
+	}
+	return r
+}
+
+func productSortField(sort domain.ProductSort) string {
+	if sort.Field == "" {
+		return domain.ProductFieldCreatedAt
+	}
+	return sort.Field
+}
+
+// productSortValue returns the value of the sort field of p, as stored.
+func productSortValue(p *domain.Product, field string) any {
+	switch field {
+	case domain.ProductFieldUpdatedAt:
+		return p.UpdatedAt
+	case domain.ProductFieldPrice:
+		return p.Price
+	case domain.ProductFieldName:
+		return p.Name
+	case domain.ProductFieldStock:
+		return p.Stock
+	}
+	return p.CreatedAt
+}
+
+// Search finds products whose name or description match query using the
+// text index, most relevant first. query uses the MongoDB $text syntax:
+// words are stemmed, "quoted phrases" must match exactly and -word excludes.
+func (s *MongoProductStore) Search(ctx context.Context, query string, limit, offset int64) (_ []domain.ProductSearchHit, _ int64, err error) {
+	defer productStoreMetrics.Observe("Search", time.Now(), &err)
+
+	filter := notDeleted(bson.M{"$text": bson.M{"$search": query}})
+	score := bson.M{"$meta": "textScore"}
+	findOptions := options.Find().
+		SetProjection(bson.M{"score": score}).
+		SetSort(bson.D{{Key: "score", Value: score}, {Key: "_id", Value: 1}})
 	if limit > 0 {
 		findOptions.SetLimit(limit)
 	}
 	if offset > 0 {
 		findOptions.SetSkip(offset)
 	}
-	findOptions.SetSort(bson.D{{"created_at", -1}})
 
 	totalCount, err := s.collection.CountDocuments(ctx, filter)
 	if err != nil {
@@ -124,18 +625,152 @@ func (s *MongoProductStore) List(ctx context.Context, filter bson.M, limit, offs
 
 	cursor, err := s.collection.Find(ctx, filter, findOptions)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list products: %w", err)
+		return nil, 0, fmt.Errorf("failed to search products: %w", err)
 	}
 	defer cursor.Close(ctx)
 
-	var products []*domain.Product
-	if err = cursor.All(ctx, &products); err != nil {
+	var docs []struct {
+		domain.Product `bson:",inline"`
+		Score          float64 `bson:"score"`
+	}
+	if err = cursor.All(ctx, &docs); err != nil {
 		return nil, 0, fmt.Errorf("failed to decode products: %w", err)
 	}
 
-	if products == nil {
-		products = []*domain.Product{}
+	hits := make([]domain.ProductSearchHit, len(docs))
+	for i := range docs {
+		hits[i] = domain.ProductSearchHit{Product: &docs[i].Product, Score: docs[i].Score}
+	}
+	return hits, totalCount, nil
+}
+
+// DecrementStock takes quantity units from the product only if enough stock
+// is available. The check and the decrement happen in a single conditional
+// update, so concurrent callers can never drive the stock below zero.
+func (s *MongoProductStore) DecrementStock(ctx context.Context, id string, quantity int) (err error) {
+	defer productStoreMetrics.Observe("DecrementStock", time.Now(), &err)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.InvalidIDError(domain.ResourceProduct, id)
+	}
+
+	filter := notDeleted(bson.M{"_id": objID, "stock": bson.M{"$gte": quantity}})
+	err = s.changeStock(ctx, id, filter, -quantity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		count, err := s.collection.CountDocuments(ctx, notDeleted(bson.M{"_id": objID}))
+		if err != nil {
+			return fmt.Errorf("failed to check product: %w", err)
+		}
+		if count == 0 {
+			return domain.NotFoundError(domain.ResourceProduct, id)
+		}
+		return domain.NewError(domain.ErrInsufficientStock, domain.ResourceProduct, id, "insufficient stock for product %s", id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to decrement stock: %w", err)
+	}
+	slog.DebugContext(ctx, "Decremented product stock", "product_id", id, "quantity", quantity)
+	return nil
+}
+
+// IncrementStock also returns stock to soft-deleted products, so that
+// releasing a reservation never fails because a product was deleted since.
+func (s *MongoProductStore) IncrementStock(ctx context.Context, id string, quantity int) (err error) {
+	defer productStoreMetrics.Observe("IncrementStock", time.Now(), &err)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.InvalidIDError(domain.ResourceProduct, id)
+	}
+
+	err = s.changeStock(ctx, id, bson.M{"_id": objID}, quantity)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return domain.NotFoundError(domain.ResourceProduct, id)
 	}
+	if err != nil {
+		return fmt.Errorf("failed to increment stock: %w", err)
+	}
+	slog.DebugContext(ctx, "Incremented product stock", "product_id", id, "quantity", quantity)
+	return nil
+}
+
+// changeStock applies delta to the product matched by filter and records a
+// StockChanged event in the same transaction. It returns
+// mongo.ErrNoDocuments if the filter matched nothing. Reservations change the
+// stock all the time, so only the stock version grows: the version guards
+// the fields that admins edit.
+func (s *MongoProductStore) changeStock(ctx context.Context, id string, filter bson.M, delta int) error {
+	update := bson.M{
+		"$inc": bson.M{"stock": delta, "stock_version": 1},
+		"$set": bson.M{"updated_at": time.Now()},
+	}
+	return s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		var after struct {
+			Stock int `bson:"stock"`
+		}
+		err := s.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().
+			SetProjection(bson.M{"stock": 1}).
+			SetReturnDocument(options.After)).Decode(&after)
+		if err != nil {
+			return err
+		}
+		return s.addEvent(ctx, domain.EventStockChanged, id, domain.StockChangedPayload{
+			ProductID: id,
+			Delta:     delta,
+			Stock:     after.Stock,
+		})
+	})
+}
+
+func (s *MongoProductStore) addEvent(ctx context.Context, eventType, productID string, payload any) error {
+	return addProductEvent(ctx, s.outbox, eventType, productID, payload)
+}
+
+// addProductEvent writes a product event to the outbox. Other stores use it
+// when they change products as a side effect.
+func addProductEvent(ctx context.Context, o *outbox.Store, eventType, productID string, payload any) error {
+	event, err := events.New(domain.EventSource, eventType, productID, payload)
+	if err != nil {
+		return err
+	}
+	return o.Add(ctx, event)
+}
+
+// ReserveStock decrements stock for all items or for none of them: the
+// decrements run in one transaction, which joins the transaction of ctx if
+// there is one.
+func (s *MongoProductStore) ReserveStock(ctx context.Context, items []domain.StockItem) (err error) {
+	defer productStoreMetrics.Observe("ReserveStock", time.Now(), &err)
 
-	return products, totalCount, nil
+	return s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range items {
+			if err := s.DecrementStock(ctx, item.ProductID, item.Quantity); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ReleaseStock returns the items to stock in one transaction, which joins
+// the transaction of ctx if there is one. Products that no longer exist are
+// skipped, since their stock cannot be returned anyway; any other error
+// aborts the whole release.
+func (s *MongoProductStore) ReleaseStock(ctx context.Context, items []domain.StockItem) (err error) {
+	defer productStoreMetrics.Observe("ReleaseStock", time.Now(), &err)
+
+	return s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		for _, item := range items {
+			err := s.IncrementStock(ctx, item.ProductID, item.Quantity)
+			if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidID) {
+				slog.WarnContext(ctx, "Skipped stock of missing product", "product_id", item.ProductID, "quantity", item.Quantity)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
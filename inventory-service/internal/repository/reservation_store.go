@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"ecommerce-microservices/inventory-service/internal/domain"
+	"ecommerce-microservices/pkg/metrics"
+	"ecommerce-microservices/pkg/outbox"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const reservationCollectionName = "reservations"
+
+type MongoReservationStore struct {
+	collection *mongo.Collection
+	outbox     *outbox.Store
+}
+
+var reservationStoreMetrics = metrics.NewStoreMetrics("reservation")
+
+func NewMongoReservationStore(db *mongo.Database) *MongoReservationStore {
+	collection := db.Collection(reservationCollectionName)
+	return &MongoReservationStore{collection: collection, outbox: outbox.NewStore(db)}
+}
+
+// WithTransaction runs fn in one MongoDB transaction. The stock changes of
+// MongoProductStore made with the context fn receives join it, so a
+// reservation and its stock commit or abort together.
+func (s *MongoReservationStore) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return s.outbox.WithTransaction(ctx, fn)
+}
+
+// EnsureIndexes creates the indexes the store relies on. It is safe to call
+// on every startup.
+func (s *MongoReservationStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		// Поиск активных резервов товара перед его окончательным удалением
+		Keys: bson.D{{Key: "items.product_id", Value: 1}, {Key: "status", Value: 1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create reservation indexes: %w", err)
+	}
+	return nil
+}
+
+func (s *MongoReservationStore) Create(ctx context.Context, reservation *domain.Reservation) (err error) {
+	defer reservationStoreMetrics.Observe("Create", time.Now(), &err)
+
+	reservation.CreatedAt = time.Now()
+	reservation.UpdatedAt = time.Now()
+
+	result, err := s.collection.InsertOne(ctx, reservation)
+	if err != nil {
+		return fmt.Errorf("failed to insert reservation: %w", err)
+	}
+
+	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+		reservation.ID = oid
+	}
+	slog.DebugContext(ctx, "Inserted reservation", "reservation_id", reservation.ID.Hex())
+	return nil
+}
+
+func (s *MongoReservationStore) GetByID(ctx context.Context, id string) (_ *domain.Reservation, err error) {
+	defer reservationStoreMetrics.Observe("GetByID", time.Now(), &err)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.InvalidIDError(domain.ResourceReservation, id)
+	}
+
+	var reservation domain.Reservation
+	err = s.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&reservation)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.NotFoundError(domain.ResourceReservation, id)
+		}
+		return nil, fmt.Errorf("failed to find reservation: %w", err)
+	}
+	return &reservation, nil
+}
+
+// TransitionStatus moves the reservation to status to if it is currently in
+// one of the from statuses. The status is part of the filter, so only one
+// concurrent caller can win the transition; the others get
+// domain.ErrInvalidStatus, or domain.ErrReservationReleased if the
+// reservation is already released.
+func (s *MongoReservationStore) TransitionStatus(ctx context.Context, id string, to domain.ReservationStatus, from ...domain.ReservationStatus) (err error) {
+	defer reservationStoreMetrics.Observe("TransitionStatus", time.Now(), &err)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.InvalidIDError(domain.ResourceReservation, id)
+	}
+
+	filter := bson.M{"_id": objID, "status": bson.M{"$in": from}}
+	update := bson.M{
+		"$set": bson.M{
+			"status":     to,
+			"updated_at": time.Now(),
+		},
+	}
+
+	result, err := s.collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update reservation status: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		var current struct {
+			Status domain.ReservationStatus `bson:"status"`
+		}
+		err := s.collection.FindOne(ctx, bson.M{"_id": objID}, options.FindOne().SetProjection(bson.M{"status": 1})).Decode(&current)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return domain.NotFoundError(domain.ResourceReservation, id)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to check reservation: %w", err)
+		}
+		if current.Status == domain.ReservationReleased {
+			return domain.NewError(domain.ErrReservationReleased, domain.ResourceReservation, id, "reservation %s is already released", id)
+		}
+		return domain.NewError(domain.ErrInvalidStatus, domain.ResourceReservation, id, "reservation %s is %s, not in status %v", id, current.Status, from)
+	}
+	slog.DebugContext(ctx, "Updated reservation status", "reservation_id", id, "status", to)
+	return nil
+}
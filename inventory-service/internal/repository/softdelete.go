@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// notDeleted restricts filter to documents that have not been soft-deleted.
+// A null deleted_at also matches documents that have no such field.
+func notDeleted(filter bson.M) bson.M {
+	filter["deleted_at"] = nil
+	return filter
+}
+
+// deletedAtIndex covers the purge of soft-deleted documents. Only deleted
+// documents are indexed.
+func deletedAtIndex() mongo.IndexModel {
+	return mongo.IndexModel{
+		Keys:    bson.D{{Key: "deleted_at", Value: 1}},
+		Options: options.Index().SetPartialFilterExpression(bson.M{"deleted_at": bson.M{"$type": "date"}}),
+	}
+}
@@ -0,0 +1,20 @@
+package repository
+
+import (
+	"ecommerce-microservices/inventory-service/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// versionFilter matches documents at the given version. Documents written
+// before versioning was added have no version field and count as version 0.
+func versionFilter(version int64) any {
+	if version == 0 {
+		return bson.M{"$in": bson.A{0, nil}}
+	}
+	return version
+}
+
+func versionMismatchError(resource, id string, version int64) error {
+	return domain.NewError(domain.ErrVersionMismatch, resource, id, "%s %s was changed after version %d, reload it and retry", resource, id, version)
+}
@@ -0,0 +1,62 @@
+package worker
+
+import (
+	"context"
+	repo "ecommerce-microservices/inventory-service/internal/repository"
+	"log/slog"
+	"time"
+)
+
+// Purger removes soft-deleted products and categories for good once they
+// have been deleted for longer than the retention. Until then they can be
+// restored. Products that active reservations still refer to, and their
+// categories, wait for a later run.
+type Purger struct {
+	productStore  *repo.MongoProductStore
+	categoryStore *repo.MongoCategoryStore
+	retention     time.Duration
+	interval      time.Duration
+}
+
+func NewPurger(ps *repo.MongoProductStore, cs *repo.MongoCategoryStore, retention, interval time.Duration) *Purger {
+	return &Purger{
+		productStore:  ps,
+		categoryStore: cs,
+		retention:     retention,
+		interval:      interval,
+	}
+}
+
+// Run purges once at start and then every interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge(ctx)
+		select {
+		case <-ctx.Done():
+			slog.Info("Purger stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge(ctx context.Context) {
+	before := time.Now().Add(-p.retention)
+	// Сначала товары: категория не должна пропасть раньше своих товаров
+	products, err := p.productStore.Purge(ctx, before)
+	if err != nil {
+		slog.ErrorContext(ctx, "Purger failed to purge products", "error", err)
+		return
+	}
+	categories, err := p.categoryStore.Purge(ctx, before)
+	if err != nil {
+		slog.ErrorContext(ctx, "Purger failed to purge categories", "error", err)
+		return
+	}
+	if products > 0 || categories > 0 {
+		slog.InfoContext(ctx, "Purged deleted items", "products", products, "categories", categories, "deleted_before", before)
+	}
+}
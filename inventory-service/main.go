@@ -4,12 +4,23 @@ import (
 	"context"
 	grpcServer "ecommerce-microservices/inventory-service/internal/delivery/grpc"
 	repo "ecommerce-microservices/inventory-service/internal/repository"
+	"ecommerce-microservices/inventory-service/internal/worker"
 	pb "ecommerce-microservices/inventory-service/pb"
+	"ecommerce-microservices/pkg/events"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/logging"
+	"ecommerce-microservices/pkg/metrics"
+	"ecommerce-microservices/pkg/outbox"
+	"ecommerce-microservices/pkg/rbac"
+	"ecommerce-microservices/pkg/tracing"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -18,11 +29,18 @@ import (
 	"google.golang.org/grpc/reflection"
 )
 
+// outboxPollInterval is how often the outbox relay looks for new events.
+const outboxPollInterval = time.Second
+
+// purgeInterval is how often soft-deleted items past their retention are
+// removed.
+const purgeInterval = time.Hour
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
-	log.Printf("Using fallback for env var %s: %s", key, fallback)
+	slog.Info("Using fallback for env var", "key", key, "value", fallback)
 	return fallback
 }
 
@@ -31,6 +49,14 @@ var (
 )
 
 func main() {
+	if _, err := logging.Setup(logging.Config{
+		Service: "inventory-service",
+		Format:  os.Getenv("LOG_FORMAT"),
+		Level:   os.Getenv("LOG_LEVEL"),
+	}); err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
+	}
+
 	mongoCfg := repo.MongoConfig{
 		Host:     getEnv("MONGO_HOST", "localhost"),
 		Port:     getEnv("MONGO_PORT", "27017"),
@@ -40,18 +66,53 @@ func main() {
 		Timeout:  15 * time.Second,
 	}
 	grpcPort := getEnv("GRPC_PORT", "50051")
+	// Общий с gateway и order-service секрет для подписи identity-токенов
+	identitySigner, err := identity.NewSigner(os.Getenv("INTERNAL_AUTH_SECRET"))
+	if err != nil {
+		logging.Fatal("Invalid INTERNAL_AUTH_SECRET", "error", err)
+	}
+	metricsPort := getEnv("METRICS_PORT", "9091")
+	eventBrokerKind := getEnv("EVENT_BROKER", "memory")
+	natsURL := getEnv("NATS_URL", "nats://localhost:4222")
+
+	// Сколько удалённые товары и категории можно восстановить
+	trashRetention, err := time.ParseDuration(getEnv("TRASH_RETENTION", "720h"))
+	if err != nil || trashRetention <= 0 {
+		logging.Fatal("Invalid TRASH_RETENTION, expected a positive duration such as 720h")
+	}
+
+	sampleRatio, err := strconv.ParseFloat(getEnv("TRACES_SAMPLE_RATIO", "1"), 64)
+	if err != nil || sampleRatio < 0 || sampleRatio > 1 {
+		logging.Fatal("Invalid TRACES_SAMPLE_RATIO, expected a number from 0 to 1")
+	}
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName:  "inventory-service",
+		Exporter:     getEnv("TRACES_EXPORTER", tracing.ExporterNone),
+		OTLPEndpoint: getEnv("TRACES_OTLP_ENDPOINT", ""),
+		FilePath:     getEnv("TRACES_FILE", "traces.json"),
+		SampleRatio:  sampleRatio,
+	})
+	if err != nil {
+		logging.Fatal("Failed to initialize tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
 
-	var err error
 	mongoClient, err = repo.NewMongoConnection(mongoCfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		logging.Fatal("Failed to connect to MongoDB", "error", err)
 	}
 	defer func() {
-		log.Println("Disconnecting MongoDB client...")
+		slog.Info("Disconnecting MongoDB client")
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		if err = mongoClient.Disconnect(ctx); err != nil {
-			log.Printf("Error disconnecting MongoDB client: %v", err)
+			slog.Error("Error disconnecting MongoDB client", "error", err)
 		}
 	}()
 
@@ -59,33 +120,84 @@ func main() {
 
 	productStore := repo.NewMongoProductStore(mongoDB)
 	categoryStore := repo.NewMongoCategoryStore(mongoDB)
+	reservationStore := repo.NewMongoReservationStore(mongoDB)
+	indexCtx, indexCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	err = productStore.EnsureIndexes(indexCtx)
+	indexCancel()
+	if err != nil {
+		logging.Fatal("Failed to create product store indexes", "error", err)
+	}
+	indexCtx, indexCancel = context.WithTimeout(context.Background(), 15*time.Second)
+	err = categoryStore.EnsureIndexes(indexCtx)
+	indexCancel()
+	if err != nil {
+		logging.Fatal("Failed to create category store indexes", "error", err)
+	}
+	indexCtx, indexCancel = context.WithTimeout(context.Background(), 15*time.Second)
+	err = reservationStore.EnsureIndexes(indexCtx)
+	indexCancel()
+	if err != nil {
+		logging.Fatal("Failed to create reservation store indexes", "error", err)
+	}
 
-	inventoryGrpcServer := grpcServer.NewInventoryServer(productStore, categoryStore)
+	eventBroker, err := events.NewBroker(eventBrokerKind, natsURL, "inventory")
+	if err != nil {
+		logging.Fatal("Failed to create event broker", "error", err)
+	}
+	defer eventBroker.Close()
+
+	outboxStore := outbox.NewStore(mongoDB)
+	indexCtx, indexCancel = context.WithTimeout(context.Background(), 15*time.Second)
+	err = outboxStore.EnsureIndexes(indexCtx)
+	indexCancel()
+	if err != nil {
+		logging.Fatal("Failed to create outbox indexes", "error", err)
+	}
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go outbox.NewRelay(outboxStore, eventBroker, outboxPollInterval).Run(workerCtx)
+	go worker.NewPurger(productStore, categoryStore, trashRetention, purgeInterval).Run(workerCtx)
+
+	inventoryGrpcServer := grpcServer.NewInventoryServer(productStore, categoryStore, reservationStore)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
 	if err != nil {
-		log.Fatalf("Failed to listen on port %s: %v", grpcPort, err)
+		logging.Fatal("Failed to listen", "port", grpcPort, "error", err)
 	}
 
-	grpcServer := grpc.NewServer()
+	accessPolicy := grpcServer.MethodPolicy
+	grpcServer := grpc.NewServer(
+		tracing.ServerOption(),
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(), metrics.UnaryServerInterceptor(), identitySigner.UnaryServerInterceptor(), rbac.UnaryServerInterceptor(accessPolicy)),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor(), metrics.StreamServerInterceptor(), identitySigner.StreamServerInterceptor(), rbac.StreamServerInterceptor(accessPolicy)),
+	)
 
 	pb.RegisterInventoryServiceServer(grpcServer, inventoryGrpcServer)
 
 	reflection.Register(grpcServer)
 
 	go func() {
-		log.Printf("Starting Inventory gRPC Service on port %s", grpcPort)
+		slog.Info("Starting Inventory gRPC Service", "port", grpcPort)
 		if err := grpcServer.Serve(lis); err != nil {
-			log.Fatalf("Failed to serve gRPC: %v", err)
+			logging.Fatal("Failed to serve gRPC", "error", err)
+		}
+	}()
+
+	metricsServer := metrics.NewServer(":" + metricsPort)
+	go func() {
+		slog.Info("Starting metrics server", "port", metricsPort)
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal("Failed to serve metrics", "error", err)
 		}
 	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down gRPC server...")
+	slog.Info("Shutting down gRPC server")
 
 	grpcServer.GracefulStop()
+	metricsServer.Close()
 
-	log.Println("Server exiting")
+	slog.Info("Server exiting")
 }
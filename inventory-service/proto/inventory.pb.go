@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -23,17 +24,243 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
-// --- Сообщения для Продуктов ---
+// Поля сортировки списка товаров. По умолчанию - created_at
+type ProductSortField int32
+
+const (
+	ProductSortField_PRODUCT_SORT_FIELD_UNSPECIFIED ProductSortField = 0
+	ProductSortField_PRODUCT_SORT_FIELD_CREATED_AT  ProductSortField = 1
+	ProductSortField_PRODUCT_SORT_FIELD_UPDATED_AT  ProductSortField = 2
+	ProductSortField_PRODUCT_SORT_FIELD_PRICE       ProductSortField = 3
+	ProductSortField_PRODUCT_SORT_FIELD_NAME        ProductSortField = 4
+	ProductSortField_PRODUCT_SORT_FIELD_STOCK       ProductSortField = 5
+)
+
+// Enum value maps for ProductSortField.
+var (
+	ProductSortField_name = map[int32]string{
+		0: "PRODUCT_SORT_FIELD_UNSPECIFIED",
+		1: "PRODUCT_SORT_FIELD_CREATED_AT",
+		2: "PRODUCT_SORT_FIELD_UPDATED_AT",
+		3: "PRODUCT_SORT_FIELD_PRICE",
+		4: "PRODUCT_SORT_FIELD_NAME",
+		5: "PRODUCT_SORT_FIELD_STOCK",
+	}
+	ProductSortField_value = map[string]int32{
+		"PRODUCT_SORT_FIELD_UNSPECIFIED": 0,
+		"PRODUCT_SORT_FIELD_CREATED_AT":  1,
+		"PRODUCT_SORT_FIELD_UPDATED_AT":  2,
+		"PRODUCT_SORT_FIELD_PRICE":       3,
+		"PRODUCT_SORT_FIELD_NAME":        4,
+		"PRODUCT_SORT_FIELD_STOCK":       5,
+	}
+)
+
+func (x ProductSortField) Enum() *ProductSortField {
+	p := new(ProductSortField)
+	*p = x
+	return p
+}
+
+func (x ProductSortField) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProductSortField) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_service_proto_inventory_proto_enumTypes[0].Descriptor()
+}
+
+func (ProductSortField) Type() protoreflect.EnumType {
+	return &file_inventory_service_proto_inventory_proto_enumTypes[0]
+}
+
+func (x ProductSortField) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProductSortField.Descriptor instead.
+func (ProductSortField) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{0}
+}
+
+// По умолчанию даты сортируются по убыванию, остальные поля - по возрастанию
+type SortOrder int32
+
+const (
+	SortOrder_SORT_ORDER_UNSPECIFIED SortOrder = 0
+	SortOrder_SORT_ORDER_ASC         SortOrder = 1
+	SortOrder_SORT_ORDER_DESC        SortOrder = 2
+)
+
+// Enum value maps for SortOrder.
+var (
+	SortOrder_name = map[int32]string{
+		0: "SORT_ORDER_UNSPECIFIED",
+		1: "SORT_ORDER_ASC",
+		2: "SORT_ORDER_DESC",
+	}
+	SortOrder_value = map[string]int32{
+		"SORT_ORDER_UNSPECIFIED": 0,
+		"SORT_ORDER_ASC":         1,
+		"SORT_ORDER_DESC":        2,
+	}
+)
+
+func (x SortOrder) Enum() *SortOrder {
+	p := new(SortOrder)
+	*p = x
+	return p
+}
+
+func (x SortOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SortOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_service_proto_inventory_proto_enumTypes[1].Descriptor()
+}
+
+func (SortOrder) Type() protoreflect.EnumType {
+	return &file_inventory_service_proto_inventory_proto_enumTypes[1]
+}
+
+func (x SortOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SortOrder.Descriptor instead.
+func (SortOrder) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{1}
+}
+
+// Что делать с товарами и подкатегориями удаляемой категории
+type DeleteCategoryMode int32
+
+const (
+	// То же, что REJECT
+	DeleteCategoryMode_DELETE_CATEGORY_MODE_UNSPECIFIED DeleteCategoryMode = 0
+	// Непустая категория не удаляется (FAILED_PRECONDITION)
+	DeleteCategoryMode_DELETE_CATEGORY_MODE_REJECT DeleteCategoryMode = 1
+	// Товары и прямые подкатегории переносятся в reassign_to_category_id
+	DeleteCategoryMode_DELETE_CATEGORY_MODE_REASSIGN DeleteCategoryMode = 2
+	// Категория, все подкатегории и их товары помечаются удалёнными
+	DeleteCategoryMode_DELETE_CATEGORY_MODE_CASCADE DeleteCategoryMode = 3
+)
+
+// Enum value maps for DeleteCategoryMode.
+var (
+	DeleteCategoryMode_name = map[int32]string{
+		0: "DELETE_CATEGORY_MODE_UNSPECIFIED",
+		1: "DELETE_CATEGORY_MODE_REJECT",
+		2: "DELETE_CATEGORY_MODE_REASSIGN",
+		3: "DELETE_CATEGORY_MODE_CASCADE",
+	}
+	DeleteCategoryMode_value = map[string]int32{
+		"DELETE_CATEGORY_MODE_UNSPECIFIED": 0,
+		"DELETE_CATEGORY_MODE_REJECT":      1,
+		"DELETE_CATEGORY_MODE_REASSIGN":    2,
+		"DELETE_CATEGORY_MODE_CASCADE":     3,
+	}
+)
+
+func (x DeleteCategoryMode) Enum() *DeleteCategoryMode {
+	p := new(DeleteCategoryMode)
+	*p = x
+	return p
+}
+
+func (x DeleteCategoryMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DeleteCategoryMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_service_proto_inventory_proto_enumTypes[2].Descriptor()
+}
+
+func (DeleteCategoryMode) Type() protoreflect.EnumType {
+	return &file_inventory_service_proto_inventory_proto_enumTypes[2]
+}
+
+func (x DeleteCategoryMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DeleteCategoryMode.Descriptor instead.
+func (DeleteCategoryMode) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{2}
+}
+
+// Сообщения для Уменьшения Стока
+type ReservationStatus int32
+
+const (
+	ReservationStatus_RESERVATION_STATUS_UNSPECIFIED ReservationStatus = 0
+	ReservationStatus_RESERVED                       ReservationStatus = 1
+	ReservationStatus_COMMITTED                      ReservationStatus = 2
+	ReservationStatus_RELEASED                       ReservationStatus = 3
+)
+
+// Enum value maps for ReservationStatus.
+var (
+	ReservationStatus_name = map[int32]string{
+		0: "RESERVATION_STATUS_UNSPECIFIED",
+		1: "RESERVED",
+		2: "COMMITTED",
+		3: "RELEASED",
+	}
+	ReservationStatus_value = map[string]int32{
+		"RESERVATION_STATUS_UNSPECIFIED": 0,
+		"RESERVED":                       1,
+		"COMMITTED":                      2,
+		"RELEASED":                       3,
+	}
+)
+
+func (x ReservationStatus) Enum() *ReservationStatus {
+	p := new(ReservationStatus)
+	*p = x
+	return p
+}
+
+func (x ReservationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReservationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_inventory_service_proto_inventory_proto_enumTypes[3].Descriptor()
+}
+
+func (ReservationStatus) Type() protoreflect.EnumType {
+	return &file_inventory_service_proto_inventory_proto_enumTypes[3]
+}
+
+func (x ReservationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReservationStatus.Descriptor instead.
+func (ReservationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{3}
+}
+
+// Сообщения для Продуктов
 type Product struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
-	CategoryId    string                 `protobuf:"bytes,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price       float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Stock       int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	CategoryId  string                 `protobuf:"bytes,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	CreatedAt   *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt   *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// Увеличивается при каждом изменении через API; резервирование и возврат
+	// стока версию не меняют
+	Version int64 `protobuf:"varint,9,opt,name=version,proto3" json:"version,omitempty"`
+	// Задан только у удалённых товаров
+	DeletedAt *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	// Увеличивается при каждом изменении стока, в том числе резервированием
+	StockVersion  int64 `protobuf:"varint,11,opt,name=stock_version,json=stockVersion,proto3" json:"stock_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -124,6 +351,27 @@ func (x *Product) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Product) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Product) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+func (x *Product) GetStockVersion() int64 {
+	if x != nil {
+		return x.StockVersion
+	}
+	return 0
+}
+
 type CreateProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
@@ -201,10 +449,12 @@ func (x *CreateProductRequest) GetCategoryId() string {
 }
 
 type GetProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Находить и удалённые товары. Только для catalog_admin
+	IncludeDeleted bool `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *GetProductRequest) Reset() {
@@ -244,14 +494,30 @@ func (x *GetProductRequest) GetId() string {
 	return ""
 }
 
+func (x *GetProductRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
 type UpdateProductRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Description   string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
-	Price         float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
-	Stock         int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
-	CategoryId    string                 `protobuf:"bytes,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Description string                 `protobuf:"bytes,3,opt,name=description,proto3" json:"description,omitempty"`
+	Price       float64                `protobuf:"fixed64,4,opt,name=price,proto3" json:"price,omitempty"`
+	Stock       int32                  `protobuf:"varint,5,opt,name=stock,proto3" json:"stock,omitempty"`
+	CategoryId  string                 `protobuf:"bytes,6,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	// Версия, которую видел клиент. Если товар с тех пор изменился,
+	// обновление отклоняется с ABORTED
+	Version *int64 `protobuf:"varint,7,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	// Поля, которые нужно изменить: name, description, price, stock,
+	// category_id. Без маски обновляются все поля
+	UpdateMask *fieldmaskpb.FieldMask `protobuf:"bytes,8,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
+	// Версия стока, которую видел клиент. Обязательна, если меняется stock:
+	// сток, зарезервированный после чтения, не перезаписывается
+	StockVersion  *int64 `protobuf:"varint,9,opt,name=stock_version,json=stockVersion,proto3,oneof" json:"stock_version,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -328,6 +594,28 @@ func (x *UpdateProductRequest) GetCategoryId() string {
 	return ""
 }
 
+func (x *UpdateProductRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+func (x *UpdateProductRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+func (x *UpdateProductRequest) GetStockVersion() int64 {
+	if x != nil && x.StockVersion != nil {
+		return *x.StockVersion
+	}
+	return 0
+}
+
+// Товар помечается удалённым и удаляется окончательно после срока хранения
 type DeleteProductRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -372,18 +660,86 @@ func (x *DeleteProductRequest) GetId() string {
 	return ""
 }
 
+type RestoreProductRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreProductRequest) Reset() {
+	*x = RestoreProductRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreProductRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreProductRequest) ProtoMessage() {}
+
+func (x *RestoreProductRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreProductRequest.ProtoReflect.Descriptor instead.
+func (*RestoreProductRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RestoreProductRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
 type ListProductsRequest struct {
-	state            protoimpl.MessageState `protogen:"open.v1"`
-	CategoryIdFilter string                 `protobuf:"bytes,1,opt,name=category_id_filter,json=categoryIdFilter,proto3" json:"category_id_filter,omitempty"`
-	PageSize         int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	PageNumber       int32                  `protobuf:"varint,3,opt,name=page_number,json=pageNumber,proto3" json:"page_number,omitempty"`
-	unknownFields    protoimpl.UnknownFields
-	sizeCache        protoimpl.SizeCache
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Устарело, используйте category_ids. Объединяется с category_ids
+	CategoryIdFilter string   `protobuf:"bytes,1,opt,name=category_id_filter,json=categoryIdFilter,proto3" json:"category_id_filter,omitempty"`
+	PageSize         int32    `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageNumber       int32    `protobuf:"varint,3,opt,name=page_number,json=pageNumber,proto3" json:"page_number,omitempty"`
+	CategoryIds      []string `protobuf:"bytes,4,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	// Границы цены включительно
+	MinPrice    *float64 `protobuf:"fixed64,5,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice    *float64 `protobuf:"fixed64,6,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	InStockOnly bool     `protobuf:"varint,7,opt,name=in_stock_only,json=inStockOnly,proto3" json:"in_stock_only,omitempty"`
+	// Начало названия, без учета регистра
+	NamePrefix string `protobuf:"bytes,8,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	// *_after включительно, *_before не включительно
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	UpdatedAfter  *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	SortBy        ProductSortField       `protobuf:"varint,13,opt,name=sort_by,json=sortBy,proto3,enum=inventory.ProductSortField" json:"sort_by,omitempty"`
+	SortOrder     SortOrder              `protobuf:"varint,14,opt,name=sort_order,json=sortOrder,proto3,enum=inventory.SortOrder" json:"sort_order,omitempty"`
+	// next_page_token предыдущего ответа. Фильтры и сортировка должны совпадать,
+	// page_number при этом не задается
+	PageToken string `protobuf:"bytes,15,opt,name=page_token,json=pageToken,proto3" json:"page_token,omitempty"`
+	// Не считать total_count: подсчет проходит по всем найденным товарам
+	SkipTotalCount bool `protobuf:"varint,16,opt,name=skip_total_count,json=skipTotalCount,proto3" json:"skip_total_count,omitempty"`
+	// Учитывать и подкатегории category_ids на любой глубине
+	IncludeDescendants bool `protobuf:"varint,17,opt,name=include_descendants,json=includeDescendants,proto3" json:"include_descendants,omitempty"`
+	// Показывать и удалённые товары. Только для catalog_admin
+	IncludeDeleted bool `protobuf:"varint,18,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ListProductsRequest) Reset() {
 	*x = ListProductsRequest{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[5]
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -395,7 +751,7 @@ func (x *ListProductsRequest) String() string {
 func (*ListProductsRequest) ProtoMessage() {}
 
 func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[5]
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,7 +764,7 @@ func (x *ListProductsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ListProductsRequest.ProtoReflect.Descriptor instead.
 func (*ListProductsRequest) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{5}
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *ListProductsRequest) GetCategoryIdFilter() string {
@@ -432,72 +788,132 @@ func (x *ListProductsRequest) GetPageNumber() int32 {
 	return 0
 }
 
-type ProductResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *ListProductsRequest) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
 }
 
-func (x *ProductResponse) Reset() {
-	*x = ProductResponse{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[6]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *ListProductsRequest) GetMinPrice() float64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
 }
 
-func (x *ProductResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *ListProductsRequest) GetMaxPrice() float64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
 }
 
-func (*ProductResponse) ProtoMessage() {}
+func (x *ListProductsRequest) GetInStockOnly() bool {
+	if x != nil {
+		return x.InStockOnly
+	}
+	return false
+}
 
-func (x *ProductResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[6]
+func (x *ListProductsRequest) GetNamePrefix() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.NamePrefix
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
-func (*ProductResponse) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{6}
+func (x *ListProductsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
 }
 
-func (x *ProductResponse) GetProduct() *Product {
+func (x *ListProductsRequest) GetCreatedBefore() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Product
+		return x.CreatedBefore
 	}
 	return nil
 }
 
-type ListProductsResponse struct {
+func (x *ListProductsRequest) GetUpdatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return nil
+}
+
+func (x *ListProductsRequest) GetUpdatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return nil
+}
+
+func (x *ListProductsRequest) GetSortBy() ProductSortField {
+	if x != nil {
+		return x.SortBy
+	}
+	return ProductSortField_PRODUCT_SORT_FIELD_UNSPECIFIED
+}
+
+func (x *ListProductsRequest) GetSortOrder() SortOrder {
+	if x != nil {
+		return x.SortOrder
+	}
+	return SortOrder_SORT_ORDER_UNSPECIFIED
+}
+
+func (x *ListProductsRequest) GetPageToken() string {
+	if x != nil {
+		return x.PageToken
+	}
+	return ""
+}
+
+func (x *ListProductsRequest) GetSkipTotalCount() bool {
+	if x != nil {
+		return x.SkipTotalCount
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetIncludeDescendants() bool {
+	if x != nil {
+		return x.IncludeDescendants
+	}
+	return false
+}
+
+func (x *ListProductsRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type GetProductsByIDsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Products      []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
-	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	Ids           []string               `protobuf:"bytes,1,rep,name=ids,proto3" json:"ids,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListProductsResponse) Reset() {
-	*x = ListProductsResponse{}
+func (x *GetProductsByIDsRequest) Reset() {
+	*x = GetProductsByIDsRequest{}
 	mi := &file_inventory_service_proto_inventory_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListProductsResponse) String() string {
+func (x *GetProductsByIDsRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListProductsResponse) ProtoMessage() {}
+func (*GetProductsByIDsRequest) ProtoMessage() {}
 
-func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+func (x *GetProductsByIDsRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_inventory_service_proto_inventory_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -509,51 +925,1308 @@ func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
-func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use GetProductsByIDsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsRequest) Descriptor() ([]byte, []int) {
 	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *ListProductsResponse) GetProducts() []*Product {
+func (x *GetProductsByIDsRequest) GetIds() []string {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+type GetProductsByIDsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	// ID, которые не найдены или имеют неверный формат
+	MissingIds    []string `protobuf:"bytes,2,rep,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductsByIDsResponse) Reset() {
+	*x = GetProductsByIDsResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductsByIDsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductsByIDsResponse) ProtoMessage() {}
+
+func (x *GetProductsByIDsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductsByIDsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductsByIDsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetProductsByIDsResponse) GetProducts() []*Product {
+	if x != nil {
+		return x.Products
+	}
+	return nil
+}
+
+func (x *GetProductsByIDsResponse) GetMissingIds() []string {
+	if x != nil {
+		return x.MissingIds
+	}
+	return nil
+}
+
+type ProductResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Product       *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductResponse) Reset() {
+	*x = ProductResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductResponse) ProtoMessage() {}
+
+func (x *ProductResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductResponse.ProtoReflect.Descriptor instead.
+func (*ProductResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ProductResponse) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+type ListProductsResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Products []*Product             `protobuf:"bytes,1,rep,name=products,proto3" json:"products,omitempty"`
+	// Не задан, если запрошен skip_total_count
+	TotalCount *int64 `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3,oneof" json:"total_count,omitempty"`
+	// Пустой на последней странице
+	NextPageToken string `protobuf:"bytes,3,opt,name=next_page_token,json=nextPageToken,proto3" json:"next_page_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListProductsResponse) Reset() {
+	*x = ListProductsResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListProductsResponse) ProtoMessage() {}
+
+func (x *ListProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListProductsResponse.ProtoReflect.Descriptor instead.
+func (*ListProductsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ListProductsResponse) GetProducts() []*Product {
 	if x != nil {
 		return x.Products
 	}
 	return nil
 }
 
-func (x *ListProductsResponse) GetTotalCount() int64 {
+func (x *ListProductsResponse) GetTotalCount() int64 {
+	if x != nil && x.TotalCount != nil {
+		return *x.TotalCount
+	}
+	return 0
+}
+
+func (x *ListProductsResponse) GetNextPageToken() string {
+	if x != nil {
+		return x.NextPageToken
+	}
+	return ""
+}
+
+// Те же фильтры, что и в ListProductsRequest
+type ProductFilter struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryIds   []string               `protobuf:"bytes,1,rep,name=category_ids,json=categoryIds,proto3" json:"category_ids,omitempty"`
+	MinPrice      *float64               `protobuf:"fixed64,2,opt,name=min_price,json=minPrice,proto3,oneof" json:"min_price,omitempty"`
+	MaxPrice      *float64               `protobuf:"fixed64,3,opt,name=max_price,json=maxPrice,proto3,oneof" json:"max_price,omitempty"`
+	InStockOnly   bool                   `protobuf:"varint,4,opt,name=in_stock_only,json=inStockOnly,proto3" json:"in_stock_only,omitempty"`
+	NamePrefix    string                 `protobuf:"bytes,5,opt,name=name_prefix,json=namePrefix,proto3" json:"name_prefix,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`
+	UpdatedAfter  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=updated_after,json=updatedAfter,proto3" json:"updated_after,omitempty"`
+	UpdatedBefore *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=updated_before,json=updatedBefore,proto3" json:"updated_before,omitempty"`
+	// Учитывать и подкатегории category_ids на любой глубине
+	IncludeDescendants bool `protobuf:"varint,10,opt,name=include_descendants,json=includeDescendants,proto3" json:"include_descendants,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *ProductFilter) Reset() {
+	*x = ProductFilter{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductFilter) ProtoMessage() {}
+
+func (x *ProductFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductFilter.ProtoReflect.Descriptor instead.
+func (*ProductFilter) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ProductFilter) GetCategoryIds() []string {
+	if x != nil {
+		return x.CategoryIds
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetMinPrice() float64 {
+	if x != nil && x.MinPrice != nil {
+		return *x.MinPrice
+	}
+	return 0
+}
+
+func (x *ProductFilter) GetMaxPrice() float64 {
+	if x != nil && x.MaxPrice != nil {
+		return *x.MaxPrice
+	}
+	return 0
+}
+
+func (x *ProductFilter) GetInStockOnly() bool {
+	if x != nil {
+		return x.InStockOnly
+	}
+	return false
+}
+
+func (x *ProductFilter) GetNamePrefix() string {
+	if x != nil {
+		return x.NamePrefix
+	}
+	return ""
+}
+
+func (x *ProductFilter) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetUpdatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAfter
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetUpdatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedBefore
+	}
+	return nil
+}
+
+func (x *ProductFilter) GetIncludeDescendants() bool {
+	if x != nil {
+		return x.IncludeDescendants
+	}
+	return false
+}
+
+type GetProductFacetsRequest struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Filter *ProductFilter         `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// Границы ценовых корзин по возрастанию: [0, b1), [b1, b2), ..., [bn, +inf).
+	// Если не заданы, используются границы по умолчанию
+	PriceBoundaries []float64 `protobuf:"fixed64,2,rep,packed,name=price_boundaries,json=priceBoundaries,proto3" json:"price_boundaries,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *GetProductFacetsRequest) Reset() {
+	*x = GetProductFacetsRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductFacetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductFacetsRequest) ProtoMessage() {}
+
+func (x *GetProductFacetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductFacetsRequest.ProtoReflect.Descriptor instead.
+func (*GetProductFacetsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *GetProductFacetsRequest) GetFilter() *ProductFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *GetProductFacetsRequest) GetPriceBoundaries() []float64 {
+	if x != nil {
+		return x.PriceBoundaries
+	}
+	return nil
+}
+
+type CategoryFacet struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	CategoryId    string                 `protobuf:"bytes,1,opt,name=category_id,json=categoryId,proto3" json:"category_id,omitempty"`
+	Count         int64                  `protobuf:"varint,2,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryFacet) Reset() {
+	*x = CategoryFacet{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryFacet) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryFacet) ProtoMessage() {}
+
+func (x *CategoryFacet) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryFacet.ProtoReflect.Descriptor instead.
+func (*CategoryFacet) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *CategoryFacet) GetCategoryId() string {
+	if x != nil {
+		return x.CategoryId
+	}
+	return ""
+}
+
+func (x *CategoryFacet) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type PriceBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Нижняя граница включительно
+	Min float64 `protobuf:"fixed64,1,opt,name=min,proto3" json:"min,omitempty"`
+	// Верхняя граница не включительно, не задана у последней корзины
+	Max           *float64 `protobuf:"fixed64,2,opt,name=max,proto3,oneof" json:"max,omitempty"`
+	Count         int64    `protobuf:"varint,3,opt,name=count,proto3" json:"count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceBucket) Reset() {
+	*x = PriceBucket{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceBucket) ProtoMessage() {}
+
+func (x *PriceBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceBucket.ProtoReflect.Descriptor instead.
+func (*PriceBucket) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PriceBucket) GetMin() float64 {
+	if x != nil {
+		return x.Min
+	}
+	return 0
+}
+
+func (x *PriceBucket) GetMax() float64 {
+	if x != nil && x.Max != nil {
+		return *x.Max
+	}
+	return 0
+}
+
+func (x *PriceBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type GetProductFacetsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// По убыванию count
+	Categories      []*CategoryFacet `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	InStockCount    int64            `protobuf:"varint,2,opt,name=in_stock_count,json=inStockCount,proto3" json:"in_stock_count,omitempty"`
+	OutOfStockCount int64            `protobuf:"varint,3,opt,name=out_of_stock_count,json=outOfStockCount,proto3" json:"out_of_stock_count,omitempty"`
+	// По возрастанию цены, включая пустые корзины
+	PriceBuckets  []*PriceBucket `protobuf:"bytes,4,rep,name=price_buckets,json=priceBuckets,proto3" json:"price_buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetProductFacetsResponse) Reset() {
+	*x = GetProductFacetsResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetProductFacetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetProductFacetsResponse) ProtoMessage() {}
+
+func (x *GetProductFacetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetProductFacetsResponse.ProtoReflect.Descriptor instead.
+func (*GetProductFacetsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetProductFacetsResponse) GetCategories() []*CategoryFacet {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *GetProductFacetsResponse) GetInStockCount() int64 {
+	if x != nil {
+		return x.InStockCount
+	}
+	return 0
+}
+
+func (x *GetProductFacetsResponse) GetOutOfStockCount() int64 {
+	if x != nil {
+		return x.OutOfStockCount
+	}
+	return 0
+}
+
+func (x *GetProductFacetsResponse) GetPriceBuckets() []*PriceBucket {
+	if x != nil {
+		return x.PriceBuckets
+	}
+	return nil
+}
+
+// Полнотекстовый поиск по name и description
+type SearchProductsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	PageSize      int32                  `protobuf:"varint,2,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	PageNumber    int32                  `protobuf:"varint,3,opt,name=page_number,json=pageNumber,proto3" json:"page_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsRequest) Reset() {
+	*x = SearchProductsRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsRequest) ProtoMessage() {}
+
+func (x *SearchProductsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsRequest.ProtoReflect.Descriptor instead.
+func (*SearchProductsRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SearchProductsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchProductsRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *SearchProductsRequest) GetPageNumber() int32 {
+	if x != nil {
+		return x.PageNumber
+	}
+	return 0
+}
+
+type ProductSearchHit struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Product *Product               `protobuf:"bytes,1,opt,name=product,proto3" json:"product,omitempty"`
+	// Релевантность по текстовому индексу, больше - лучше
+	Score float64 `protobuf:"fixed64,2,opt,name=score,proto3" json:"score,omitempty"`
+	// Поле (name, description) -> HTML-экранированный текст, в котором
+	// найденные слова обернуты в <em></em>. Только поля с совпадениями
+	Highlights    map[string]string `protobuf:"bytes,3,rep,name=highlights,proto3" json:"highlights,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProductSearchHit) Reset() {
+	*x = ProductSearchHit{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProductSearchHit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProductSearchHit) ProtoMessage() {}
+
+func (x *ProductSearchHit) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProductSearchHit.ProtoReflect.Descriptor instead.
+func (*ProductSearchHit) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ProductSearchHit) GetProduct() *Product {
+	if x != nil {
+		return x.Product
+	}
+	return nil
+}
+
+func (x *ProductSearchHit) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+func (x *ProductSearchHit) GetHighlights() map[string]string {
+	if x != nil {
+		return x.Highlights
+	}
+	return nil
+}
+
+type SearchProductsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Hits          []*ProductSearchHit    `protobuf:"bytes,1,rep,name=hits,proto3" json:"hits,omitempty"`
+	TotalCount    int64                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchProductsResponse) Reset() {
+	*x = SearchProductsResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchProductsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchProductsResponse) ProtoMessage() {}
+
+func (x *SearchProductsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchProductsResponse.ProtoReflect.Descriptor instead.
+func (*SearchProductsResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SearchProductsResponse) GetHits() []*ProductSearchHit {
+	if x != nil {
+		return x.Hits
+	}
+	return nil
+}
+
+func (x *SearchProductsResponse) GetTotalCount() int64 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// --- Сообщения для Категорий ---
+type Category struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Version   int64                  `protobuf:"varint,5,opt,name=version,proto3" json:"version,omitempty"`
+	// Пустой у корневых категорий
+	ParentId string `protobuf:"bytes,6,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// ID предков от корня до родителя
+	Path []string `protobuf:"bytes,7,rep,name=path,proto3" json:"path,omitempty"`
+	// Уникален среди всех категорий, в том числе удалённых
+	Slug string `protobuf:"bytes,8,opt,name=slug,proto3" json:"slug,omitempty"`
+	// Задан только у удалённых категорий
+	DeletedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=deleted_at,json=deletedAt,proto3" json:"deleted_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Category) Reset() {
+	*x = Category{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Category) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Category) ProtoMessage() {}
+
+func (x *Category) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Category.ProtoReflect.Descriptor instead.
+func (*Category) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *Category) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Category) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Category) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Category) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Category) GetVersion() int64 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Category) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *Category) GetPath() []string {
+	if x != nil {
+		return x.Path
+	}
+	return nil
+}
+
+func (x *Category) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+func (x *Category) GetDeletedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeletedAt
+	}
+	return nil
+}
+
+type CreateCategoryRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Name     string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	ParentId string                 `protobuf:"bytes,2,opt,name=parent_id,json=parentId,proto3" json:"parent_id,omitempty"`
+	// По умолчанию строится из name
+	Slug          string `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateCategoryRequest) Reset() {
+	*x = CreateCategoryRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateCategoryRequest) ProtoMessage() {}
+
+func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CreateCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateCategoryRequest) GetParentId() string {
+	if x != nil {
+		return x.ParentId
+	}
+	return ""
+}
+
+func (x *CreateCategoryRequest) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type GetCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// Находить и удалённые категории. Только для catalog_admin
+	IncludeDeleted bool `protobuf:"varint,2,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetCategoryRequest) Reset() {
+	*x = GetCategoryRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCategoryRequest) ProtoMessage() {}
+
+func (x *GetCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCategoryRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *GetCategoryRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+type UpdateCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// Версия, которую видел клиент, как в UpdateProductRequest
+	Version *int64 `protobuf:"varint,3,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	// Не задан - родитель не меняется, пустой - категория становится корневой.
+	// Подкатегории переносятся вместе с категорией
+	ParentId *string `protobuf:"bytes,4,opt,name=parent_id,json=parentId,proto3,oneof" json:"parent_id,omitempty"`
+	// Не задан - slug не меняется
+	Slug          *string `protobuf:"bytes,5,opt,name=slug,proto3,oneof" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateCategoryRequest) Reset() {
+	*x = UpdateCategoryRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateCategoryRequest) ProtoMessage() {}
+
+func (x *UpdateCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateCategoryRequest.ProtoReflect.Descriptor instead.
+func (*UpdateCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *UpdateCategoryRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateCategoryRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+func (x *UpdateCategoryRequest) GetParentId() string {
+	if x != nil && x.ParentId != nil {
+		return *x.ParentId
+	}
+	return ""
+}
+
+func (x *UpdateCategoryRequest) GetSlug() string {
+	if x != nil && x.Slug != nil {
+		return *x.Slug
+	}
+	return ""
+}
+
+type DeleteCategoryRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Mode  DeleteCategoryMode     `protobuf:"varint,2,opt,name=mode,proto3,enum=inventory.DeleteCategoryMode" json:"mode,omitempty"`
+	// Обязателен для DELETE_CATEGORY_MODE_REASSIGN
+	ReassignToCategoryId string `protobuf:"bytes,3,opt,name=reassign_to_category_id,json=reassignToCategoryId,proto3" json:"reassign_to_category_id,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *DeleteCategoryRequest) Reset() {
+	*x = DeleteCategoryRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteCategoryRequest) ProtoMessage() {}
+
+func (x *DeleteCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteCategoryRequest.ProtoReflect.Descriptor instead.
+func (*DeleteCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeleteCategoryRequest) GetMode() DeleteCategoryMode {
+	if x != nil {
+		return x.Mode
+	}
+	return DeleteCategoryMode_DELETE_CATEGORY_MODE_UNSPECIFIED
+}
+
+func (x *DeleteCategoryRequest) GetReassignToCategoryId() string {
+	if x != nil {
+		return x.ReassignToCategoryId
+	}
+	return ""
+}
+
+type ListCategoriesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Можно добавить пагинацию
+	// Показывать и удалённые категории. Только для catalog_admin
+	IncludeDeleted bool `protobuf:"varint,1,opt,name=include_deleted,json=includeDeleted,proto3" json:"include_deleted,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ListCategoriesRequest) Reset() {
+	*x = ListCategoriesRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListCategoriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListCategoriesRequest) ProtoMessage() {}
+
+func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
+func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListCategoriesRequest) GetIncludeDeleted() bool {
+	if x != nil {
+		return x.IncludeDeleted
+	}
+	return false
+}
+
+// Восстанавливает категорию вместе с подкатегориями и товарами,
+// удалёнными вместе с ней
+type RestoreCategoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreCategoryRequest) Reset() {
+	*x = RestoreCategoryRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreCategoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreCategoryRequest) ProtoMessage() {}
+
+func (x *RestoreCategoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreCategoryRequest.ProtoReflect.Descriptor instead.
+func (*RestoreCategoryRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *RestoreCategoryRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+// Элемент пути от корня до категории
+type Breadcrumb struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Breadcrumb) Reset() {
+	*x = Breadcrumb{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Breadcrumb) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Breadcrumb) ProtoMessage() {}
+
+func (x *Breadcrumb) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Breadcrumb.ProtoReflect.Descriptor instead.
+func (*Breadcrumb) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *Breadcrumb) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Breadcrumb) GetSlug() string {
+	if x != nil {
+		return x.Slug
+	}
+	return ""
+}
+
+type CategoryResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Category *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	// Только в GetCategoryByID: от корня до самой категории включительно
+	Breadcrumbs   []*Breadcrumb `protobuf:"bytes,2,rep,name=breadcrumbs,proto3" json:"breadcrumbs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryResponse) Reset() {
+	*x = CategoryResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryResponse) ProtoMessage() {}
+
+func (x *CategoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryResponse.ProtoReflect.Descriptor instead.
+func (*CategoryResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *CategoryResponse) GetCategory() *Category {
+	if x != nil {
+		return x.Category
+	}
+	return nil
+}
+
+func (x *CategoryResponse) GetBreadcrumbs() []*Breadcrumb {
 	if x != nil {
-		return x.TotalCount
+		return x.Breadcrumbs
 	}
-	return 0
+	return nil
 }
 
-// --- Сообщения для Категорий ---
-type Category struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+type GetCategoryTreeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Не задан - все дерево
+	RootId string `protobuf:"bytes,1,opt,name=root_id,json=rootId,proto3" json:"root_id,omitempty"`
+	// Глубина под корнем, 0 - без ограничения
+	MaxDepth      int32 `protobuf:"varint,2,opt,name=max_depth,json=maxDepth,proto3" json:"max_depth,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *Category) Reset() {
-	*x = Category{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[8]
+func (x *GetCategoryTreeRequest) Reset() {
+	*x = GetCategoryTreeRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Category) String() string {
+func (x *GetCategoryTreeRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Category) ProtoMessage() {}
+func (*GetCategoryTreeRequest) ProtoMessage() {}
 
-func (x *Category) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[8]
+func (x *GetCategoryTreeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -564,61 +2237,100 @@ func (x *Category) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Category.ProtoReflect.Descriptor instead.
-func (*Category) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use GetCategoryTreeRequest.ProtoReflect.Descriptor instead.
+func (*GetCategoryTreeRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{28}
 }
 
-func (x *Category) GetId() string {
+func (x *GetCategoryTreeRequest) GetRootId() string {
 	if x != nil {
-		return x.Id
+		return x.RootId
 	}
 	return ""
 }
 
-func (x *Category) GetName() string {
+func (x *GetCategoryTreeRequest) GetMaxDepth() int32 {
 	if x != nil {
-		return x.Name
+		return x.MaxDepth
 	}
-	return ""
+	return 0
 }
 
-func (x *Category) GetCreatedAt() *timestamppb.Timestamp {
+type CategoryTreeNode struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Category *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	// По имени
+	Children      []*CategoryTreeNode `protobuf:"bytes,2,rep,name=children,proto3" json:"children,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CategoryTreeNode) Reset() {
+	*x = CategoryTreeNode{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CategoryTreeNode) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CategoryTreeNode) ProtoMessage() {}
+
+func (x *CategoryTreeNode) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[29]
 	if x != nil {
-		return x.CreatedAt
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CategoryTreeNode.ProtoReflect.Descriptor instead.
+func (*CategoryTreeNode) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *CategoryTreeNode) GetCategory() *Category {
+	if x != nil {
+		return x.Category
 	}
 	return nil
 }
 
-func (x *Category) GetUpdatedAt() *timestamppb.Timestamp {
+func (x *CategoryTreeNode) GetChildren() []*CategoryTreeNode {
 	if x != nil {
-		return x.UpdatedAt
+		return x.Children
 	}
 	return nil
 }
 
-type CreateCategoryRequest struct {
+type GetCategoryTreeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Roots         []*CategoryTreeNode    `protobuf:"bytes,1,rep,name=roots,proto3" json:"roots,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CreateCategoryRequest) Reset() {
-	*x = CreateCategoryRequest{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[9]
+func (x *GetCategoryTreeResponse) Reset() {
+	*x = GetCategoryTreeResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CreateCategoryRequest) String() string {
+func (x *GetCategoryTreeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CreateCategoryRequest) ProtoMessage() {}
+func (*GetCategoryTreeResponse) ProtoMessage() {}
 
-func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[9]
+func (x *GetCategoryTreeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -629,40 +2341,40 @@ func (x *CreateCategoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CreateCategoryRequest.ProtoReflect.Descriptor instead.
-func (*CreateCategoryRequest) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{9}
+// Deprecated: Use GetCategoryTreeResponse.ProtoReflect.Descriptor instead.
+func (*GetCategoryTreeResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{30}
 }
 
-func (x *CreateCategoryRequest) GetName() string {
+func (x *GetCategoryTreeResponse) GetRoots() []*CategoryTreeNode {
 	if x != nil {
-		return x.Name
+		return x.Roots
 	}
-	return ""
+	return nil
 }
 
-type GetCategoryRequest struct {
+type ListCategoriesResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCategoryRequest) Reset() {
-	*x = GetCategoryRequest{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[10]
+func (x *ListCategoriesResponse) Reset() {
+	*x = ListCategoriesResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCategoryRequest) String() string {
+func (x *ListCategoriesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCategoryRequest) ProtoMessage() {}
+func (*ListCategoriesResponse) ProtoMessage() {}
 
-func (x *GetCategoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[10]
+func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -673,41 +2385,41 @@ func (x *GetCategoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCategoryRequest.ProtoReflect.Descriptor instead.
-func (*GetCategoryRequest) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use ListCategoriesResponse.ProtoReflect.Descriptor instead.
+func (*ListCategoriesResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{31}
 }
 
-func (x *GetCategoryRequest) GetId() string {
+func (x *ListCategoriesResponse) GetCategories() []*Category {
 	if x != nil {
-		return x.Id
+		return x.Categories
 	}
-	return ""
+	return nil
 }
 
-type UpdateCategoryRequest struct {
+type StockItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
+	Quantity      int32                  `protobuf:"varint,2,opt,name=quantity,proto3" json:"quantity,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateCategoryRequest) Reset() {
-	*x = UpdateCategoryRequest{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[11]
+func (x *StockItem) Reset() {
+	*x = StockItem{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateCategoryRequest) String() string {
+func (x *StockItem) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateCategoryRequest) ProtoMessage() {}
+func (*StockItem) ProtoMessage() {}
 
-func (x *UpdateCategoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[11]
+func (x *StockItem) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -718,47 +2430,51 @@ func (x *UpdateCategoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateCategoryRequest.ProtoReflect.Descriptor instead.
-func (*UpdateCategoryRequest) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use StockItem.ProtoReflect.Descriptor instead.
+func (*StockItem) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{32}
 }
 
-func (x *UpdateCategoryRequest) GetId() string {
+func (x *StockItem) GetProductId() string {
 	if x != nil {
-		return x.Id
+		return x.ProductId
 	}
 	return ""
 }
 
-func (x *UpdateCategoryRequest) GetName() string {
+func (x *StockItem) GetQuantity() int32 {
 	if x != nil {
-		return x.Name
+		return x.Quantity
 	}
-	return ""
+	return 0
 }
 
-type DeleteCategoryRequest struct {
+type Reservation struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Items         []*StockItem           `protobuf:"bytes,2,rep,name=items,proto3" json:"items,omitempty"`
+	Status        ReservationStatus      `protobuf:"varint,3,opt,name=status,proto3,enum=inventory.ReservationStatus" json:"status,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteCategoryRequest) Reset() {
-	*x = DeleteCategoryRequest{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[12]
+func (x *Reservation) Reset() {
+	*x = Reservation{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteCategoryRequest) String() string {
+func (x *Reservation) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteCategoryRequest) ProtoMessage() {}
+func (*Reservation) ProtoMessage() {}
 
-func (x *DeleteCategoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[12]
+func (x *Reservation) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -769,39 +2485,68 @@ func (x *DeleteCategoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteCategoryRequest.ProtoReflect.Descriptor instead.
-func (*DeleteCategoryRequest) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use Reservation.ProtoReflect.Descriptor instead.
+func (*Reservation) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{33}
 }
 
-func (x *DeleteCategoryRequest) GetId() string {
+func (x *Reservation) GetId() string {
 	if x != nil {
 		return x.Id
 	}
 	return ""
 }
 
-type ListCategoriesRequest struct {
+func (x *Reservation) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *Reservation) GetStatus() ReservationStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ReservationStatus_RESERVATION_STATUS_UNSPECIFIED
+}
+
+func (x *Reservation) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Reservation) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type ReserveStockRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*StockItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCategoriesRequest) Reset() {
-	*x = ListCategoriesRequest{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[13]
+func (x *ReserveStockRequest) Reset() {
+	*x = ReserveStockRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCategoriesRequest) String() string {
+func (x *ReserveStockRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCategoriesRequest) ProtoMessage() {}
+func (*ReserveStockRequest) ProtoMessage() {}
 
-func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[13]
+func (x *ReserveStockRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -812,33 +2557,40 @@ func (x *ListCategoriesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCategoriesRequest.ProtoReflect.Descriptor instead.
-func (*ListCategoriesRequest) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{13}
+// Deprecated: Use ReserveStockRequest.ProtoReflect.Descriptor instead.
+func (*ReserveStockRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{34}
 }
 
-type CategoryResponse struct {
+func (x *ReserveStockRequest) GetItems() []*StockItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type CommitReservationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Category      *Category              `protobuf:"bytes,1,opt,name=category,proto3" json:"category,omitempty"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *CategoryResponse) Reset() {
-	*x = CategoryResponse{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[14]
+func (x *CommitReservationRequest) Reset() {
+	*x = CommitReservationRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *CategoryResponse) String() string {
+func (x *CommitReservationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*CategoryResponse) ProtoMessage() {}
+func (*CommitReservationRequest) ProtoMessage() {}
 
-func (x *CategoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[14]
+func (x *CommitReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -849,40 +2601,40 @@ func (x *CategoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use CategoryResponse.ProtoReflect.Descriptor instead.
-func (*CategoryResponse) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{14}
+// Deprecated: Use CommitReservationRequest.ProtoReflect.Descriptor instead.
+func (*CommitReservationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{35}
 }
 
-func (x *CategoryResponse) GetCategory() *Category {
+func (x *CommitReservationRequest) GetReservationId() string {
 	if x != nil {
-		return x.Category
+		return x.ReservationId
 	}
-	return nil
+	return ""
 }
 
-type ListCategoriesResponse struct {
+type ReleaseReservationRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Categories    []*Category            `protobuf:"bytes,1,rep,name=categories,proto3" json:"categories,omitempty"`
+	ReservationId string                 `protobuf:"bytes,1,opt,name=reservation_id,json=reservationId,proto3" json:"reservation_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListCategoriesResponse) Reset() {
-	*x = ListCategoriesResponse{}
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[15]
+func (x *ReleaseReservationRequest) Reset() {
+	*x = ReleaseReservationRequest{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListCategoriesResponse) String() string {
+func (x *ReleaseReservationRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListCategoriesResponse) ProtoMessage() {}
+func (*ReleaseReservationRequest) ProtoMessage() {}
 
-func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_inventory_service_proto_inventory_proto_msgTypes[15]
+func (x *ReleaseReservationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -893,14 +2645,58 @@ func (x *ListCategoriesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListCategoriesResponse.ProtoReflect.Descriptor instead.
-func (*ListCategoriesResponse) Descriptor() ([]byte, []int) {
-	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{15}
+// Deprecated: Use ReleaseReservationRequest.ProtoReflect.Descriptor instead.
+func (*ReleaseReservationRequest) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{36}
 }
 
-func (x *ListCategoriesResponse) GetCategories() []*Category {
+func (x *ReleaseReservationRequest) GetReservationId() string {
 	if x != nil {
-		return x.Categories
+		return x.ReservationId
+	}
+	return ""
+}
+
+type ReservationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reservation   *Reservation           `protobuf:"bytes,1,opt,name=reservation,proto3" json:"reservation,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReservationResponse) Reset() {
+	*x = ReservationResponse{}
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReservationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReservationResponse) ProtoMessage() {}
+
+func (x *ReservationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_inventory_service_proto_inventory_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReservationResponse.ProtoReflect.Descriptor instead.
+func (*ReservationResponse) Descriptor() ([]byte, []int) {
+	return file_inventory_service_proto_inventory_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *ReservationResponse) GetReservation() *Reservation {
+	if x != nil {
+		return x.Reservation
 	}
 	return nil
 }
@@ -909,7 +2705,7 @@ var File_inventory_service_proto_inventory_proto protoreflect.FileDescriptor
 
 const file_inventory_service_proto_inventory_proto_rawDesc = "" +
 	"\n" +
-	"'inventory-service/proto/inventory.proto\x12\tinventory\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\x92\x02\n" +
+	"'inventory-service/proto/inventory.proto\x12\tinventory\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\"\x8c\x03\n" +
 	"\aProduct\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -921,16 +2717,22 @@ const file_inventory_service_proto_inventory_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\x99\x01\n" +
+	"updated_at\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\t \x01(\x03R\aversion\x129\n" +
+	"\n" +
+	"deleted_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\x12#\n" +
+	"\rstock_version\x18\v \x01(\x03R\fstockVersion\"\x99\x01\n" +
 	"\x14CreateProductRequest\x12\x12\n" +
 	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
 	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x14\n" +
 	"\x05price\x18\x03 \x01(\x01R\x05price\x12\x14\n" +
 	"\x05stock\x18\x04 \x01(\x05R\x05stock\x12\x1f\n" +
 	"\vcategory_id\x18\x05 \x01(\tR\n" +
-	"categoryId\"#\n" +
+	"categoryId\"L\n" +
 	"\x11GetProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\xa9\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xcd\x02\n" +
 	"\x14UpdateProductRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12 \n" +
@@ -938,54 +2740,234 @@ const file_inventory_service_proto_inventory_proto_rawDesc = "" +
 	"\x05price\x18\x04 \x01(\x01R\x05price\x12\x14\n" +
 	"\x05stock\x18\x05 \x01(\x05R\x05stock\x12\x1f\n" +
 	"\vcategory_id\x18\x06 \x01(\tR\n" +
-	"categoryId\"&\n" +
+	"categoryId\x12\x1d\n" +
+	"\aversion\x18\a \x01(\x03H\x00R\aversion\x88\x01\x01\x12;\n" +
+	"\vupdate_mask\x18\b \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMask\x12(\n" +
+	"\rstock_version\x18\t \x01(\x03H\x01R\fstockVersion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_versionB\x10\n" +
+	"\x0e_stock_version\"&\n" +
 	"\x14DeleteProductRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x81\x01\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"'\n" +
+	"\x15RestoreProductRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xdf\x06\n" +
 	"\x13ListProductsRequest\x12,\n" +
 	"\x12category_id_filter\x18\x01 \x01(\tR\x10categoryIdFilter\x12\x1b\n" +
 	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1f\n" +
 	"\vpage_number\x18\x03 \x01(\x05R\n" +
-	"pageNumber\"?\n" +
+	"pageNumber\x12!\n" +
+	"\fcategory_ids\x18\x04 \x03(\tR\vcategoryIds\x12 \n" +
+	"\tmin_price\x18\x05 \x01(\x01H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\x06 \x01(\x01H\x01R\bmaxPrice\x88\x01\x01\x12\"\n" +
+	"\rin_stock_only\x18\a \x01(\bR\vinStockOnly\x12\x1f\n" +
+	"\vname_prefix\x18\b \x01(\tR\n" +
+	"namePrefix\x12?\n" +
+	"\rcreated_after\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12?\n" +
+	"\rupdated_after\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedAfter\x12A\n" +
+	"\x0eupdated_before\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\rupdatedBefore\x124\n" +
+	"\asort_by\x18\r \x01(\x0e2\x1b.inventory.ProductSortFieldR\x06sortBy\x123\n" +
+	"\n" +
+	"sort_order\x18\x0e \x01(\x0e2\x14.inventory.SortOrderR\tsortOrder\x12\x1d\n" +
+	"\n" +
+	"page_token\x18\x0f \x01(\tR\tpageToken\x12(\n" +
+	"\x10skip_total_count\x18\x10 \x01(\bR\x0eskipTotalCount\x12/\n" +
+	"\x13include_descendants\x18\x11 \x01(\bR\x12includeDescendants\x12'\n" +
+	"\x0finclude_deleted\x18\x12 \x01(\bR\x0eincludeDeletedB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_price\"+\n" +
+	"\x17GetProductsByIDsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\tR\x03ids\"k\n" +
+	"\x18GetProductsByIDsResponse\x12.\n" +
+	"\bproducts\x18\x01 \x03(\v2\x12.inventory.ProductR\bproducts\x12\x1f\n" +
+	"\vmissing_ids\x18\x02 \x03(\tR\n" +
+	"missingIds\"?\n" +
 	"\x0fProductResponse\x12,\n" +
-	"\aproduct\x18\x01 \x01(\v2\x12.inventory.ProductR\aproduct\"g\n" +
+	"\aproduct\x18\x01 \x01(\v2\x12.inventory.ProductR\aproduct\"\xa4\x01\n" +
 	"\x14ListProductsResponse\x12.\n" +
-	"\bproducts\x18\x01 \x03(\v2\x12.inventory.ProductR\bproducts\x12\x1f\n" +
+	"\bproducts\x18\x01 \x03(\v2\x12.inventory.ProductR\bproducts\x12$\n" +
+	"\vtotal_count\x18\x02 \x01(\x03H\x00R\n" +
+	"totalCount\x88\x01\x01\x12&\n" +
+	"\x0fnext_page_token\x18\x03 \x01(\tR\rnextPageTokenB\x0e\n" +
+	"\f_total_count\"\x90\x04\n" +
+	"\rProductFilter\x12!\n" +
+	"\fcategory_ids\x18\x01 \x03(\tR\vcategoryIds\x12 \n" +
+	"\tmin_price\x18\x02 \x01(\x01H\x00R\bminPrice\x88\x01\x01\x12 \n" +
+	"\tmax_price\x18\x03 \x01(\x01H\x01R\bmaxPrice\x88\x01\x01\x12\"\n" +
+	"\rin_stock_only\x18\x04 \x01(\bR\vinStockOnly\x12\x1f\n" +
+	"\vname_prefix\x18\x05 \x01(\tR\n" +
+	"namePrefix\x12?\n" +
+	"\rcreated_after\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12?\n" +
+	"\rupdated_after\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fupdatedAfter\x12A\n" +
+	"\x0eupdated_before\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rupdatedBefore\x12/\n" +
+	"\x13include_descendants\x18\n" +
+	" \x01(\bR\x12includeDescendantsB\f\n" +
+	"\n" +
+	"_min_priceB\f\n" +
+	"\n" +
+	"_max_price\"v\n" +
+	"\x17GetProductFacetsRequest\x120\n" +
+	"\x06filter\x18\x01 \x01(\v2\x18.inventory.ProductFilterR\x06filter\x12)\n" +
+	"\x10price_boundaries\x18\x02 \x03(\x01R\x0fpriceBoundaries\"F\n" +
+	"\rCategoryFacet\x12\x1f\n" +
+	"\vcategory_id\x18\x01 \x01(\tR\n" +
+	"categoryId\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\"T\n" +
+	"\vPriceBucket\x12\x10\n" +
+	"\x03min\x18\x01 \x01(\x01R\x03min\x12\x15\n" +
+	"\x03max\x18\x02 \x01(\x01H\x00R\x03max\x88\x01\x01\x12\x14\n" +
+	"\x05count\x18\x03 \x01(\x03R\x05countB\x06\n" +
+	"\x04_max\"\xe4\x01\n" +
+	"\x18GetProductFacetsResponse\x128\n" +
+	"\n" +
+	"categories\x18\x01 \x03(\v2\x18.inventory.CategoryFacetR\n" +
+	"categories\x12$\n" +
+	"\x0ein_stock_count\x18\x02 \x01(\x03R\finStockCount\x12+\n" +
+	"\x12out_of_stock_count\x18\x03 \x01(\x03R\x0foutOfStockCount\x12;\n" +
+	"\rprice_buckets\x18\x04 \x03(\v2\x16.inventory.PriceBucketR\fpriceBuckets\"k\n" +
+	"\x15SearchProductsRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1f\n" +
+	"\vpage_number\x18\x03 \x01(\x05R\n" +
+	"pageNumber\"\xe2\x01\n" +
+	"\x10ProductSearchHit\x12,\n" +
+	"\aproduct\x18\x01 \x01(\v2\x12.inventory.ProductR\aproduct\x12\x14\n" +
+	"\x05score\x18\x02 \x01(\x01R\x05score\x12K\n" +
+	"\n" +
+	"highlights\x18\x03 \x03(\v2+.inventory.ProductSearchHit.HighlightsEntryR\n" +
+	"highlights\x1a=\n" +
+	"\x0fHighlightsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"j\n" +
+	"\x16SearchProductsResponse\x12/\n" +
+	"\x04hits\x18\x01 \x03(\v2\x1b.inventory.ProductSearchHitR\x04hits\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x03R\n" +
-	"totalCount\"\xa4\x01\n" +
+	"totalCount\"\xbe\x02\n" +
 	"\bCategory\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
 	"\n" +
 	"created_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"+\n" +
+	"updated_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\x03R\aversion\x12\x1b\n" +
+	"\tparent_id\x18\x06 \x01(\tR\bparentId\x12\x12\n" +
+	"\x04path\x18\a \x03(\tR\x04path\x12\x12\n" +
+	"\x04slug\x18\b \x01(\tR\x04slug\x129\n" +
+	"\n" +
+	"deleted_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tdeletedAt\"\\\n" +
 	"\x15CreateCategoryRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"$\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1b\n" +
+	"\tparent_id\x18\x02 \x01(\tR\bparentId\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\"M\n" +
 	"\x12GetCategoryRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\";\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12'\n" +
+	"\x0finclude_deleted\x18\x02 \x01(\bR\x0eincludeDeleted\"\xb8\x01\n" +
 	"\x15UpdateCategoryRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
-	"\x04name\x18\x02 \x01(\tR\x04name\"'\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\aversion\x18\x03 \x01(\x03H\x00R\aversion\x88\x01\x01\x12 \n" +
+	"\tparent_id\x18\x04 \x01(\tH\x01R\bparentId\x88\x01\x01\x12\x17\n" +
+	"\x04slug\x18\x05 \x01(\tH\x02R\x04slug\x88\x01\x01B\n" +
+	"\n" +
+	"\b_versionB\f\n" +
+	"\n" +
+	"_parent_idB\a\n" +
+	"\x05_slug\"\x91\x01\n" +
 	"\x15DeleteCategoryRequest\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\tR\x02id\"\x17\n" +
-	"\x15ListCategoriesRequest\"C\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x121\n" +
+	"\x04mode\x18\x02 \x01(\x0e2\x1d.inventory.DeleteCategoryModeR\x04mode\x125\n" +
+	"\x17reassign_to_category_id\x18\x03 \x01(\tR\x14reassignToCategoryId\"@\n" +
+	"\x15ListCategoriesRequest\x12'\n" +
+	"\x0finclude_deleted\x18\x01 \x01(\bR\x0eincludeDeleted\"(\n" +
+	"\x16RestoreCategoryRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"D\n" +
+	"\n" +
+	"Breadcrumb\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\"|\n" +
 	"\x10CategoryResponse\x12/\n" +
-	"\bcategory\x18\x01 \x01(\v2\x13.inventory.CategoryR\bcategory\"M\n" +
+	"\bcategory\x18\x01 \x01(\v2\x13.inventory.CategoryR\bcategory\x127\n" +
+	"\vbreadcrumbs\x18\x02 \x03(\v2\x15.inventory.BreadcrumbR\vbreadcrumbs\"N\n" +
+	"\x16GetCategoryTreeRequest\x12\x17\n" +
+	"\aroot_id\x18\x01 \x01(\tR\x06rootId\x12\x1b\n" +
+	"\tmax_depth\x18\x02 \x01(\x05R\bmaxDepth\"|\n" +
+	"\x10CategoryTreeNode\x12/\n" +
+	"\bcategory\x18\x01 \x01(\v2\x13.inventory.CategoryR\bcategory\x127\n" +
+	"\bchildren\x18\x02 \x03(\v2\x1b.inventory.CategoryTreeNodeR\bchildren\"L\n" +
+	"\x17GetCategoryTreeResponse\x121\n" +
+	"\x05roots\x18\x01 \x03(\v2\x1b.inventory.CategoryTreeNodeR\x05roots\"M\n" +
 	"\x16ListCategoriesResponse\x123\n" +
 	"\n" +
 	"categories\x18\x01 \x03(\v2\x13.inventory.CategoryR\n" +
-	"categories2\xa9\x06\n" +
+	"categories\"F\n" +
+	"\tStockItem\x12\x1d\n" +
+	"\n" +
+	"product_id\x18\x01 \x01(\tR\tproductId\x12\x1a\n" +
+	"\bquantity\x18\x02 \x01(\x05R\bquantity\"\xf5\x01\n" +
+	"\vReservation\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12*\n" +
+	"\x05items\x18\x02 \x03(\v2\x14.inventory.StockItemR\x05items\x124\n" +
+	"\x06status\x18\x03 \x01(\x0e2\x1c.inventory.ReservationStatusR\x06status\x129\n" +
+	"\n" +
+	"created_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"A\n" +
+	"\x13ReserveStockRequest\x12*\n" +
+	"\x05items\x18\x01 \x03(\v2\x14.inventory.StockItemR\x05items\"A\n" +
+	"\x18CommitReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId\"B\n" +
+	"\x19ReleaseReservationRequest\x12%\n" +
+	"\x0ereservation_id\x18\x01 \x01(\tR\rreservationId\"O\n" +
+	"\x13ReservationResponse\x128\n" +
+	"\vreservation\x18\x01 \x01(\v2\x16.inventory.ReservationR\vreservation*\xd5\x01\n" +
+	"\x10ProductSortField\x12\"\n" +
+	"\x1ePRODUCT_SORT_FIELD_UNSPECIFIED\x10\x00\x12!\n" +
+	"\x1dPRODUCT_SORT_FIELD_CREATED_AT\x10\x01\x12!\n" +
+	"\x1dPRODUCT_SORT_FIELD_UPDATED_AT\x10\x02\x12\x1c\n" +
+	"\x18PRODUCT_SORT_FIELD_PRICE\x10\x03\x12\x1b\n" +
+	"\x17PRODUCT_SORT_FIELD_NAME\x10\x04\x12\x1c\n" +
+	"\x18PRODUCT_SORT_FIELD_STOCK\x10\x05*P\n" +
+	"\tSortOrder\x12\x1a\n" +
+	"\x16SORT_ORDER_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eSORT_ORDER_ASC\x10\x01\x12\x13\n" +
+	"\x0fSORT_ORDER_DESC\x10\x02*\xa0\x01\n" +
+	"\x12DeleteCategoryMode\x12$\n" +
+	" DELETE_CATEGORY_MODE_UNSPECIFIED\x10\x00\x12\x1f\n" +
+	"\x1bDELETE_CATEGORY_MODE_REJECT\x10\x01\x12!\n" +
+	"\x1dDELETE_CATEGORY_MODE_REASSIGN\x10\x02\x12 \n" +
+	"\x1cDELETE_CATEGORY_MODE_CASCADE\x10\x03*b\n" +
+	"\x11ReservationStatus\x12\"\n" +
+	"\x1eRESERVATION_STATUS_UNSPECIFIED\x10\x00\x12\f\n" +
+	"\bRESERVED\x10\x01\x12\r\n" +
+	"\tCOMMITTED\x10\x02\x12\f\n" +
+	"\bRELEASED\x10\x032\xbd\f\n" +
 	"\x10InventoryService\x12L\n" +
 	"\rCreateProduct\x12\x1f.inventory.CreateProductRequest\x1a\x1a.inventory.ProductResponse\x12J\n" +
 	"\x0eGetProductByID\x12\x1c.inventory.GetProductRequest\x1a\x1a.inventory.ProductResponse\x12L\n" +
 	"\rUpdateProduct\x12\x1f.inventory.UpdateProductRequest\x1a\x1a.inventory.ProductResponse\x12H\n" +
-	"\rDeleteProduct\x12\x1f.inventory.DeleteProductRequest\x1a\x16.google.protobuf.Empty\x12O\n" +
-	"\fListProducts\x12\x1e.inventory.ListProductsRequest\x1a\x1f.inventory.ListProductsResponse\x12O\n" +
+	"\rDeleteProduct\x12\x1f.inventory.DeleteProductRequest\x1a\x16.google.protobuf.Empty\x12N\n" +
+	"\x0eRestoreProduct\x12 .inventory.RestoreProductRequest\x1a\x1a.inventory.ProductResponse\x12O\n" +
+	"\fListProducts\x12\x1e.inventory.ListProductsRequest\x1a\x1f.inventory.ListProductsResponse\x12[\n" +
+	"\x10GetProductsByIDs\x12\".inventory.GetProductsByIDsRequest\x1a#.inventory.GetProductsByIDsResponse\x12U\n" +
+	"\x0eSearchProducts\x12 .inventory.SearchProductsRequest\x1a!.inventory.SearchProductsResponse\x12[\n" +
+	"\x10GetProductFacets\x12\".inventory.GetProductFacetsRequest\x1a#.inventory.GetProductFacetsResponse\x12O\n" +
 	"\x0eCreateCategory\x12 .inventory.CreateCategoryRequest\x1a\x1b.inventory.CategoryResponse\x12M\n" +
 	"\x0fGetCategoryByID\x12\x1d.inventory.GetCategoryRequest\x1a\x1b.inventory.CategoryResponse\x12O\n" +
 	"\x0eUpdateCategory\x12 .inventory.UpdateCategoryRequest\x1a\x1b.inventory.CategoryResponse\x12J\n" +
-	"\x0eDeleteCategory\x12 .inventory.DeleteCategoryRequest\x1a\x16.google.protobuf.Empty\x12U\n" +
-	"\x0eListCategories\x12 .inventory.ListCategoriesRequest\x1a!.inventory.ListCategoriesResponseBCZAecommerce-microservices/inventory-service/internal/pb;inventorypbb\x06proto3"
+	"\x0eDeleteCategory\x12 .inventory.DeleteCategoryRequest\x1a\x16.google.protobuf.Empty\x12Q\n" +
+	"\x0fRestoreCategory\x12!.inventory.RestoreCategoryRequest\x1a\x1b.inventory.CategoryResponse\x12X\n" +
+	"\x0fGetCategoryTree\x12!.inventory.GetCategoryTreeRequest\x1a\".inventory.GetCategoryTreeResponse\x12U\n" +
+	"\x0eListCategories\x12 .inventory.ListCategoriesRequest\x1a!.inventory.ListCategoriesResponse\x12N\n" +
+	"\fReserveStock\x12\x1e.inventory.ReserveStockRequest\x1a\x1e.inventory.ReservationResponse\x12X\n" +
+	"\x11CommitReservation\x12#.inventory.CommitReservationRequest\x1a\x1e.inventory.ReservationResponse\x12Z\n" +
+	"\x12ReleaseReservation\x12$.inventory.ReleaseReservationRequest\x1a\x1e.inventory.ReservationResponseBCZAecommerce-microservices/inventory-service/internal/pb;inventorypbb\x06proto3"
 
 var (
 	file_inventory_service_proto_inventory_proto_rawDescOnce sync.Once
@@ -999,61 +2981,139 @@ func file_inventory_service_proto_inventory_proto_rawDescGZIP() []byte {
 	return file_inventory_service_proto_inventory_proto_rawDescData
 }
 
-var file_inventory_service_proto_inventory_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
+var file_inventory_service_proto_inventory_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_inventory_service_proto_inventory_proto_msgTypes = make([]protoimpl.MessageInfo, 39)
 var file_inventory_service_proto_inventory_proto_goTypes = []any{
-	(*Product)(nil),                // 0: inventory.Product
-	(*CreateProductRequest)(nil),   // 1: inventory.CreateProductRequest
-	(*GetProductRequest)(nil),      // 2: inventory.GetProductRequest
-	(*UpdateProductRequest)(nil),   // 3: inventory.UpdateProductRequest
-	(*DeleteProductRequest)(nil),   // 4: inventory.DeleteProductRequest
-	(*ListProductsRequest)(nil),    // 5: inventory.ListProductsRequest
-	(*ProductResponse)(nil),        // 6: inventory.ProductResponse
-	(*ListProductsResponse)(nil),   // 7: inventory.ListProductsResponse
-	(*Category)(nil),               // 8: inventory.Category
-	(*CreateCategoryRequest)(nil),  // 9: inventory.CreateCategoryRequest
-	(*GetCategoryRequest)(nil),     // 10: inventory.GetCategoryRequest
-	(*UpdateCategoryRequest)(nil),  // 11: inventory.UpdateCategoryRequest
-	(*DeleteCategoryRequest)(nil),  // 12: inventory.DeleteCategoryRequest
-	(*ListCategoriesRequest)(nil),  // 13: inventory.ListCategoriesRequest
-	(*CategoryResponse)(nil),       // 14: inventory.CategoryResponse
-	(*ListCategoriesResponse)(nil), // 15: inventory.ListCategoriesResponse
-	(*timestamppb.Timestamp)(nil),  // 16: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),          // 17: google.protobuf.Empty
+	(ProductSortField)(0),             // 0: inventory.ProductSortField
+	(SortOrder)(0),                    // 1: inventory.SortOrder
+	(DeleteCategoryMode)(0),           // 2: inventory.DeleteCategoryMode
+	(ReservationStatus)(0),            // 3: inventory.ReservationStatus
+	(*Product)(nil),                   // 4: inventory.Product
+	(*CreateProductRequest)(nil),      // 5: inventory.CreateProductRequest
+	(*GetProductRequest)(nil),         // 6: inventory.GetProductRequest
+	(*UpdateProductRequest)(nil),      // 7: inventory.UpdateProductRequest
+	(*DeleteProductRequest)(nil),      // 8: inventory.DeleteProductRequest
+	(*RestoreProductRequest)(nil),     // 9: inventory.RestoreProductRequest
+	(*ListProductsRequest)(nil),       // 10: inventory.ListProductsRequest
+	(*GetProductsByIDsRequest)(nil),   // 11: inventory.GetProductsByIDsRequest
+	(*GetProductsByIDsResponse)(nil),  // 12: inventory.GetProductsByIDsResponse
+	(*ProductResponse)(nil),           // 13: inventory.ProductResponse
+	(*ListProductsResponse)(nil),      // 14: inventory.ListProductsResponse
+	(*ProductFilter)(nil),             // 15: inventory.ProductFilter
+	(*GetProductFacetsRequest)(nil),   // 16: inventory.GetProductFacetsRequest
+	(*CategoryFacet)(nil),             // 17: inventory.CategoryFacet
+	(*PriceBucket)(nil),               // 18: inventory.PriceBucket
+	(*GetProductFacetsResponse)(nil),  // 19: inventory.GetProductFacetsResponse
+	(*SearchProductsRequest)(nil),     // 20: inventory.SearchProductsRequest
+	(*ProductSearchHit)(nil),          // 21: inventory.ProductSearchHit
+	(*SearchProductsResponse)(nil),    // 22: inventory.SearchProductsResponse
+	(*Category)(nil),                  // 23: inventory.Category
+	(*CreateCategoryRequest)(nil),     // 24: inventory.CreateCategoryRequest
+	(*GetCategoryRequest)(nil),        // 25: inventory.GetCategoryRequest
+	(*UpdateCategoryRequest)(nil),     // 26: inventory.UpdateCategoryRequest
+	(*DeleteCategoryRequest)(nil),     // 27: inventory.DeleteCategoryRequest
+	(*ListCategoriesRequest)(nil),     // 28: inventory.ListCategoriesRequest
+	(*RestoreCategoryRequest)(nil),    // 29: inventory.RestoreCategoryRequest
+	(*Breadcrumb)(nil),                // 30: inventory.Breadcrumb
+	(*CategoryResponse)(nil),          // 31: inventory.CategoryResponse
+	(*GetCategoryTreeRequest)(nil),    // 32: inventory.GetCategoryTreeRequest
+	(*CategoryTreeNode)(nil),          // 33: inventory.CategoryTreeNode
+	(*GetCategoryTreeResponse)(nil),   // 34: inventory.GetCategoryTreeResponse
+	(*ListCategoriesResponse)(nil),    // 35: inventory.ListCategoriesResponse
+	(*StockItem)(nil),                 // 36: inventory.StockItem
+	(*Reservation)(nil),               // 37: inventory.Reservation
+	(*ReserveStockRequest)(nil),       // 38: inventory.ReserveStockRequest
+	(*CommitReservationRequest)(nil),  // 39: inventory.CommitReservationRequest
+	(*ReleaseReservationRequest)(nil), // 40: inventory.ReleaseReservationRequest
+	(*ReservationResponse)(nil),       // 41: inventory.ReservationResponse
+	nil,                               // 42: inventory.ProductSearchHit.HighlightsEntry
+	(*timestamppb.Timestamp)(nil),     // 43: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),     // 44: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),             // 45: google.protobuf.Empty
 }
 var file_inventory_service_proto_inventory_proto_depIdxs = []int32{
-	16, // 0: inventory.Product.created_at:type_name -> google.protobuf.Timestamp
-	16, // 1: inventory.Product.updated_at:type_name -> google.protobuf.Timestamp
-	0,  // 2: inventory.ProductResponse.product:type_name -> inventory.Product
-	0,  // 3: inventory.ListProductsResponse.products:type_name -> inventory.Product
-	16, // 4: inventory.Category.created_at:type_name -> google.protobuf.Timestamp
-	16, // 5: inventory.Category.updated_at:type_name -> google.protobuf.Timestamp
-	8,  // 6: inventory.CategoryResponse.category:type_name -> inventory.Category
-	8,  // 7: inventory.ListCategoriesResponse.categories:type_name -> inventory.Category
-	1,  // 8: inventory.InventoryService.CreateProduct:input_type -> inventory.CreateProductRequest
-	2,  // 9: inventory.InventoryService.GetProductByID:input_type -> inventory.GetProductRequest
-	3,  // 10: inventory.InventoryService.UpdateProduct:input_type -> inventory.UpdateProductRequest
-	4,  // 11: inventory.InventoryService.DeleteProduct:input_type -> inventory.DeleteProductRequest
-	5,  // 12: inventory.InventoryService.ListProducts:input_type -> inventory.ListProductsRequest
-	9,  // 13: inventory.InventoryService.CreateCategory:input_type -> inventory.CreateCategoryRequest
-	10, // 14: inventory.InventoryService.GetCategoryByID:input_type -> inventory.GetCategoryRequest
-	11, // 15: inventory.InventoryService.UpdateCategory:input_type -> inventory.UpdateCategoryRequest
-	12, // 16: inventory.InventoryService.DeleteCategory:input_type -> inventory.DeleteCategoryRequest
-	13, // 17: inventory.InventoryService.ListCategories:input_type -> inventory.ListCategoriesRequest
-	6,  // 18: inventory.InventoryService.CreateProduct:output_type -> inventory.ProductResponse
-	6,  // 19: inventory.InventoryService.GetProductByID:output_type -> inventory.ProductResponse
-	6,  // 20: inventory.InventoryService.UpdateProduct:output_type -> inventory.ProductResponse
-	17, // 21: inventory.InventoryService.DeleteProduct:output_type -> google.protobuf.Empty
-	7,  // 22: inventory.InventoryService.ListProducts:output_type -> inventory.ListProductsResponse
-	14, // 23: inventory.InventoryService.CreateCategory:output_type -> inventory.CategoryResponse
-	14, // 24: inventory.InventoryService.GetCategoryByID:output_type -> inventory.CategoryResponse
-	14, // 25: inventory.InventoryService.UpdateCategory:output_type -> inventory.CategoryResponse
-	17, // 26: inventory.InventoryService.DeleteCategory:output_type -> google.protobuf.Empty
-	15, // 27: inventory.InventoryService.ListCategories:output_type -> inventory.ListCategoriesResponse
-	18, // [18:28] is the sub-list for method output_type
-	8,  // [8:18] is the sub-list for method input_type
-	8,  // [8:8] is the sub-list for extension type_name
-	8,  // [8:8] is the sub-list for extension extendee
-	0,  // [0:8] is the sub-list for field type_name
+	43, // 0: inventory.Product.created_at:type_name -> google.protobuf.Timestamp
+	43, // 1: inventory.Product.updated_at:type_name -> google.protobuf.Timestamp
+	43, // 2: inventory.Product.deleted_at:type_name -> google.protobuf.Timestamp
+	44, // 3: inventory.UpdateProductRequest.update_mask:type_name -> google.protobuf.FieldMask
+	43, // 4: inventory.ListProductsRequest.created_after:type_name -> google.protobuf.Timestamp
+	43, // 5: inventory.ListProductsRequest.created_before:type_name -> google.protobuf.Timestamp
+	43, // 6: inventory.ListProductsRequest.updated_after:type_name -> google.protobuf.Timestamp
+	43, // 7: inventory.ListProductsRequest.updated_before:type_name -> google.protobuf.Timestamp
+	0,  // 8: inventory.ListProductsRequest.sort_by:type_name -> inventory.ProductSortField
+	1,  // 9: inventory.ListProductsRequest.sort_order:type_name -> inventory.SortOrder
+	4,  // 10: inventory.GetProductsByIDsResponse.products:type_name -> inventory.Product
+	4,  // 11: inventory.ProductResponse.product:type_name -> inventory.Product
+	4,  // 12: inventory.ListProductsResponse.products:type_name -> inventory.Product
+	43, // 13: inventory.ProductFilter.created_after:type_name -> google.protobuf.Timestamp
+	43, // 14: inventory.ProductFilter.created_before:type_name -> google.protobuf.Timestamp
+	43, // 15: inventory.ProductFilter.updated_after:type_name -> google.protobuf.Timestamp
+	43, // 16: inventory.ProductFilter.updated_before:type_name -> google.protobuf.Timestamp
+	15, // 17: inventory.GetProductFacetsRequest.filter:type_name -> inventory.ProductFilter
+	17, // 18: inventory.GetProductFacetsResponse.categories:type_name -> inventory.CategoryFacet
+	18, // 19: inventory.GetProductFacetsResponse.price_buckets:type_name -> inventory.PriceBucket
+	4,  // 20: inventory.ProductSearchHit.product:type_name -> inventory.Product
+	42, // 21: inventory.ProductSearchHit.highlights:type_name -> inventory.ProductSearchHit.HighlightsEntry
+	21, // 22: inventory.SearchProductsResponse.hits:type_name -> inventory.ProductSearchHit
+	43, // 23: inventory.Category.created_at:type_name -> google.protobuf.Timestamp
+	43, // 24: inventory.Category.updated_at:type_name -> google.protobuf.Timestamp
+	43, // 25: inventory.Category.deleted_at:type_name -> google.protobuf.Timestamp
+	2,  // 26: inventory.DeleteCategoryRequest.mode:type_name -> inventory.DeleteCategoryMode
+	23, // 27: inventory.CategoryResponse.category:type_name -> inventory.Category
+	30, // 28: inventory.CategoryResponse.breadcrumbs:type_name -> inventory.Breadcrumb
+	23, // 29: inventory.CategoryTreeNode.category:type_name -> inventory.Category
+	33, // 30: inventory.CategoryTreeNode.children:type_name -> inventory.CategoryTreeNode
+	33, // 31: inventory.GetCategoryTreeResponse.roots:type_name -> inventory.CategoryTreeNode
+	23, // 32: inventory.ListCategoriesResponse.categories:type_name -> inventory.Category
+	36, // 33: inventory.Reservation.items:type_name -> inventory.StockItem
+	3,  // 34: inventory.Reservation.status:type_name -> inventory.ReservationStatus
+	43, // 35: inventory.Reservation.created_at:type_name -> google.protobuf.Timestamp
+	43, // 36: inventory.Reservation.updated_at:type_name -> google.protobuf.Timestamp
+	36, // 37: inventory.ReserveStockRequest.items:type_name -> inventory.StockItem
+	37, // 38: inventory.ReservationResponse.reservation:type_name -> inventory.Reservation
+	5,  // 39: inventory.InventoryService.CreateProduct:input_type -> inventory.CreateProductRequest
+	6,  // 40: inventory.InventoryService.GetProductByID:input_type -> inventory.GetProductRequest
+	7,  // 41: inventory.InventoryService.UpdateProduct:input_type -> inventory.UpdateProductRequest
+	8,  // 42: inventory.InventoryService.DeleteProduct:input_type -> inventory.DeleteProductRequest
+	9,  // 43: inventory.InventoryService.RestoreProduct:input_type -> inventory.RestoreProductRequest
+	10, // 44: inventory.InventoryService.ListProducts:input_type -> inventory.ListProductsRequest
+	11, // 45: inventory.InventoryService.GetProductsByIDs:input_type -> inventory.GetProductsByIDsRequest
+	20, // 46: inventory.InventoryService.SearchProducts:input_type -> inventory.SearchProductsRequest
+	16, // 47: inventory.InventoryService.GetProductFacets:input_type -> inventory.GetProductFacetsRequest
+	24, // 48: inventory.InventoryService.CreateCategory:input_type -> inventory.CreateCategoryRequest
+	25, // 49: inventory.InventoryService.GetCategoryByID:input_type -> inventory.GetCategoryRequest
+	26, // 50: inventory.InventoryService.UpdateCategory:input_type -> inventory.UpdateCategoryRequest
+	27, // 51: inventory.InventoryService.DeleteCategory:input_type -> inventory.DeleteCategoryRequest
+	29, // 52: inventory.InventoryService.RestoreCategory:input_type -> inventory.RestoreCategoryRequest
+	32, // 53: inventory.InventoryService.GetCategoryTree:input_type -> inventory.GetCategoryTreeRequest
+	28, // 54: inventory.InventoryService.ListCategories:input_type -> inventory.ListCategoriesRequest
+	38, // 55: inventory.InventoryService.ReserveStock:input_type -> inventory.ReserveStockRequest
+	39, // 56: inventory.InventoryService.CommitReservation:input_type -> inventory.CommitReservationRequest
+	40, // 57: inventory.InventoryService.ReleaseReservation:input_type -> inventory.ReleaseReservationRequest
+	13, // 58: inventory.InventoryService.CreateProduct:output_type -> inventory.ProductResponse
+	13, // 59: inventory.InventoryService.GetProductByID:output_type -> inventory.ProductResponse
+	13, // 60: inventory.InventoryService.UpdateProduct:output_type -> inventory.ProductResponse
+	45, // 61: inventory.InventoryService.DeleteProduct:output_type -> google.protobuf.Empty
+	13, // 62: inventory.InventoryService.RestoreProduct:output_type -> inventory.ProductResponse
+	14, // 63: inventory.InventoryService.ListProducts:output_type -> inventory.ListProductsResponse
+	12, // 64: inventory.InventoryService.GetProductsByIDs:output_type -> inventory.GetProductsByIDsResponse
+	22, // 65: inventory.InventoryService.SearchProducts:output_type -> inventory.SearchProductsResponse
+	19, // 66: inventory.InventoryService.GetProductFacets:output_type -> inventory.GetProductFacetsResponse
+	31, // 67: inventory.InventoryService.CreateCategory:output_type -> inventory.CategoryResponse
+	31, // 68: inventory.InventoryService.GetCategoryByID:output_type -> inventory.CategoryResponse
+	31, // 69: inventory.InventoryService.UpdateCategory:output_type -> inventory.CategoryResponse
+	45, // 70: inventory.InventoryService.DeleteCategory:output_type -> google.protobuf.Empty
+	31, // 71: inventory.InventoryService.RestoreCategory:output_type -> inventory.CategoryResponse
+	34, // 72: inventory.InventoryService.GetCategoryTree:output_type -> inventory.GetCategoryTreeResponse
+	35, // 73: inventory.InventoryService.ListCategories:output_type -> inventory.ListCategoriesResponse
+	41, // 74: inventory.InventoryService.ReserveStock:output_type -> inventory.ReservationResponse
+	41, // 75: inventory.InventoryService.CommitReservation:output_type -> inventory.ReservationResponse
+	41, // 76: inventory.InventoryService.ReleaseReservation:output_type -> inventory.ReservationResponse
+	58, // [58:77] is the sub-list for method output_type
+	39, // [39:58] is the sub-list for method input_type
+	39, // [39:39] is the sub-list for extension type_name
+	39, // [39:39] is the sub-list for extension extendee
+	0,  // [0:39] is the sub-list for field type_name
 }
 
 func init() { file_inventory_service_proto_inventory_proto_init() }
@@ -1061,18 +3121,25 @@ func file_inventory_service_proto_inventory_proto_init() {
 	if File_inventory_service_proto_inventory_proto != nil {
 		return
 	}
+	file_inventory_service_proto_inventory_proto_msgTypes[3].OneofWrappers = []any{}
+	file_inventory_service_proto_inventory_proto_msgTypes[6].OneofWrappers = []any{}
+	file_inventory_service_proto_inventory_proto_msgTypes[10].OneofWrappers = []any{}
+	file_inventory_service_proto_inventory_proto_msgTypes[11].OneofWrappers = []any{}
+	file_inventory_service_proto_inventory_proto_msgTypes[14].OneofWrappers = []any{}
+	file_inventory_service_proto_inventory_proto_msgTypes[22].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_inventory_service_proto_inventory_proto_rawDesc), len(file_inventory_service_proto_inventory_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   16,
+			NumEnums:      4,
+			NumMessages:   39,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_inventory_service_proto_inventory_proto_goTypes,
 		DependencyIndexes: file_inventory_service_proto_inventory_proto_depIdxs,
+		EnumInfos:         file_inventory_service_proto_inventory_proto_enumTypes,
 		MessageInfos:      file_inventory_service_proto_inventory_proto_msgTypes,
 	}.Build()
 	File_inventory_service_proto_inventory_proto = out.File
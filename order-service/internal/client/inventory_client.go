@@ -3,8 +3,13 @@ package client
 import (
 	"context"
 	inventorypb "ecommerce-microservices/inventory-service/pb"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/logging"
+	"ecommerce-microservices/pkg/rbac"
+	"ecommerce-microservices/pkg/tracing"
 	"fmt"
-	"log"
+	"log/slog"
+	"sync"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -14,44 +19,157 @@ import (
 
 type InventoryClient interface {
 	GetProduct(ctx context.Context, productID string) (*inventorypb.Product, error)
+	// GetProducts fetches products in batches. Products that do not exist are
+	// not an error: their IDs are returned in missing.
+	GetProducts(ctx context.Context, productIDs []string) (products map[string]*inventorypb.Product, missing []string, err error)
+	ReserveStock(ctx context.Context, items []*inventorypb.StockItem) (*inventorypb.Reservation, error)
+	CommitReservation(ctx context.Context, reservationID string) error
+	ReleaseReservation(ctx context.Context, reservationID string) error
 }
 
+// serviceIdentity is sent with every call: order-service reserves and
+// releases stock on its own behalf, also from background workers.
+var serviceIdentity = identity.Identity{UserID: "order-service", Roles: []string{string(rbac.Service)}}
+
+// productBatchSize matches the per-call limit of InventoryService.GetProductsByIDs.
+const productBatchSize = 100
+
 type grpcInventoryClient struct {
 	conn inventorypb.InventoryServiceClient
 }
 
-func NewInventoryGRPCClient(ctx context.Context, target string) (InventoryClient, *grpc.ClientConn, error) {
-	log.Printf("Connecting to Inventory gRPC Service at %s", target)
-	conn, err := grpc.DialContext(ctx, target, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+// NewInventoryGRPCClient connects to the Inventory Service. Every call is
+// signed by signer with serviceIdentity.
+func NewInventoryGRPCClient(ctx context.Context, target string, signer *identity.Signer) (InventoryClient, *grpc.ClientConn, error) {
+	slog.InfoContext(ctx, "Connecting to Inventory gRPC Service", "target", target)
+	conn, err := grpc.DialContext(ctx, target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithBlock(),
+		tracing.DialOption(),
+		grpc.WithChainUnaryInterceptor(logging.UnaryClientInterceptor(), signer.StaticUnaryClientInterceptor(serviceIdentity)),
+	)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to dial inventory service: %w", err)
 	}
 
 	client := inventorypb.NewInventoryServiceClient(conn)
-	log.Printf("Successfully connected to Inventory gRPC Service")
+	slog.InfoContext(ctx, "Connected to Inventory gRPC Service")
 
 	return &grpcInventoryClient{conn: client}, conn, nil
 }
 
 func (c *grpcInventoryClient) GetProduct(ctx context.Context, productID string) (*inventorypb.Product, error) {
-	log.Printf("gRPC Client: Calling InventoryService.GetProductByID for ID: %s", productID)
+	slog.DebugContext(ctx, "Calling InventoryService.GetProductByID", "product_id", productID)
 	req := &inventorypb.GetProductRequest{Id: productID}
 
 	resp, err := c.conn.GetProductByID(ctx, req)
 	if err != nil {
 		st, ok := status.FromError(err)
 		if ok {
-			log.Printf("gRPC Client: InventoryService.GetProductByID failed with code %s: %s", st.Code(), st.Message())
+			slog.WarnContext(ctx, "InventoryService.GetProductByID failed", "code", st.Code().String(), "error", st.Message())
 			return nil, err
 		}
-		log.Printf("gRPC Client: InventoryService.GetProductByID failed with non-gRPC error: %v", err)
+		slog.ErrorContext(ctx, "InventoryService.GetProductByID failed with non-gRPC error", "error", err)
 		return nil, fmt.Errorf("inventory service call failed: %w", err)
 	}
 	if resp == nil || resp.Product == nil {
-		log.Printf("gRPC Client: Received nil product response for ID: %s", productID)
+		slog.ErrorContext(ctx, "Received nil product response", "product_id", productID)
 		return nil, status.Error(codes.Internal, "received nil product from inventory service")
 	}
 
-	log.Printf("gRPC Client: Received product details for ID: %s, Name: %s", productID, resp.Product.Name)
+	slog.DebugContext(ctx, "Received product details", "product_id", productID, "product_name", resp.Product.Name)
 	return resp.Product, nil
 }
+
+func (c *grpcInventoryClient) GetProducts(ctx context.Context, productIDs []string) (map[string]*inventorypb.Product, []string, error) {
+	var batches [][]string
+	for start := 0; start < len(productIDs); start += productBatchSize {
+		end := min(start+productBatchSize, len(productIDs))
+		batches = append(batches, productIDs[start:end])
+	}
+	slog.DebugContext(ctx, "Calling InventoryService.GetProductsByIDs", "ids", len(productIDs), "batches", len(batches))
+
+	responses := make([]*inventorypb.GetProductsByIDsResponse, len(batches))
+	errs := make([]error, len(batches))
+	var wg sync.WaitGroup
+	for i, batch := range batches {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			responses[i], errs[i] = c.conn.GetProductsByIDs(ctx, &inventorypb.GetProductsByIDsRequest{Ids: batch})
+		}()
+	}
+	wg.Wait()
+
+	products := make(map[string]*inventorypb.Product, len(productIDs))
+	var missing []string
+	for i, resp := range responses {
+		if err := errs[i]; err != nil {
+			if st, ok := status.FromError(err); ok {
+				slog.WarnContext(ctx, "InventoryService.GetProductsByIDs failed", "code", st.Code().String(), "error", st.Message())
+				return nil, nil, err
+			}
+			slog.ErrorContext(ctx, "InventoryService.GetProductsByIDs failed with non-gRPC error", "error", err)
+			return nil, nil, fmt.Errorf("inventory service call failed: %w", err)
+		}
+		for _, p := range resp.Products {
+			products[p.Id] = p
+		}
+		missing = append(missing, resp.MissingIds...)
+	}
+
+	slog.DebugContext(ctx, "Received products", "found", len(products), "missing", len(missing))
+	return products, missing, nil
+}
+
+func (c *grpcInventoryClient) ReserveStock(ctx context.Context, items []*inventorypb.StockItem) (*inventorypb.Reservation, error) {
+	slog.DebugContext(ctx, "Calling InventoryService.ReserveStock", "items", len(items))
+	req := &inventorypb.ReserveStockRequest{Items: items}
+
+	resp, err := c.conn.ReserveStock(ctx, req)
+	if err != nil {
+		if st, ok := status.FromError(err); ok {
+			slog.WarnContext(ctx, "InventoryService.ReserveStock failed", "code", st.Code().String(), "error", st.Message())
+			return nil, err
+		}
+		slog.ErrorContext(ctx, "InventoryService.ReserveStock failed with non-gRPC error", "error", err)
+		return nil, fmt.Errorf("inventory service call failed: %w", err)
+	}
+	if resp == nil || resp.Reservation == nil {
+		slog.ErrorContext(ctx, "Received nil reservation response")
+		return nil, status.Error(codes.Internal, "received nil reservation from inventory service")
+	}
+
+	slog.DebugContext(ctx, "Stock reserved", "reservation_id", resp.Reservation.Id)
+	return resp.Reservation, nil
+}
+
+func (c *grpcInventoryClient) CommitReservation(ctx context.Context, reservationID string) error {
+	slog.DebugContext(ctx, "Calling InventoryService.CommitReservation", "reservation_id", reservationID)
+	req := &inventorypb.CommitReservationRequest{ReservationId: reservationID}
+
+	if _, err := c.conn.CommitReservation(ctx, req); err != nil {
+		if st, ok := status.FromError(err); ok {
+			slog.WarnContext(ctx, "InventoryService.CommitReservation failed", "code", st.Code().String(), "error", st.Message())
+			return err
+		}
+		slog.ErrorContext(ctx, "InventoryService.CommitReservation failed with non-gRPC error", "error", err)
+		return fmt.Errorf("inventory service call failed: %w", err)
+	}
+	return nil
+}
+
+func (c *grpcInventoryClient) ReleaseReservation(ctx context.Context, reservationID string) error {
+	slog.DebugContext(ctx, "Calling InventoryService.ReleaseReservation", "reservation_id", reservationID)
+	req := &inventorypb.ReleaseReservationRequest{ReservationId: reservationID}
+
+	if _, err := c.conn.ReleaseReservation(ctx, req); err != nil {
+		if st, ok := status.FromError(err); ok {
+			slog.WarnContext(ctx, "InventoryService.ReleaseReservation failed", "code", st.Code().String(), "error", st.Message())
+			return err
+		}
+		slog.ErrorContext(ctx, "InventoryService.ReleaseReservation failed with non-gRPC error", "error", err)
+		return fmt.Errorf("inventory service call failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"ecommerce-microservices/order-service/internal/domain"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/rbac"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	customer   = identity.Identity{UserID: "user-1", Roles: []string{string(rbac.Customer)}}
+	orderAdmin = identity.Identity{UserID: "admin-1", Roles: []string{string(rbac.OrderAdmin)}}
+	support    = identity.Identity{UserID: "support-1", Roles: []string{string(rbac.Support)}}
+)
+
+func TestAuthorizeStatusChange(t *testing.T) {
+	tests := []struct {
+		name   string
+		caller identity.Identity
+		to     domain.OrderStatus
+		want   codes.Code
+	}{
+		{"customer cancels", customer, domain.StatusCancelled, codes.OK},
+		{"customer pays", customer, domain.StatusPaid, codes.PermissionDenied},
+		{"customer ships", customer, domain.StatusShipped, codes.PermissionDenied},
+		{"customer completes", customer, domain.StatusCompleted, codes.PermissionDenied},
+		{"customer fails", customer, domain.StatusFailed, codes.PermissionDenied},
+		{"support pays", support, domain.StatusPaid, codes.PermissionDenied},
+		{"admin pays", orderAdmin, domain.StatusPaid, codes.OK},
+		{"admin ships", orderAdmin, domain.StatusShipped, codes.OK},
+		{"admin cancels", orderAdmin, domain.StatusCancelled, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeStatusChange(context.Background(), tt.caller, tt.to)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("authorizeStatusChange() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveUserID(t *testing.T) {
+	tests := []struct {
+		name      string
+		caller    identity.Identity
+		requested string
+		want      string
+		code      codes.Code
+	}{
+		{"own orders by default", customer, "", "user-1", codes.OK},
+		{"own orders explicitly", customer, "user-1", "user-1", codes.OK},
+		{"customer asks for another user", customer, "user-2", "", codes.PermissionDenied},
+		{"reader asks for another user", support, "user-2", "user-2", codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveUserID(context.Background(), tt.caller, tt.requested, orderReaders...)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("resolveUserID() code = %v, want %v", code, tt.code)
+			}
+			if got != tt.want {
+				t.Errorf("resolveUserID() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthorizeOrderAccess(t *testing.T) {
+	order := &domain.Order{ID: primitive.NewObjectID(), UserID: "user-1"}
+	other := identity.Identity{UserID: "user-2", Roles: []string{string(rbac.Customer)}}
+
+	tests := []struct {
+		name       string
+		caller     identity.Identity
+		privileged []rbac.Role
+		want       codes.Code
+	}{
+		{"owner", customer, orderReaders, codes.OK},
+		// Чужой заказ выглядит как несуществующий
+		{"other customer", other, orderReaders, codes.NotFound},
+		{"reader", support, orderReaders, codes.OK},
+		{"reader cannot manage", support, orderManagers, codes.NotFound},
+		{"manager", orderAdmin, orderManagers, codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := authorizeOrderAccess(context.Background(), tt.caller, order, tt.privileged...)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("authorizeOrderAccess() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
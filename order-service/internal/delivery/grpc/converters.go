@@ -13,6 +13,10 @@ func OrderStatusToProto(status domain.OrderStatus) pb.OrderStatus {
 	switch status {
 	case domain.StatusPending:
 		return pb.OrderStatus_PENDING
+	case domain.StatusPaid:
+		return pb.OrderStatus_PAID
+	case domain.StatusShipped:
+		return pb.OrderStatus_SHIPPED
 	case domain.StatusCompleted:
 		return pb.OrderStatus_COMPLETED
 	case domain.StatusCancelled:
@@ -28,6 +32,10 @@ func OrderStatusFromProto(status pb.OrderStatus) domain.OrderStatus {
 	switch status {
 	case pb.OrderStatus_PENDING:
 		return domain.StatusPending
+	case pb.OrderStatus_PAID:
+		return domain.StatusPaid
+	case pb.OrderStatus_SHIPPED:
+		return domain.StatusShipped
 	case pb.OrderStatus_COMPLETED:
 		return domain.StatusCompleted
 	case pb.OrderStatus_CANCELLED:
@@ -58,6 +66,24 @@ func OrderItemsToProto(items []domain.OrderItem) []*pb.OrderItem {
 	return protoItems
 }
 
+func StatusTransitionToProto(t domain.StatusTransition) *pb.StatusTransition {
+	return &pb.StatusTransition{
+		From:      OrderStatusToProto(t.From),
+		To:        OrderStatusToProto(t.To),
+		Actor:     t.Actor,
+		Reason:    t.Reason,
+		ChangedAt: timestamppb.New(t.ChangedAt),
+	}
+}
+
+func StatusHistoryToProto(history []domain.StatusTransition) []*pb.StatusTransition {
+	protoHistory := make([]*pb.StatusTransition, len(history))
+	for i, t := range history {
+		protoHistory[i] = StatusTransitionToProto(t)
+	}
+	return protoHistory
+}
+
 func OrderToProto(o *domain.Order) *pb.Order {
 	if o == nil {
 		return nil
@@ -70,6 +96,7 @@ func OrderToProto(o *domain.Order) *pb.Order {
 		Status:      OrderStatusToProto(o.Status),
 		CreatedAt:   timestamppb.New(o.CreatedAt),
 		UpdatedAt:   timestamppb.New(o.UpdatedAt),
+		History:     StatusHistoryToProto(o.History),
 	}
 }
 
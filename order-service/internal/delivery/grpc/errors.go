@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"ecommerce-microservices/order-service/internal/domain"
+	pb "ecommerce-microservices/order-service/pb"
+	"ecommerce-microservices/pkg/grpcerr"
+	"fmt"
+	"slices"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// errorTranslator maps the errors returned by the stores to gRPC statuses.
+var errorTranslator = grpcerr.NewTranslator("order-service",
+	grpcerr.Rule{Err: domain.ErrNotFound, Code: codes.NotFound, Reason: "NOT_FOUND"},
+	grpcerr.Rule{Err: domain.ErrInvalidID, Code: codes.InvalidArgument, Reason: "INVALID_ID"},
+	grpcerr.Rule{Err: domain.ErrConflict, Code: codes.AlreadyExists, Reason: "ALREADY_EXISTS"},
+	// Клиенты не передают версию заказа, поэтому это не VERSION_MISMATCH,
+	// а гонка изменений статуса, после которой запрос можно повторить
+	grpcerr.Rule{Err: domain.ErrVersionMismatch, Code: codes.Aborted, Reason: "CONCURRENT_MODIFICATION"},
+	grpcerr.Rule{Err: domain.ErrClaimLost, Code: codes.Aborted, Reason: "IDEMPOTENCY_CLAIM_LOST"},
+)
+
+// unknownProductsError reports the order items whose products the inventory
+// does not know, with one field violation per item.
+func unknownProductsError(items []*pb.CreateOrderItemInput, missing []string) error {
+	violations := make([]grpcerr.FieldViolation, 0, len(missing))
+	for i, item := range items {
+		if slices.Contains(missing, item.ProductId) {
+			violations = append(violations, grpcerr.FieldViolation{
+				Field:       fmt.Sprintf("items[%d].product_id", i),
+				Description: fmt.Sprintf("product %s does not exist", item.ProductId),
+			})
+		}
+	}
+	return errorTranslator.Status(codes.FailedPrecondition, "UNKNOWN_PRODUCT",
+		fmt.Sprintf("Products not found: %s", strings.Join(missing, ", ")), violations...)
+}
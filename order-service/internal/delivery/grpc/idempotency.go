@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+
+	pb "ecommerce-microservices/order-service/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// idempotencyKeyMetadata carries the gateway's Idempotency-Key header.
+	idempotencyKeyMetadata = "idempotency-key"
+	// idempotentReplayedMetadata is set on responses that were replayed
+	// from an earlier request with the same key.
+	idempotentReplayedMetadata = "idempotent-replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+func idempotencyKeyFromContext(ctx context.Context) string {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ""
+	}
+	if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+		return values[0]
+	}
+	return ""
+}
+
+// createOrderRequestHash fingerprints the request so that a key reused with a
+// different payload can be told apart from a genuine retry.
+func createOrderRequestHash(req *pb.CreateOrderRequest) (string, error) {
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// createOrderIdempotent creates the order at most once per key of the
+// caller. Replays of a completed request return the original order; a key
+// reused with a different payload, or while the first request is still
+// running, is rejected. A request that outlives its claim and loses it to a
+// retry fails with Aborted instead of creating a second order.
+func (s *OrderServer) createOrderIdempotent(ctx context.Context, callerID, key string, req *pb.CreateOrderRequest) (*pb.OrderResponse, error) {
+	if len(key) > maxIdempotencyKeyLength {
+		return nil, status.Errorf(codes.InvalidArgument, "Idempotency key must not be longer than %d characters", maxIdempotencyKeyLength)
+	}
+
+	requestHash, err := createOrderRequestHash(req)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "Failed to process idempotency key: %v", err)
+	}
+
+	record, claimed, err := s.orderStore.ClaimIdempotencyKey(ctx, callerID, key, requestHash)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to claim idempotency key", "idempotency_key", key, "error", err)
+		return nil, status.Errorf(codes.Internal, "Failed to process idempotency key: %v", err)
+	}
+
+	if !claimed {
+		if record.RequestHash != requestHash {
+			slog.WarnContext(ctx, "Idempotency key reused with a different payload", "idempotency_key", key)
+			return nil, status.Errorf(codes.FailedPrecondition, "Idempotency key %s was already used with a different request", key)
+		}
+		if record.OrderID == "" {
+			slog.InfoContext(ctx, "Idempotency key is still being processed", "idempotency_key", key)
+			return nil, status.Errorf(codes.Aborted, "A request with idempotency key %s is still being processed", key)
+		}
+
+		order, err := s.orderStore.GetByID(ctx, record.OrderID)
+		if err != nil {
+			slog.ErrorContext(ctx, "Failed to load order for idempotency key", "order_id", record.OrderID, "idempotency_key", key, "error", err)
+			return nil, status.Errorf(codes.Internal, "Failed to load original order: %v", err)
+		}
+		slog.InfoContext(ctx, "Replaying order for idempotency key", "order_id", record.OrderID, "idempotency_key", key)
+		if err := grpc.SetHeader(ctx, metadata.Pairs(idempotentReplayedMetadata, "true")); err != nil {
+			slog.ErrorContext(ctx, "Failed to set replay header", "error", err)
+		}
+		return &pb.OrderResponse{Order: OrderToProto(order)}, nil
+	}
+
+	// Ключ связывается с заказом в транзакции создания заказа
+	resp, err := s.createOrder(ctx, req, record)
+	if err != nil {
+		if releaseErr := s.orderStore.ReleaseIdempotencyKey(context.WithoutCancel(ctx), record); releaseErr != nil {
+			slog.ErrorContext(ctx, "Failed to release idempotency key", "idempotency_key", key, "error", releaseErr)
+		}
+		return nil, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,124 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"ecommerce-microservices/order-service/internal/domain"
+	repo "ecommerce-microservices/order-service/internal/repository"
+	pb "ecommerce-microservices/order-service/pb"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// toDoc converts v to the document a mock server returns for it.
+func toDoc(t *mtest.T, v any) bson.D {
+	t.Helper()
+	data, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v", err)
+	}
+	return doc
+}
+
+// headerStream records the headers a handler sets.
+type headerStream struct {
+	header metadata.MD
+}
+
+func (s *headerStream) Method() string { return "/order.OrderService/CreateOrder" }
+
+func (s *headerStream) SetHeader(md metadata.MD) error {
+	s.header = metadata.Join(s.header, md)
+	return nil
+}
+
+func (s *headerStream) SendHeader(md metadata.MD) error { return s.SetHeader(md) }
+
+func (s *headerStream) SetTrailer(md metadata.MD) error { return nil }
+
+func TestCreateOrderIdempotentExistingKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	req := &pb.CreateOrderRequest{UserId: "user-1", Items: []*pb.CreateOrderItemInput{{ProductId: "p1", Quantity: 2}}}
+	requestHash, err := createOrderRequestHash(req)
+	if err != nil {
+		t.Fatalf("createOrderRequestHash() error = %v", err)
+	}
+	order := &domain.Order{ID: primitive.NewObjectID(), UserID: "user-1", Status: domain.StatusPending}
+
+	tests := []struct {
+		name      string
+		record    domain.IdempotencyRecord
+		wantCode  codes.Code
+		wantOrder bool
+	}{
+		{
+			name:      "replay of a completed request",
+			record:    domain.IdempotencyRecord{RequestHash: requestHash, OrderID: order.ID.Hex()},
+			wantCode:  codes.OK,
+			wantOrder: true,
+		},
+		{
+			name:     "request still in flight",
+			record:   domain.IdempotencyRecord{RequestHash: requestHash, ClaimID: "claim-1"},
+			wantCode: codes.Aborted,
+		},
+		{
+			name:     "different payload",
+			record:   domain.IdempotencyRecord{RequestHash: "other", OrderID: order.ID.Hex()},
+			wantCode: codes.FailedPrecondition,
+		},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			tt.record.UserID, tt.record.Key = "user-1", "key-1"
+			mt.AddMockResponses(
+				mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"}),
+				mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}, bson.E{Key: "nModified", Value: 0}),
+				mtest.CreateCursorResponse(0, "test.idempotency_keys", mtest.FirstBatch, toDoc(mt, tt.record)),
+				mtest.CreateCursorResponse(0, "test.orders", mtest.FirstBatch, toDoc(mt, order)),
+			)
+			server := &OrderServer{orderStore: repo.NewMongoOrderStore(mt.DB)}
+
+			stream := &headerStream{}
+			ctx := grpc.NewContextWithServerTransportStream(context.Background(), stream)
+
+			resp, err := server.createOrderIdempotent(ctx, "user-1", "key-1", req)
+			if code := status.Code(err); code != tt.wantCode {
+				mt.Fatalf("createOrderIdempotent() code = %v, want %v (%v)", code, tt.wantCode, err)
+			}
+			if !tt.wantOrder {
+				return
+			}
+			if resp.GetOrder().GetId() != order.ID.Hex() {
+				mt.Errorf("replayed order = %q, want %q", resp.GetOrder().GetId(), order.ID.Hex())
+			}
+			if got := stream.header.Get(idempotentReplayedMetadata); len(got) != 1 || got[0] != "true" {
+				mt.Errorf("%s header = %v, want true", idempotentReplayedMetadata, got)
+			}
+		})
+	}
+}
+
+func TestCreateOrderIdempotentKeyTooLong(t *testing.T) {
+	server := &OrderServer{}
+	key := strings.Repeat("k", maxIdempotencyKeyLength+1)
+
+	_, err := server.createOrderIdempotent(context.Background(), "user-1", key, &pb.CreateOrderRequest{})
+	if code := status.Code(err); code != codes.InvalidArgument {
+		t.Errorf("createOrderIdempotent() code = %v, want %v", code, codes.InvalidArgument)
+	}
+}
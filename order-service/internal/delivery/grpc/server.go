@@ -2,85 +2,125 @@ package grpc
 
 import (
 	"context"
+	inventorypb "ecommerce-microservices/inventory-service/pb"
 	invClient "ecommerce-microservices/order-service/internal/client"
 	"ecommerce-microservices/order-service/internal/domain"
 	repo "ecommerce-microservices/order-service/internal/repository"
+	"ecommerce-microservices/order-service/internal/saga"
+	"ecommerce-microservices/order-service/internal/worker"
 	pb "ecommerce-microservices/order-service/pb"
+	"ecommerce-microservices/pkg/grpcerr"
+	"ecommerce-microservices/pkg/logging"
+	"ecommerce-microservices/pkg/pagination"
 	"errors"
-	"log"
+	"fmt"
+	"log/slog"
+	"time"
 
-	"go.mongodb.org/mongo-driver/bson/primitive"
-	"go.mongodb.org/mongo-driver/mongo"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// systemActor is recorded in the status history for changes that were not
+// requested by a user.
+const systemActor = "system"
+
 type OrderServer struct {
 	pb.UnimplementedOrderServiceServer
 	orderStore      *repo.MongoOrderStore
 	inventoryClient invClient.InventoryClient
+	stockReleaser   *worker.StockReleaser
 }
 
-func NewOrderServer(os *repo.MongoOrderStore, ic invClient.InventoryClient) *OrderServer {
+func NewOrderServer(os *repo.MongoOrderStore, ic invClient.InventoryClient, sr *worker.StockReleaser) *OrderServer {
 	if os == nil {
-		log.Fatalf("MongoOrderStore cannot be nil")
+		logging.Fatal("MongoOrderStore cannot be nil")
 	}
 	if ic == nil {
-		log.Fatalf("InventoryClient cannot be nil")
+		logging.Fatal("InventoryClient cannot be nil")
+	}
+	if sr == nil {
+		logging.Fatal("StockReleaser cannot be nil")
 	}
 	return &OrderServer{
 		orderStore:      os,
 		inventoryClient: ic,
+		stockReleaser:   sr,
 	}
 }
 
 func (s *OrderServer) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.OrderResponse, error) {
-	log.Printf("Received CreateOrder request for user %s", req.UserId)
-	if req.UserId == "" || len(req.Items) == 0 {
-		log.Printf("CreateOrder validation failed: UserID or Items empty")
-		return nil, status.Error(codes.InvalidArgument, "UserID and at least one item are required")
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserId, err = resolveUserID(ctx, caller, req.UserId); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Received CreateOrder request", "user_id", req.UserId)
+	if len(req.Items) == 0 {
+		slog.InfoContext(ctx, "CreateOrder validation failed: items empty")
+		return nil, grpcerr.InvalidArgumentf("items", "At least one item is required")
 	}
 
-	var orderItems []domain.OrderItem
-	var totalAmount float64
-	productIDs := make(map[string]bool)
+	if key := idempotencyKeyFromContext(ctx); key != "" {
+		return s.createOrderIdempotent(ctx, caller.UserID, key, req)
+	}
+	return s.createOrder(ctx, req, nil)
+}
 
-	for _, itemInput := range req.Items {
+// createOrder creates the order described by req. If claim is not nil, the
+// order is only persisted while claim still holds its idempotency key.
+func (s *OrderServer) createOrder(ctx context.Context, req *pb.CreateOrderRequest, claim *domain.IdempotencyRecord) (*pb.OrderResponse, error) {
+	productIDs := make([]string, 0, len(req.Items))
+	seen := make(map[string]bool)
+
+	for i, itemInput := range req.Items {
 		if itemInput.ProductId == "" || itemInput.Quantity <= 0 {
-			log.Printf("CreateOrder validation failed: Invalid item data: ProductID '%s', Quantity %d", itemInput.ProductId, itemInput.Quantity)
-			return nil, status.Errorf(codes.InvalidArgument, "Invalid item data: ProductID '%s', Quantity %d", itemInput.ProductId, itemInput.Quantity)
+			slog.InfoContext(ctx, "CreateOrder validation failed: invalid item data", "product_id", itemInput.ProductId, "quantity", itemInput.Quantity)
+			field := fmt.Sprintf("items[%d].quantity", i)
+			if itemInput.ProductId == "" {
+				field = fmt.Sprintf("items[%d].product_id", i)
+			}
+			return nil, grpcerr.InvalidArgumentf(field, "Invalid item data: ProductID '%s', Quantity %d", itemInput.ProductId, itemInput.Quantity)
 		}
-		if _, exists := productIDs[itemInput.ProductId]; exists {
-			log.Printf("CreateOrder validation failed: Duplicate product ID %s", itemInput.ProductId)
-			return nil, status.Errorf(codes.InvalidArgument, "Duplicate product ID in order: %s", itemInput.ProductId)
+		if seen[itemInput.ProductId] {
+			slog.InfoContext(ctx, "CreateOrder validation failed: duplicate product ID", "product_id", itemInput.ProductId)
+			return nil, grpcerr.InvalidArgumentf(fmt.Sprintf("items[%d].product_id", i), "Duplicate product ID in order: %s", itemInput.ProductId)
 		}
-		productIDs[itemInput.ProductId] = true
+		seen[itemInput.ProductId] = true
+		productIDs = append(productIDs, itemInput.ProductId)
+	}
 
-		log.Printf("Calling Inventory Service for product data (price): %s", itemInput.ProductId)
-		productInfo, err := s.inventoryClient.GetProduct(ctx, itemInput.ProductId)
-		if err != nil {
-			log.Printf("Failed to get product %s from inventory: %v", itemInput.ProductId, err)
-			st, ok := status.FromError(err)
-			if ok {
-				if st.Code() == codes.NotFound {
-					return nil, status.Errorf(codes.FailedPrecondition, "Product not found: %s", itemInput.ProductId)
-				}
-				if st.Code() == codes.InvalidArgument {
-					return nil, status.Errorf(codes.FailedPrecondition, "Invalid Product ID format for inventory check: %s", itemInput.ProductId)
-				}
-				return nil, status.Errorf(codes.Internal, "Failed to verify product %s: %s", itemInput.ProductId, st.Message())
-			}
-			return nil, status.Errorf(codes.Internal, "Internal error verifying product %s: %v", itemInput.ProductId, err)
+	slog.DebugContext(ctx, "Calling Inventory Service for product prices", "products", len(productIDs))
+	products, missing, err := s.inventoryClient.GetProducts(ctx, productIDs)
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to get products from inventory", "error", err)
+		if st, ok := status.FromError(err); ok {
+			return nil, status.Errorf(codes.Internal, "Failed to verify products: %s", st.Message())
 		}
+		return nil, status.Errorf(codes.Internal, "Internal error verifying products: %v", err)
+	}
+	if len(missing) > 0 {
+		slog.InfoContext(ctx, "CreateOrder failed: products not found", "missing_product_ids", missing)
+		return nil, unknownProductsError(req.Items, missing)
+	}
 
-		orderItem := domain.OrderItem{
+	orderItems := make([]domain.OrderItem, 0, len(req.Items))
+	var totalAmount float64
+	for _, itemInput := range req.Items {
+		productInfo, ok := products[itemInput.ProductId]
+		if !ok {
+			return nil, unknownProductsError(req.Items, []string{itemInput.ProductId})
+		}
+		orderItems = append(orderItems, domain.OrderItem{
 			ProductID:    itemInput.ProductId,
 			Quantity:     int(itemInput.Quantity),
 			PriceAtOrder: productInfo.Price,
-		}
-		orderItems = append(orderItems, orderItem)
+		})
 		totalAmount += productInfo.Price * float64(itemInput.Quantity)
-		log.Printf("Product %s (%s) price %.2f obtained. Requested Qty: %d", productInfo.Name, itemInput.ProductId, productInfo.Price, itemInput.Quantity)
+		slog.DebugContext(ctx, "Product price obtained", "product_id", itemInput.ProductId, "product_name", productInfo.Name, "price", productInfo.Price, "quantity", itemInput.Quantity)
 	}
 
 	newOrder := &domain.Order{
@@ -88,95 +128,209 @@ func (s *OrderServer) CreateOrder(ctx context.Context, req *pb.CreateOrderReques
 		Items:       orderItems,
 		TotalAmount: totalAmount,
 		Status:      domain.StatusPending,
+		History: []domain.StatusTransition{{
+			To:        domain.StatusPending,
+			Actor:     req.UserId,
+			Reason:    "order created",
+			ChangedAt: time.Now(),
+		}},
 	}
 
-	log.Printf("Attempting to create order in DB for user %s with %d items, total: %.2f", req.UserId, len(orderItems), totalAmount)
-	createErr := s.orderStore.Create(ctx, newOrder)
-	if createErr != nil {
-		log.Printf("Error saving order to database: %v", createErr)
-		return nil, status.Errorf(codes.Internal, "Failed to create order in database: %v", createErr)
+	if err := s.createOrderSaga(newOrder, claim).Execute(ctx); err != nil {
+		return nil, err
 	}
 
-	log.Printf("Order %s created successfully for user %s (stock not checked)", newOrder.ID.Hex(), newOrder.UserID)
+	slog.InfoContext(ctx, "Order created", "order_id", newOrder.ID.Hex(), "user_id", newOrder.UserID, "reservation_id", newOrder.ReservationID)
 	return &pb.OrderResponse{Order: OrderToProto(newOrder)}, nil
 }
 
+// createOrderSaga reserves stock for every item, persists the order and then
+// commits the reservation. If a step fails, the reservation is released and
+// an already persisted order is marked as failed.
+func (s *OrderServer) createOrderSaga(order *domain.Order, claim *domain.IdempotencyRecord) *saga.Saga {
+	stockItems := make([]*inventorypb.StockItem, len(order.Items))
+	for i, item := range order.Items {
+		stockItems[i] = &inventorypb.StockItem{
+			ProductId: item.ProductID,
+			Quantity:  int32(item.Quantity),
+		}
+	}
+
+	return saga.New("CreateOrder").
+		AddStep(saga.Step{
+			Name: "reserve-stock",
+			Action: func(ctx context.Context) error {
+				reservation, err := s.inventoryClient.ReserveStock(ctx, stockItems)
+				if err != nil {
+					st, ok := status.FromError(err)
+					if ok {
+						switch st.Code() {
+						case codes.FailedPrecondition:
+							return status.Errorf(codes.FailedPrecondition, "Insufficient stock: %s", st.Message())
+						case codes.NotFound, codes.InvalidArgument:
+							return status.Errorf(codes.FailedPrecondition, "Failed to reserve stock: %s", st.Message())
+						}
+						return status.Errorf(codes.Internal, "Failed to reserve stock: %s", st.Message())
+					}
+					return status.Errorf(codes.Internal, "Internal error reserving stock: %v", err)
+				}
+				order.ReservationID = reservation.Id
+				return nil
+			},
+			Compensate: func(ctx context.Context) error {
+				return s.inventoryClient.ReleaseReservation(ctx, order.ReservationID)
+			},
+		}).
+		AddStep(saga.Step{
+			Name: "persist-order",
+			Action: func(ctx context.Context) error {
+				slog.DebugContext(ctx, "Creating order in DB", "user_id", order.UserID, "items", len(order.Items), "total_amount", order.TotalAmount)
+				var err error
+				if claim != nil {
+					err = s.orderStore.CreateIdempotent(ctx, order, claim)
+				} else {
+					err = s.orderStore.Create(ctx, order)
+				}
+				if err != nil {
+					slog.ErrorContext(ctx, "Error saving order to database", "error", err)
+					return errorTranslator.Error(err, "Failed to create order in database")
+				}
+				return nil
+			},
+			Compensate: func(ctx context.Context) error {
+				return s.orderStore.UpdateStatus(ctx, order.ID.Hex(), domain.StatusTransition{
+					From:   domain.StatusPending,
+					To:     domain.StatusFailed,
+					Actor:  systemActor,
+					Reason: "order creation failed",
+				})
+			},
+		}).
+		AddStep(saga.Step{
+			Name: "commit-reservation",
+			Action: func(ctx context.Context) error {
+				if err := s.inventoryClient.CommitReservation(ctx, order.ReservationID); err != nil {
+					return status.Errorf(codes.Internal, "Failed to commit stock reservation %s: %v", order.ReservationID, err)
+				}
+				return nil
+			},
+		})
+}
+
 func (s *OrderServer) GetOrderByID(ctx context.Context, req *pb.GetOrderRequest) (*pb.OrderResponse, error) {
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "Order ID is required")
+		return nil, grpcerr.InvalidArgumentf("id", "Order ID is required")
 	}
-	log.Printf("Received GetOrderByID request for ID: %s", req.Id)
+	slog.InfoContext(ctx, "Received GetOrderByID request", "order_id", req.Id)
 
-	_, err := primitive.ObjectIDFromHex(req.Id)
+	caller, err := callerFromContext(ctx)
 	if err != nil {
-		log.Printf("Invalid order ID format: %s, error: %v", req.Id, err)
-		return nil, status.Errorf(codes.InvalidArgument, "Invalid order ID format: %s", req.Id)
+		return nil, err
 	}
 
 	order, err := s.orderStore.GetByID(ctx, req.Id)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			log.Printf("Order %s not found", req.Id)
-			return nil, status.Errorf(codes.NotFound, "Order with ID %s not found", req.Id)
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidID) {
+			slog.InfoContext(ctx, "Order not found", "order_id", req.Id, "error", err)
+		} else {
+			slog.ErrorContext(ctx, "Failed to get order", "order_id", req.Id, "error", err)
 		}
-		log.Printf("Failed to get order %s: %v", req.Id, err)
-		return nil, status.Errorf(codes.Internal, "Failed to get order: %v", err)
+		return nil, errorTranslator.Error(err, "Failed to get order")
+	}
+	if err := authorizeOrderAccess(ctx, caller, order, orderReaders...); err != nil {
+		return nil, err
 	}
 
-	log.Printf("Order %s found for user %s", req.Id, order.UserID)
+	slog.DebugContext(ctx, "Order found", "order_id", req.Id, "user_id", order.UserID)
 	return &pb.OrderResponse{Order: OrderToProto(order)}, nil
 }
 
 func (s *OrderServer) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrderStatusRequest) (*pb.OrderResponse, error) {
 	if req.Id == "" {
-		return nil, status.Error(codes.InvalidArgument, "Order ID is required")
+		return nil, grpcerr.InvalidArgumentf("id", "Order ID is required")
 	}
-	log.Printf("Received UpdateOrderStatus request for ID: %s to status %s", req.Id, req.Status)
+	slog.InfoContext(ctx, "Received UpdateOrderStatus request", "order_id", req.Id, "status", req.Status.String())
 
-	_, err := primitive.ObjectIDFromHex(req.Id)
+	caller, err := callerFromContext(ctx)
 	if err != nil {
-		log.Printf("Invalid order ID format for status update: %s, error: %v", req.Id, err)
-		return nil, status.Errorf(codes.InvalidArgument, "Invalid order ID format: %s", req.Id)
+		return nil, err
 	}
 
+	if req.Status == pb.OrderStatus_ORDER_STATUS_UNSPECIFIED {
+		slog.InfoContext(ctx, "Invalid target status", "order_id", req.Id, "status", req.Status.String())
+		return nil, grpcerr.InvalidArgumentf("status", "Invalid target status specified: %s", req.Status)
+	}
 	newStatusDomain := OrderStatusFromProto(req.Status)
+	if !newStatusDomain.IsValid() {
+		slog.InfoContext(ctx, "Invalid target status", "order_id", req.Id, "status", req.Status.String(), "domain_status", newStatusDomain)
+		return nil, grpcerr.InvalidArgumentf("status", "Invalid target status specified: %s", req.Status)
+	}
 
-	isValidDomainStatus := false
-	switch newStatusDomain {
-	case domain.StatusPending, domain.StatusCompleted, domain.StatusCancelled, domain.StatusFailed:
-		isValidDomainStatus = true
+	order, err := s.orderStore.GetByID(ctx, req.Id)
+	if err != nil {
+		if errors.Is(err, domain.ErrNotFound) || errors.Is(err, domain.ErrInvalidID) {
+			slog.InfoContext(ctx, "Order not found for status update", "order_id", req.Id, "error", err)
+		} else {
+			slog.ErrorContext(ctx, "Failed to get order for status update", "order_id", req.Id, "error", err)
+		}
+		return nil, errorTranslator.Error(err, "Failed to get order")
+	}
+	if err := authorizeOrderAccess(ctx, caller, order, orderManagers...); err != nil {
+		return nil, err
+	}
+	if err := authorizeStatusChange(ctx, caller, newStatusDomain); err != nil {
+		return nil, err
 	}
 
-	if req.Status == pb.OrderStatus_ORDER_STATUS_UNSPECIFIED || !isValidDomainStatus {
-		log.Printf("Invalid target status for order %s: proto status %s (domain status '%s')", req.Id, req.Status, newStatusDomain)
-		return nil, status.Errorf(codes.InvalidArgument, "Invalid target status specified: %s", req.Status)
+	if !order.Status.CanTransitionTo(newStatusDomain) {
+		slog.InfoContext(ctx, "Rejected status transition", "order_id", req.Id, "from", order.Status, "to", newStatusDomain)
+		return nil, status.Errorf(codes.FailedPrecondition, "Cannot change order status from %s to %s (allowed: %v)", order.Status, newStatusDomain, order.Status.AllowedTransitions())
 	}
 
-	err = s.orderStore.UpdateStatus(ctx, req.Id, newStatusDomain)
+	err = s.orderStore.UpdateStatus(ctx, req.Id, domain.StatusTransition{
+		From:   order.Status,
+		To:     newStatusDomain,
+		Actor:  caller.UserID,
+		Reason: req.Reason,
+	})
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			log.Printf("Order %s not found for status update", req.Id)
-			return nil, status.Errorf(codes.NotFound, "Order with ID %s not found to update status", req.Id)
+		switch {
+		case errors.Is(err, domain.ErrVersionMismatch):
+			slog.WarnContext(ctx, "Order status changed concurrently", "order_id", req.Id, "error", err)
+		case errors.Is(err, domain.ErrNotFound):
+			slog.InfoContext(ctx, "Order not found for status update", "order_id", req.Id)
+		default:
+			slog.ErrorContext(ctx, "Failed to update order status", "order_id", req.Id, "error", err)
+		}
+		return nil, errorTranslator.Error(err, "Failed to update order status")
+	}
+
+	if newStatusDomain.ReleasesStock() {
+		// При ошибке сток вернет фоновый StockReleaser
+		if err := s.stockReleaser.Release(ctx, order); err != nil {
+			slog.WarnContext(ctx, "Failed to release stock of order, will retry in background", "order_id", req.Id, "error", err)
 		}
-		log.Printf("Failed to update status for order %s: %v", req.Id, err)
-		return nil, status.Errorf(codes.Internal, "Failed to update order status: %v", err)
 	}
 
 	updatedOrder, err := s.orderStore.GetByID(ctx, req.Id)
 	if err != nil {
-		log.Printf("Failed to retrieve updated order %s after status change: %v", req.Id, err)
+		slog.ErrorContext(ctx, "Failed to retrieve updated order after status change", "order_id", req.Id, "error", err)
 		return nil, status.Errorf(codes.Internal, "Failed to retrieve updated order details after status change: %v", err)
 	}
 
-	log.Printf("Order %s status updated successfully to %s (domain: '%s')", req.Id, req.Status, newStatusDomain)
+	slog.InfoContext(ctx, "Order status updated", "order_id", req.Id, "status", newStatusDomain)
 	return &pb.OrderResponse{Order: OrderToProto(updatedOrder)}, nil
 }
 
 func (s *OrderServer) ListUserOrders(ctx context.Context, req *pb.ListOrdersRequest) (*pb.ListOrdersResponse, error) {
-	if req.UserId == "" {
-		return nil, status.Error(codes.InvalidArgument, "User ID is required to list orders")
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if req.UserId, err = resolveUserID(ctx, caller, req.UserId, orderReaders...); err != nil {
+		return nil, err
 	}
-	log.Printf("Received ListUserOrders request for user %s, PageSize: %d, PageNumber: %d", req.UserId, req.PageSize, req.PageNumber)
+	slog.InfoContext(ctx, "Received ListUserOrders request", "user_id", req.UserId, "page_size", req.PageSize, "page_number", req.PageNumber)
 
 	limit := int64(req.PageSize)
 	if limit <= 0 {
@@ -185,22 +339,116 @@ func (s *OrderServer) ListUserOrders(ctx context.Context, req *pb.ListOrdersRequ
 	if limit > 100 {
 		limit = 100
 	}
-	page := int64(req.PageNumber)
-	if page <= 0 {
-		page = 1
+	pageNumber := int64(req.PageNumber)
+	if pageNumber <= 0 {
+		pageNumber = 1
+	}
+	offset := (pageNumber - 1) * limit
+
+	// Токен привязан к пользователю, чей список он продолжает
+	fingerprint := pagination.Fingerprint(req.UserId)
+	page, err := listPage(req.PageToken, req.PageNumber, fingerprint)
+	if err != nil {
+		return nil, err
 	}
-	offset := (page - 1) * limit
+	page.Limit = limit
+	page.Offset = offset
+	page.CountTotal = !req.SkipTotalCount
 
-	log.Printf("Listing orders for user %s with limit %d, offset %d (page %d)", req.UserId, limit, offset, page)
-	orders, total, err := s.orderStore.ListByUserID(ctx, req.UserId, limit, offset)
+	slog.DebugContext(ctx, "Listing orders", "user_id", req.UserId, "limit", limit, "offset", offset, "page", pageNumber, "keyset", page.After != nil)
+	orders, next, total, err := s.orderStore.ListByUserID(ctx, req.UserId, page)
 	if err != nil {
-		log.Printf("Failed to list orders for user %s: %v", req.UserId, err)
+		slog.ErrorContext(ctx, "Failed to list orders", "user_id", req.UserId, "error", err)
 		return nil, status.Errorf(codes.Internal, "Failed to list orders for user %s", req.UserId)
 	}
 
-	log.Printf("Found %d orders (total %d) for user %s", len(orders), total, req.UserId)
-	return &pb.ListOrdersResponse{
-		Orders:     OrdersToProto(orders),
-		TotalCount: total,
-	}, nil
+	slog.DebugContext(ctx, "Orders found", "user_id", req.UserId, "count", len(orders), "total", total)
+	resp := &pb.ListOrdersResponse{Orders: OrdersToProto(orders)}
+	if page.CountTotal {
+		resp.TotalCount = &total
+	}
+	if next != nil {
+		if resp.NextPageToken, err = pagination.EncodeToken(*next, fingerprint); err != nil {
+			return nil, status.Errorf(codes.Internal, "Failed to list orders for user %s: %v", req.UserId, err)
+		}
+	}
+	return resp, nil
+}
+
+// listPage starts the page of a list request. A page token continues the
+// list it was issued for and cannot be combined with a page number.
+func listPage(pageToken string, pageNumber int32, fingerprint string) (pagination.Page, error) {
+	if pageToken == "" {
+		return pagination.Page{}, nil
+	}
+	if pageNumber > 1 {
+		return pagination.Page{}, grpcerr.InvalidArgumentf("page_number", "Page number cannot be combined with a page token")
+	}
+	after, err := pagination.DecodeToken(pageToken, fingerprint)
+	if err != nil {
+		return pagination.Page{}, grpcerr.InvalidArgumentf("page_token", "Page token is invalid or was issued for a different user")
+	}
+	return pagination.Page{After: &after}, nil
+}
+
+// WatchOrder sends the current order and then a new event for every status
+// change, until the order reaches a terminal status or the client leaves.
+// Event sequence numbers are the length of the status history, so clients
+// can tell which changes they have already seen.
+func (s *OrderServer) WatchOrder(req *pb.GetOrderRequest, stream grpc.ServerStreamingServer[pb.OrderEvent]) error {
+	if req.Id == "" {
+		return grpcerr.InvalidArgumentf("id", "Order ID is required")
+	}
+	ctx := stream.Context()
+	slog.InfoContext(ctx, "Received WatchOrder request", "order_id", req.Id)
+
+	caller, err := callerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	changes, err := s.orderStore.WatchByID(ctx, req.Id)
+	if err != nil {
+		if !errors.Is(err, domain.ErrInvalidID) {
+			slog.ErrorContext(ctx, "Failed to watch order", "order_id", req.Id, "error", err)
+		}
+		return errorTranslator.Error(err, "Failed to watch order")
+	}
+	defer changes.Close(context.WithoutCancel(ctx))
+
+	order, err := s.orderStore.GetByID(ctx, req.Id)
+	if err != nil {
+		if !errors.Is(err, domain.ErrNotFound) {
+			slog.ErrorContext(ctx, "Failed to get order", "order_id", req.Id, "error", err)
+		}
+		return errorTranslator.Error(err, "Failed to get order")
+	}
+	if err := authorizeOrderAccess(ctx, caller, order, orderReaders...); err != nil {
+		return err
+	}
+
+	lastSequence := int64(-1)
+	for {
+		if sequence := int64(len(order.History)); sequence > lastSequence {
+			if err := stream.Send(&pb.OrderEvent{Sequence: sequence, Order: OrderToProto(order)}); err != nil {
+				slog.WarnContext(ctx, "Failed to send order event", "order_id", req.Id, "error", err)
+				return err
+			}
+			lastSequence = sequence
+		}
+		if order.Status.IsTerminal() {
+			slog.InfoContext(ctx, "Order reached terminal status, closing watch", "order_id", req.Id, "status", order.Status)
+			return nil
+		}
+
+		order, err = changes.Next(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				slog.InfoContext(ctx, "Client stopped watching order", "order_id", req.Id)
+				return status.FromContextError(ctx.Err()).Err()
+			}
+			slog.ErrorContext(ctx, "Failed to watch order", "order_id", req.Id, "error", err)
+			return status.Errorf(codes.Internal, "Failed to watch order: %v", err)
+		}
+	}
 }
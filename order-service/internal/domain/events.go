@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// EventSource identifies this service in published events.
+const EventSource = "order-service"
+
+const (
+	EventOrderCreated       = "OrderCreated"
+	EventOrderStatusChanged = "OrderStatusChanged"
+)
+
+type OrderStatusChangedPayload struct {
+	OrderID   string      `json:"order_id"`
+	UserID    string      `json:"user_id"`
+	From      OrderStatus `json:"from"`
+	To        OrderStatus `json:"to"`
+	Actor     string      `json:"actor"`
+	Reason    string      `json:"reason,omitempty"`
+	ChangedAt time.Time   `json:"changed_at"`
+}
@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord maps a client supplied Idempotency-Key to the order it
+// created. Keys are scoped to the user who sent them. OrderID is empty while
+// the first request is still in flight; such a claim is held by ClaimID
+// until LockedUntil, after which a retry may take it over.
+type IdempotencyRecord struct {
+	UserID      string    `json:"user_id" bson:"user_id"`
+	Key         string    `json:"key" bson:"key"`
+	RequestHash string    `json:"request_hash" bson:"request_hash"`
+	OrderID     string    `json:"order_id,omitempty" bson:"order_id,omitempty"`
+	ClaimID     string    `json:"claim_id,omitempty" bson:"claim_id,omitempty"`
+	LockedUntil time.Time `json:"locked_until,omitempty" bson:"locked_until,omitempty"`
+	CreatedAt   time.Time `json:"created_at" bson:"created_at"`
+}
+
+// ClaimLostError reports that the claim on key ran out and was taken over by
+// a retry before the request holding it could finish.
+func ClaimLostError(key string) *Error {
+	return NewError(ErrClaimLost, ResourceIdempotencyKey, key, "idempotency key %s was taken over by a retry", key)
+}
@@ -10,19 +10,43 @@ type OrderStatus string
 
 const (
 	StatusPending   OrderStatus = "pending"
+	StatusPaid      OrderStatus = "paid"
+	StatusShipped   OrderStatus = "shipped"
 	StatusCompleted OrderStatus = "completed"
 	StatusCancelled OrderStatus = "cancelled"
 	StatusFailed    OrderStatus = "failed"
 )
 
+// StockReleaseStatus tracks returning the reserved stock of a cancelled or
+// failed order to the Inventory Service.
+type StockReleaseStatus string
+
+const (
+	StockReleasePending  StockReleaseStatus = "pending"
+	StockReleaseReleased StockReleaseStatus = "released"
+)
+
+// StatusTransition is one entry of the order status history. From is empty
+// for the entry written when the order is created.
+type StatusTransition struct {
+	From      OrderStatus `json:"from,omitempty" bson:"from,omitempty"`
+	To        OrderStatus `json:"to" bson:"to"`
+	Actor     string      `json:"actor" bson:"actor"`
+	Reason    string      `json:"reason,omitempty" bson:"reason,omitempty"`
+	ChangedAt time.Time   `json:"changed_at" bson:"changed_at"`
+}
+
 type Order struct {
-	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
-	UserID      string             `json:"user_id" bson:"user_id" binding:"required"`
-	Items       []OrderItem        `json:"items" bson:"items" binding:"required,dive"`
-	TotalAmount float64            `json:"total_amount" bson:"total_amount"`
-	Status      OrderStatus        `json:"status" bson:"status"`
-	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
-	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID        string             `json:"user_id" bson:"user_id" binding:"required"`
+	Items         []OrderItem        `json:"items" bson:"items" binding:"required,dive"`
+	TotalAmount   float64            `json:"total_amount" bson:"total_amount"`
+	Status        OrderStatus        `json:"status" bson:"status"`
+	ReservationID string             `json:"reservation_id,omitempty" bson:"reservation_id,omitempty"`
+	StockRelease  StockReleaseStatus `json:"stock_release,omitempty" bson:"stock_release,omitempty"`
+	History       []StatusTransition `json:"history" bson:"history"`
+	CreatedAt     time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt     time.Time          `json:"updated_at" bson:"updated_at"`
 }
 
 type CreateOrderInput struct {
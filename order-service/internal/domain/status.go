@@ -0,0 +1,41 @@
+package domain
+
+// statusTransitions lists, for every status, the statuses an order may move
+// to next. Statuses without an entry are terminal.
+var statusTransitions = map[OrderStatus][]OrderStatus{
+	StatusPending: {StatusPaid, StatusCancelled, StatusFailed},
+	StatusPaid:    {StatusShipped, StatusCancelled},
+	StatusShipped: {StatusCompleted},
+}
+
+func (s OrderStatus) IsValid() bool {
+	switch s {
+	case StatusPending, StatusPaid, StatusShipped, StatusCompleted, StatusCancelled, StatusFailed:
+		return true
+	}
+	return false
+}
+
+func (s OrderStatus) IsTerminal() bool {
+	return len(statusTransitions[s]) == 0
+}
+
+// ReleasesStock reports whether an order entering s gives its reserved stock
+// back to the inventory.
+func (s OrderStatus) ReleasesStock() bool {
+	return s == StatusCancelled || s == StatusFailed
+}
+
+func (s OrderStatus) CanTransitionTo(next OrderStatus) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// AllowedTransitions returns the statuses reachable from s in one step.
+func (s OrderStatus) AllowedTransitions() []OrderStatus {
+	return statusTransitions[s]
+}
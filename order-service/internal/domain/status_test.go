@@ -0,0 +1,90 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+var allStatuses = []OrderStatus{
+	StatusPending, StatusPaid, StatusShipped, StatusCompleted, StatusCancelled, StatusFailed,
+}
+
+func TestCanTransitionTo(t *testing.T) {
+	// Все разрешенные переходы; любой другой переход запрещен
+	allowed := map[OrderStatus][]OrderStatus{
+		StatusPending: {StatusPaid, StatusCancelled, StatusFailed},
+		StatusPaid:    {StatusShipped, StatusCancelled},
+		StatusShipped: {StatusCompleted},
+	}
+
+	for _, from := range allStatuses {
+		for _, to := range allStatuses {
+			want := slices.Contains(allowed[from], to)
+			if got := from.CanTransitionTo(to); got != want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", from, to, got, want)
+			}
+		}
+	}
+}
+
+func TestCanTransitionToUnknownStatus(t *testing.T) {
+	tests := []struct {
+		from, to OrderStatus
+	}{
+		{StatusPending, "refunded"},
+		{"refunded", StatusPaid},
+		{"", StatusPending},
+		{StatusPending, ""},
+	}
+
+	for _, tt := range tests {
+		if tt.from.CanTransitionTo(tt.to) {
+			t.Errorf("%q.CanTransitionTo(%q) = true, want false", tt.from, tt.to)
+		}
+	}
+}
+
+func TestStatusProperties(t *testing.T) {
+	tests := []struct {
+		status        OrderStatus
+		valid         bool
+		terminal      bool
+		releasesStock bool
+	}{
+		{StatusPending, true, false, false},
+		{StatusPaid, true, false, false},
+		{StatusShipped, true, false, false},
+		{StatusCompleted, true, true, false},
+		{StatusCancelled, true, true, true},
+		{StatusFailed, true, true, true},
+		{"refunded", false, true, false},
+		{"", false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			if got := tt.status.IsValid(); got != tt.valid {
+				t.Errorf("IsValid() = %v, want %v", got, tt.valid)
+			}
+			if got := tt.status.IsTerminal(); got != tt.terminal {
+				t.Errorf("IsTerminal() = %v, want %v", got, tt.terminal)
+			}
+			if got := tt.status.ReleasesStock(); got != tt.releasesStock {
+				t.Errorf("ReleasesStock() = %v, want %v", got, tt.releasesStock)
+			}
+		})
+	}
+}
+
+func TestAllowedTransitionsMatchCanTransitionTo(t *testing.T) {
+	for _, from := range allStatuses {
+		for _, to := range from.AllowedTransitions() {
+			if !to.IsValid() {
+				t.Errorf("%s allows transition to unknown status %q", from, to)
+			}
+			if !from.CanTransitionTo(to) {
+				t.Errorf("%s lists %s as allowed, but CanTransitionTo is false", from, to)
+			}
+		}
+	}
+}
@@ -2,8 +2,9 @@ package repository
 
 import (
 	"context"
+	"ecommerce-microservices/pkg/tracing"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/mongo"
@@ -39,9 +40,9 @@ func NewMongoConnection(cfg MongoConfig) (*mongo.Client, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), connectionTimeout)
 	defer cancel()
 
-	log.Printf("Connecting to MongoDB: %s/%s", mongoURI, cfg.DBName)
+	slog.Info("Connecting to MongoDB", "host", cfg.Host, "port", cfg.Port, "db", cfg.DBName)
 
-	clientOptions := options.Client().ApplyURI(mongoURI)
+	clientOptions := options.Client().ApplyURI(mongoURI).SetMonitor(tracing.MongoMonitor())
 	client, err := mongo.Connect(ctx, clientOptions)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to mongodb: %w", err)
@@ -57,7 +58,7 @@ func NewMongoConnection(cfg MongoConfig) (*mongo.Client, error) {
 		return nil, fmt.Errorf("failed to ping mongodb: %w", err)
 	}
 
-	log.Println("Successfully connected and pinged MongoDB!")
+	slog.Info("Connected to MongoDB")
 	return client, nil
 }
 
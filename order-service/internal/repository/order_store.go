@@ -3,9 +3,13 @@ package repository
 import (
 	"context"
 	"ecommerce-microservices/order-service/internal/domain"
+	"ecommerce-microservices/pkg/events"
+	"ecommerce-microservices/pkg/metrics"
+	"ecommerce-microservices/pkg/outbox"
+	"ecommerce-microservices/pkg/pagination"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson"
@@ -14,107 +18,439 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
-const orderCollectionName = "orders"
+const (
+	orderCollectionName          = "orders"
+	idempotencyKeyCollectionName = "idempotency_keys"
 
+	// idempotencyKeyTTL is how long a client may replay a CreateOrder request.
+	idempotencyKeyTTL = 24 * time.Hour
+	// idempotencyLease is how long a claim of a request in flight blocks
+	// retries. It is well above the time CreateOrder takes, so only claims
+	// of crashed requests run out.
+	idempotencyLease = 30 * time.Second
+)
+
+// MongoOrderStore writes a domain event to the outbox in the same
+// transaction as order creation and every status change.
 type MongoOrderStore struct {
-	collection *mongo.Collection
+	collection      *mongo.Collection
+	idempotencyKeys *mongo.Collection
+	outbox          *outbox.Store
 }
 
+var orderStoreMetrics = metrics.NewStoreMetrics("order")
+
 func NewMongoOrderStore(db *mongo.Database) *MongoOrderStore {
 	collection := db.Collection(orderCollectionName)
-	return &MongoOrderStore{collection: collection}
+	return &MongoOrderStore{
+		collection:      collection,
+		idempotencyKeys: db.Collection(idempotencyKeyCollectionName),
+		outbox:          outbox.NewStore(db),
+	}
 }
 
-func (s *MongoOrderStore) Create(ctx context.Context, order *domain.Order) error {
-	order.CreatedAt = time.Now()
-	order.UpdatedAt = time.Now()
+// EnsureIndexes creates the indexes the store relies on. It is safe to call
+// on every startup.
+func (s *MongoOrderStore) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateOne(ctx, mongo.IndexModel{
+		// Порядок ListByUserID
+		Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}, {Key: "_id", Value: -1}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create order indexes: %w", err)
+	}
 
-	result, err := s.collection.InsertOne(ctx, order)
+	_, err = s.idempotencyKeys.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{
+			// Ключи уникальны только в пределах пользователя
+			Keys:    bson.D{{Key: "user_id", Value: 1}, {Key: "key", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+		{
+			Keys:    bson.D{{Key: "created_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(idempotencyKeyTTL.Seconds())),
+		},
+	})
 	if err != nil {
-		return fmt.Errorf("failed to insert order: %w", err)
+		return fmt.Errorf("failed to create idempotency key indexes: %w", err)
 	}
+	return nil
+}
+
+func (s *MongoOrderStore) Create(ctx context.Context, order *domain.Order) (err error) {
+	defer orderStoreMetrics.Observe("Create", time.Now(), &err)
+
+	order.CreatedAt = time.Now()
+	order.UpdatedAt = time.Now()
 
-	if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
-		order.ID = oid
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		order.ID = primitive.NilObjectID
+		result, err := s.collection.InsertOne(ctx, order)
+		if err != nil {
+			return fmt.Errorf("failed to insert order: %w", err)
+		}
+		if oid, ok := result.InsertedID.(primitive.ObjectID); ok {
+			order.ID = oid
+		}
+		return s.addEvent(ctx, domain.EventOrderCreated, order.ID.Hex(), order)
+	})
+	if err != nil {
+		return err
 	}
-	log.Printf("Inserted order with ID: %v for user %s", result.InsertedID, order.UserID)
+	slog.DebugContext(ctx, "Inserted order", "order_id", order.ID.Hex(), "user_id", order.UserID)
 	return nil
 }
 
-func (s *MongoOrderStore) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+func (s *MongoOrderStore) GetByID(ctx context.Context, id string) (_ *domain.Order, err error) {
+	defer orderStoreMetrics.Observe("GetByID", time.Now(), &err)
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid id format: %w", err)
+		return nil, domain.InvalidIDError(domain.ResourceOrder, id)
 	}
 
 	var order domain.Order
 	err = s.collection.FindOne(ctx, bson.M{"_id": objID}).Decode(&order)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, fmt.Errorf("order not found")
+			return nil, domain.NotFoundError(domain.ResourceOrder, id)
 		}
 		return nil, fmt.Errorf("failed to find order: %w", err)
 	}
 	return &order, nil
 }
 
-func (s *MongoOrderStore) UpdateStatus(ctx context.Context, id string, status domain.OrderStatus) error {
+// UpdateStatus applies the transition only if the order is still in
+// transition.From, and appends it to the order history in the same update.
+// Statuses that release stock also mark the release as pending, so it is
+// recorded atomically with the status change.
+func (s *MongoOrderStore) UpdateStatus(ctx context.Context, id string, transition domain.StatusTransition) (err error) {
+	defer orderStoreMetrics.Observe("UpdateStatus", time.Now(), &err)
+
 	objID, err := primitive.ObjectIDFromHex(id)
 	if err != nil {
-		return fmt.Errorf("invalid id format: %w", err)
+		return domain.InvalidIDError(domain.ResourceOrder, id)
+	}
+
+	if transition.ChangedAt.IsZero() {
+		transition.ChangedAt = time.Now()
 	}
 
-	filter := bson.M{"_id": objID}
+	set := bson.M{
+		"status":     transition.To,
+		"updated_at": transition.ChangedAt,
+	}
+	if transition.To.ReleasesStock() {
+		set["stock_release"] = domain.StockReleasePending
+	}
+
+	filter := bson.M{"_id": objID, "status": transition.From}
+	update := bson.M{
+		"$set":  set,
+		"$push": bson.M{"history": transition},
+	}
+
+	err = s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		var updated struct {
+			UserID string `bson:"user_id"`
+		}
+		err := s.collection.FindOneAndUpdate(ctx, filter, update, options.FindOneAndUpdate().
+			SetProjection(bson.M{"user_id": 1})).Decode(&updated)
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			count, err := s.collection.CountDocuments(ctx, bson.M{"_id": objID})
+			if err != nil {
+				return fmt.Errorf("failed to check order: %w", err)
+			}
+			if count == 0 {
+				return domain.NotFoundError(domain.ResourceOrder, id)
+			}
+			return domain.NewError(domain.ErrVersionMismatch, domain.ResourceOrder, id, "order %s status changed concurrently: expected %s", id, transition.From)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		return s.addEvent(ctx, domain.EventOrderStatusChanged, id, domain.OrderStatusChangedPayload{
+			OrderID:   id,
+			UserID:    updated.UserID,
+			From:      transition.From,
+			To:        transition.To,
+			Actor:     transition.Actor,
+			Reason:    transition.Reason,
+			ChangedAt: transition.ChangedAt,
+		})
+	})
+	if err != nil {
+		return err
+	}
+	slog.DebugContext(ctx, "Updated order status", "order_id", id, "from", transition.From, "to", transition.To, "actor", transition.Actor)
+	return nil
+}
+
+// MarkStockReleased records that the stock of the order went back to the
+// inventory. It only matches orders whose release is still pending.
+func (s *MongoOrderStore) MarkStockReleased(ctx context.Context, id string) (err error) {
+	defer orderStoreMetrics.Observe("MarkStockReleased", time.Now(), &err)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.InvalidIDError(domain.ResourceOrder, id)
+	}
+
+	filter := bson.M{"_id": objID, "stock_release": domain.StockReleasePending}
 	update := bson.M{
 		"$set": bson.M{
-			"status":     status,
-			"updated_at": time.Now(),
+			"stock_release": domain.StockReleaseReleased,
+			"updated_at":    time.Now(),
 		},
 	}
 
 	result, err := s.collection.UpdateOne(ctx, filter, update)
 	if err != nil {
-		return fmt.Errorf("failed to update order status: %w", err)
+		return fmt.Errorf("failed to mark stock released: %w", err)
 	}
-
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("order not found to update status")
+		slog.DebugContext(ctx, "Order has no pending stock release", "order_id", id)
 	}
-	log.Printf("Updated order status ID: %s to %s, Matched: %d, Modified: %d", id, status, result.MatchedCount, result.ModifiedCount)
 	return nil
 }
 
-func (s *MongoOrderStore) ListByUserID(ctx context.Context, userID string, limit, offset int64) ([]*domain.Order, int64, error) {
-	filter := bson.M{"user_id": userID}
+// ListPendingStockReleases returns orders whose stock release is pending and
+// that were last updated before olderThan.
+func (s *MongoOrderStore) ListPendingStockReleases(ctx context.Context, olderThan time.Time, limit int64) (_ []*domain.Order, err error) {
+	defer orderStoreMetrics.Observe("ListPendingStockReleases", time.Now(), &err)
 
-	findOptions := options.Find()
-	if limit > 0 {
-		findOptions.SetLimit(limit)
+	filter := bson.M{
+		"stock_release": domain.StockReleasePending,
+		"updated_at":    bson.M{"$lt": olderThan},
 	}
-	if offset > 0 {
-		findOptions.SetSkip(offset)
+	findOptions := options.Find().SetLimit(limit).SetSort(bson.D{{Key: "updated_at", Value: 1}})
+
+	cursor, err := s.collection.Find(ctx, filter, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending stock releases: %w", err)
 	}
-	findOptions.SetSort(bson.D{{"created_at", -1}})
+	defer cursor.Close(ctx)
 
-	totalCount, err := s.collection.CountDocuments(ctx, filter)
+	var orders []*domain.Order
+	if err = cursor.All(ctx, &orders); err != nil {
+		return nil, fmt.Errorf("failed to decode orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (s *MongoOrderStore) addEvent(ctx context.Context, eventType, orderID string, payload any) error {
+	event, err := events.New(domain.EventSource, eventType, orderID, payload)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to count orders for user %s: %w", userID, err)
+		return err
 	}
+	return s.outbox.Add(ctx, event)
+}
 
-	cursor, err := s.collection.Find(ctx, filter, findOptions)
+// ListByUserID returns a page of the orders of a user, newest first. If
+// there are more orders, next is the cursor to continue after the page.
+// total is the number of all orders of the user, or 0 if page.CountTotal is
+// not set.
+func (s *MongoOrderStore) ListByUserID(ctx context.Context, userID string, page pagination.Page) (_ []*domain.Order, next *pagination.Cursor, total int64, err error) {
+	defer orderStoreMetrics.Observe("ListByUserID", time.Now(), &err)
+
+	filter := bson.M{"user_id": userID}
+
+	// Лишний заказ показывает, есть ли следующая страница
+	findOptions := options.Find().SetSort(pagination.Sort("created_at", true))
+	if page.Limit > 0 {
+		findOptions.SetLimit(page.Limit + 1)
+	}
+	if page.After == nil && page.Offset > 0 {
+		findOptions.SetSkip(page.Offset)
+	}
+
+	if page.CountTotal {
+		total, err = s.collection.CountDocuments(ctx, filter)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("failed to count orders for user %s: %w", userID, err)
+		}
+	}
+
+	cursor, err := s.collection.Find(ctx, pagination.After(filter, "created_at", true, page.After), findOptions)
 	if err != nil {
-		return nil, 0, fmt.Errorf("failed to list orders for user %s: %w", userID, err)
+		return nil, nil, 0, fmt.Errorf("failed to list orders for user %s: %w", userID, err)
 	}
 	defer cursor.Close(ctx)
 
-	var orders []*domain.Order
+	orders := []*domain.Order{}
 	if err = cursor.All(ctx, &orders); err != nil {
-		return nil, 0, fmt.Errorf("failed to decode orders for user %s: %w", userID, err)
+		return nil, nil, 0, fmt.Errorf("failed to decode orders for user %s: %w", userID, err)
+	}
+
+	if page.Limit > 0 && int64(len(orders)) > page.Limit {
+		orders = orders[:page.Limit]
+		last := orders[len(orders)-1]
+		next = &pagination.Cursor{Value: last.CreatedAt, ID: last.ID}
+	}
+	return orders, next, total, nil
+}
+
+// ClaimIdempotencyKey records the key of userID for a new request. A claim
+// of the same request whose lease has run out is taken over. If the key is
+// otherwise taken, the existing record is returned and claimed is false.
+func (s *MongoOrderStore) ClaimIdempotencyKey(ctx context.Context, userID, key, requestHash string) (record *domain.IdempotencyRecord, claimed bool, err error) {
+	defer orderStoreMetrics.Observe("ClaimIdempotencyKey", time.Now(), &err)
+
+	now := time.Now()
+	record = &domain.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+		ClaimID:     primitive.NewObjectID().Hex(),
+		LockedUntil: now.Add(idempotencyLease),
+		CreatedAt:   now,
 	}
 
-	if orders == nil {
-		orders = []*domain.Order{}
+	_, err = s.idempotencyKeys.InsertOne(ctx, record)
+	if err == nil {
+		return record, true, nil
 	}
+	if !mongo.IsDuplicateKeyError(err) {
+		return nil, false, fmt.Errorf("failed to insert idempotency key: %w", err)
+	}
+
+	// Запрос, захвативший ключ, не завершился за время аренды (например,
+	// упал процесс), поэтому повтор того же запроса забирает ключ себе
+	stale := bson.M{
+		"user_id":      userID,
+		"key":          key,
+		"request_hash": requestHash,
+		"order_id":     bson.M{"$exists": false},
+		"locked_until": bson.M{"$lte": now},
+	}
+	result, err := s.idempotencyKeys.UpdateOne(ctx, stale, bson.M{"$set": bson.M{
+		"claim_id":     record.ClaimID,
+		"locked_until": record.LockedUntil,
+	}})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to take over idempotency key: %w", err)
+	}
+	if result.ModifiedCount > 0 {
+		return record, true, nil
+	}
+
+	var existing domain.IdempotencyRecord
+	err = s.idempotencyKeys.FindOne(ctx, bson.M{"user_id": userID, "key": key}).Decode(&existing)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to find idempotency key: %w", err)
+	}
+	return &existing, false, nil
+}
+
+// CreateIdempotent creates order and links the idempotency key claimed by
+// record to it in one transaction. If the claim was taken over by a retry in
+// the meantime, nothing is written and a ClaimLostError is returned, so at
+// most one of the requests creates an order.
+func (s *MongoOrderStore) CreateIdempotent(ctx context.Context, order *domain.Order, record *domain.IdempotencyRecord) (err error) {
+	defer orderStoreMetrics.Observe("CreateIdempotent", time.Now(), &err)
+
+	return s.outbox.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := s.Create(ctx, order); err != nil {
+			return err
+		}
+		return s.CompleteIdempotencyKey(ctx, record, order.ID.Hex())
+	})
+}
+
+// CompleteIdempotencyKey links a claimed key to the order it produced. It
+// returns a ClaimLostError if record no longer holds the claim.
+func (s *MongoOrderStore) CompleteIdempotencyKey(ctx context.Context, record *domain.IdempotencyRecord, orderID string) (err error) {
+	defer orderStoreMetrics.Observe("CompleteIdempotencyKey", time.Now(), &err)
+
+	result, err := s.idempotencyKeys.UpdateOne(ctx, claimFilter(record), bson.M{
+		"$set":   bson.M{"order_id": orderID},
+		"$unset": bson.M{"locked_until": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.ClaimLostError(record.Key)
+	}
+	return nil
+}
+
+// ReleaseIdempotencyKey removes the key claimed by record after its request
+// failed, so the client can retry with the same key. This includes a key
+// already linked to an order that failed later on. A claim that was taken
+// over in the meantime is left alone.
+func (s *MongoOrderStore) ReleaseIdempotencyKey(ctx context.Context, record *domain.IdempotencyRecord) (err error) {
+	defer orderStoreMetrics.Observe("ReleaseIdempotencyKey", time.Now(), &err)
+
+	_, err = s.idempotencyKeys.DeleteOne(ctx, bson.M{
+		"user_id":  record.UserID,
+		"key":      record.Key,
+		"claim_id": record.ClaimID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// claimFilter matches the idempotency key of record while it is still held
+// by the claim of record.
+func claimFilter(record *domain.IdempotencyRecord) bson.M {
+	return bson.M{
+		"user_id":  record.UserID,
+		"key":      record.Key,
+		"claim_id": record.ClaimID,
+		"order_id": bson.M{"$exists": false},
+	}
+}
+
+// OrderChangeStream yields the full order document after each update.
+type OrderChangeStream struct {
+	stream *mongo.ChangeStream
+}
+
+// WatchByID opens a change stream on a single order. Open it before reading
+// the current state, so that no change between the read and the watch is
+// lost.
+func (s *MongoOrderStore) WatchByID(ctx context.Context, id string) (_ *OrderChangeStream, err error) {
+	defer orderStoreMetrics.Observe("WatchByID", time.Now(), &err)
+
+	objID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.InvalidIDError(domain.ResourceOrder, id)
+	}
+
+	pipeline := mongo.Pipeline{{{Key: "$match", Value: bson.M{
+		"documentKey._id": objID,
+		"operationType":   bson.M{"$in": bson.A{"update", "replace"}},
+	}}}}
+	stream, err := s.collection.Watch(ctx, pipeline, options.ChangeStream().SetFullDocument(options.UpdateLookup))
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch order: %w", err)
+	}
+	return &OrderChangeStream{stream: stream}, nil
+}
+
+// Next blocks until the order changes, ctx is done or the stream fails.
+func (w *OrderChangeStream) Next(ctx context.Context) (*domain.Order, error) {
+	for w.stream.Next(ctx) {
+		var change struct {
+			FullDocument *domain.Order `bson:"fullDocument"`
+		}
+		if err := w.stream.Decode(&change); err != nil {
+			return nil, fmt.Errorf("failed to decode order change: %w", err)
+		}
+		if change.FullDocument != nil {
+			return change.FullDocument, nil
+		}
+	}
+	if err := w.stream.Err(); err != nil {
+		return nil, fmt.Errorf("order change stream failed: %w", err)
+	}
+	return nil, ctx.Err()
+}
 
-	return orders, totalCount, nil
+func (w *OrderChangeStream) Close(ctx context.Context) error {
+	return w.stream.Close(ctx)
 }
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"ecommerce-microservices/order-service/internal/domain"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+var duplicateKeyResponse = mtest.CreateWriteErrorsResponse(mtest.WriteError{Code: 11000, Message: "duplicate key"})
+
+func updateResponse(matched, modified int32) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: matched}, bson.E{Key: "nModified", Value: modified})
+}
+
+// sentUpdate returns the filter and update of the next update command sent.
+func sentUpdate(mt *mtest.T) (q, u bson.Raw) {
+	mt.Helper()
+	started := mt.GetStartedEvent()
+	if started == nil || started.CommandName != "update" {
+		mt.Fatalf("sent %v, want update", started)
+	}
+	statement := started.Command.Lookup("updates").Array().Index(0).Value().Document()
+	return statement.Lookup("q").Document(), statement.Lookup("u").Document()
+}
+
+func TestClaimIdempotencyKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("new key", func(mt *mtest.T) {
+		store := NewMongoOrderStore(mt.DB)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		record, claimed, err := store.ClaimIdempotencyKey(context.Background(), "user-1", "key-1", "hash")
+		if err != nil || !claimed {
+			mt.Fatalf("ClaimIdempotencyKey() = %v, %v, want claimed", claimed, err)
+		}
+		if record.ClaimID == "" || !record.LockedUntil.After(time.Now()) {
+			mt.Errorf("record = %+v, want a claim with a lease", record)
+		}
+	})
+
+	mt.Run("stale claim is taken over", func(mt *mtest.T) {
+		store := NewMongoOrderStore(mt.DB)
+		mt.AddMockResponses(duplicateKeyResponse, updateResponse(1, 1))
+
+		record, claimed, err := store.ClaimIdempotencyKey(context.Background(), "user-1", "key-1", "hash")
+		if err != nil || !claimed {
+			mt.Fatalf("ClaimIdempotencyKey() = %v, %v, want claimed", claimed, err)
+		}
+
+		mt.GetStartedEvent() // insert
+		q, u := sentUpdate(mt)
+		if hash := q.Lookup("request_hash").StringValue(); hash != "hash" {
+			mt.Errorf("takeover filter request_hash = %q, want %q", hash, "hash")
+		}
+		if _, err := q.LookupErr("locked_until", "$lte"); err != nil {
+			mt.Error("takeover filter does not check that the lease ran out")
+		}
+		if claimID := u.Lookup("$set", "claim_id").StringValue(); claimID != record.ClaimID {
+			mt.Errorf("takeover claim_id = %q, want %q", claimID, record.ClaimID)
+		}
+	})
+
+	mt.Run("key is taken", func(mt *mtest.T) {
+		store := NewMongoOrderStore(mt.DB)
+		existing := bson.D{
+			{Key: "user_id", Value: "user-1"},
+			{Key: "key", Value: "key-1"},
+			{Key: "request_hash", Value: "hash"},
+			{Key: "order_id", Value: "order-1"},
+		}
+		mt.AddMockResponses(
+			duplicateKeyResponse,
+			updateResponse(0, 0),
+			mtest.CreateCursorResponse(0, "test.idempotency_keys", mtest.FirstBatch, existing),
+		)
+
+		record, claimed, err := store.ClaimIdempotencyKey(context.Background(), "user-1", "key-1", "hash")
+		if err != nil || claimed {
+			mt.Fatalf("ClaimIdempotencyKey() = %v, %v, want not claimed", claimed, err)
+		}
+		if record.OrderID != "order-1" {
+			mt.Errorf("record.OrderID = %q, want order-1", record.OrderID)
+		}
+	})
+}
+
+func TestCompleteIdempotencyKey(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	record := &domain.IdempotencyRecord{UserID: "user-1", Key: "key-1", ClaimID: "claim-1"}
+
+	mt.Run("claim held", func(mt *mtest.T) {
+		store := NewMongoOrderStore(mt.DB)
+		mt.AddMockResponses(updateResponse(1, 1))
+
+		if err := store.CompleteIdempotencyKey(context.Background(), record, "order-1"); err != nil {
+			mt.Fatalf("CompleteIdempotencyKey() error = %v", err)
+		}
+		q, _ := sentUpdate(mt)
+		if claimID := q.Lookup("claim_id").StringValue(); claimID != record.ClaimID {
+			mt.Errorf("filter claim_id = %q, want %q", claimID, record.ClaimID)
+		}
+	})
+
+	mt.Run("claim taken over", func(mt *mtest.T) {
+		store := NewMongoOrderStore(mt.DB)
+		mt.AddMockResponses(updateResponse(0, 0))
+
+		err := store.CompleteIdempotencyKey(context.Background(), record, "order-1")
+		if !errors.Is(err, domain.ErrClaimLost) {
+			mt.Errorf("CompleteIdempotencyKey() error = %v, want %v", err, domain.ErrClaimLost)
+		}
+	})
+}
+
+func TestCreateIdempotent(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		name     string
+		matched  int32
+		wantErr  error
+		wantLast string
+	}{
+		{"claim held", 1, nil, "commitTransaction"},
+		// Повтор забрал ключ: заказ не сохраняется
+		{"claim lost", 0, domain.ErrClaimLost, "abortTransaction"},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			store := NewMongoOrderStore(mt.DB)
+			mt.AddMockResponses(
+				mtest.CreateSuccessResponse(), // заказ
+				mtest.CreateSuccessResponse(), // событие в outbox
+				updateResponse(tt.matched, tt.matched),
+				mtest.CreateSuccessResponse(), // завершение транзакции
+			)
+			record := &domain.IdempotencyRecord{UserID: "user-1", Key: "key-1", ClaimID: "claim-1"}
+
+			err := store.CreateIdempotent(context.Background(), &domain.Order{UserID: "user-1"}, record)
+			if !errors.Is(err, tt.wantErr) {
+				mt.Fatalf("CreateIdempotent() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var names []string
+			for started := mt.GetStartedEvent(); started != nil; started = mt.GetStartedEvent() {
+				if _, err := started.Command.LookupErr("txnNumber"); err != nil {
+					mt.Errorf("%s was sent outside the transaction", started.CommandName)
+				}
+				names = append(names, started.CommandName)
+			}
+			want := []string{"insert", "insert", "update", tt.wantLast}
+			if !slices.Equal(names, want) {
+				mt.Errorf("commands = %v, want %v", names, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,58 @@
+package saga
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Step is one local transaction of a saga together with the action that
+// undoes it. Compensate may be nil for steps that need no undo.
+type Step struct {
+	Name       string
+	Action     func(ctx context.Context) error
+	Compensate func(ctx context.Context) error
+}
+
+// Saga runs its steps in order. When a step fails, the compensations of the
+// steps that already completed run in reverse order.
+type Saga struct {
+	name  string
+	steps []Step
+}
+
+func New(name string) *Saga {
+	return &Saga{name: name}
+}
+
+func (s *Saga) AddStep(step Step) *Saga {
+	s.steps = append(s.steps, step)
+	return s
+}
+
+// Execute returns the error of the failed step. Compensations run even if ctx
+// has already been cancelled; their errors are logged, not returned.
+func (s *Saga) Execute(ctx context.Context) error {
+	for i, step := range s.steps {
+		slog.DebugContext(ctx, "Executing saga step", "saga", s.name, "step", step.Name)
+		if err := step.Action(ctx); err != nil {
+			slog.WarnContext(ctx, "Saga step failed", "saga", s.name, "step", step.Name, "error", err)
+			s.compensate(context.WithoutCancel(ctx), i)
+			return err
+		}
+	}
+	slog.DebugContext(ctx, "Saga completed", "saga", s.name)
+	return nil
+}
+
+func (s *Saga) compensate(ctx context.Context, failed int) {
+	for i := failed - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if step.Compensate == nil {
+			continue
+		}
+		slog.InfoContext(ctx, "Compensating saga step", "saga", s.name, "step", step.Name)
+		if err := step.Compensate(ctx); err != nil {
+			slog.ErrorContext(ctx, "Saga compensation failed", "saga", s.name, "step", step.Name, "error", err)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package saga
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestSagaExecute(t *testing.T) {
+	errStep := errors.New("step failed")
+
+	tests := []struct {
+		name    string
+		failAt  string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "all steps succeed",
+			want: []string{"do a", "do b", "do c"},
+		},
+		{
+			// Компенсации идут в обратном порядке, упавший шаг не компенсируется
+			name:    "last step fails",
+			failAt:  "c",
+			want:    []string{"do a", "do b", "do c", "undo b", "undo a"},
+			wantErr: errStep,
+		},
+		{
+			name:    "first step fails",
+			failAt:  "a",
+			want:    []string{"do a"},
+			wantErr: errStep,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+			step := func(name string) Step {
+				return Step{
+					Name: name,
+					Action: func(ctx context.Context) error {
+						calls = append(calls, "do "+name)
+						if name == tt.failAt {
+							return errStep
+						}
+						return nil
+					},
+					Compensate: func(ctx context.Context) error {
+						calls = append(calls, "undo "+name)
+						return nil
+					},
+				}
+			}
+
+			err := New("test").AddStep(step("a")).AddStep(step("b")).AddStep(step("c")).Execute(context.Background())
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Execute() error = %v, want %v", err, tt.wantErr)
+			}
+			if !slices.Equal(calls, tt.want) {
+				t.Errorf("calls = %v, want %v", calls, tt.want)
+			}
+		})
+	}
+}
+
+func TestSagaCompensation(t *testing.T) {
+	var calls []string
+	errStep := errors.New("step failed")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err := New("test").
+		AddStep(Step{
+			Name:   "a",
+			Action: func(ctx context.Context) error { return nil },
+			Compensate: func(ctx context.Context) error {
+				// Компенсация идет и после отмены запроса
+				if ctx.Err() != nil {
+					t.Errorf("compensation context is cancelled: %v", ctx.Err())
+				}
+				calls = append(calls, "undo a")
+				return nil
+			},
+		}).
+		AddStep(Step{
+			// Шаг без компенсации пропускается
+			Name:   "b",
+			Action: func(ctx context.Context) error { return nil },
+		}).
+		AddStep(Step{
+			Name: "c",
+			Action: func(ctx context.Context) error {
+				return nil
+			},
+			Compensate: func(ctx context.Context) error {
+				calls = append(calls, "undo c")
+				// Ошибка компенсации не останавливает остальные
+				return errors.New("compensation failed")
+			},
+		}).
+		AddStep(Step{
+			Name: "d",
+			Action: func(ctx context.Context) error {
+				cancel()
+				return errStep
+			},
+		}).
+		Execute(ctx)
+
+	if !errors.Is(err, errStep) {
+		t.Errorf("Execute() error = %v, want %v", err, errStep)
+	}
+	if want := []string{"undo c", "undo a"}; !slices.Equal(calls, want) {
+		t.Errorf("compensations = %v, want %v", calls, want)
+	}
+}
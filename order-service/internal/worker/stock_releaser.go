@@ -0,0 +1,79 @@
+package worker
+
+import (
+	"context"
+	invClient "ecommerce-microservices/order-service/internal/client"
+	"ecommerce-microservices/order-service/internal/domain"
+	repo "ecommerce-microservices/order-service/internal/repository"
+	"ecommerce-microservices/pkg/grpcerr"
+	"log/slog"
+	"time"
+)
+
+const stockReleaseBatchSize = 50
+
+// reasonReservationReleased is the error reason of InventoryService for a
+// reservation that was already released.
+const reasonReservationReleased = "RESERVATION_RELEASED"
+
+// StockReleaser returns the reserved stock of cancelled and failed orders to
+// the Inventory Service. The order keeps its release pending until the
+// inventory confirms it, and the inventory releases a reservation only once,
+// so retries never return the same stock twice.
+type StockReleaser struct {
+	orderStore      *repo.MongoOrderStore
+	inventoryClient invClient.InventoryClient
+	interval        time.Duration
+}
+
+func NewStockReleaser(os *repo.MongoOrderStore, ic invClient.InventoryClient, interval time.Duration) *StockReleaser {
+	return &StockReleaser{
+		orderStore:      os,
+		inventoryClient: ic,
+		interval:        interval,
+	}
+}
+
+// Release returns the stock of the order and marks the release as done.
+func (r *StockReleaser) Release(ctx context.Context, order *domain.Order) error {
+	orderID := order.ID.Hex()
+	if order.ReservationID != "" {
+		err := r.inventoryClient.ReleaseReservation(ctx, order.ReservationID)
+		// Уже возвращенный резерв - успех; другие отказы повторяются
+		if err != nil && grpcerr.Reason(err) != reasonReservationReleased {
+			return err
+		}
+		slog.InfoContext(ctx, "Stock of order returned to inventory", "order_id", orderID, "reservation_id", order.ReservationID)
+	}
+	return r.orderStore.MarkStockReleased(ctx, orderID)
+}
+
+// Run retries pending releases every interval until ctx is cancelled.
+func (r *StockReleaser) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Stock releaser stopped")
+			return
+		case <-ticker.C:
+			r.releasePending(ctx)
+		}
+	}
+}
+
+func (r *StockReleaser) releasePending(ctx context.Context) {
+	// Свежие заказы пропускаем: их сток возвращает сам UpdateOrderStatus
+	orders, err := r.orderStore.ListPendingStockReleases(ctx, time.Now().Add(-r.interval), stockReleaseBatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "Stock releaser failed to list pending releases", "error", err)
+		return
+	}
+	for _, order := range orders {
+		if err := r.Release(ctx, order); err != nil {
+			slog.WarnContext(ctx, "Stock releaser failed to release stock", "order_id", order.ID.Hex(), "error", err)
+		}
+	}
+}
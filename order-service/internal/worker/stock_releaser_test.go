@@ -0,0 +1,140 @@
+package worker
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	inventorypb "ecommerce-microservices/inventory-service/pb"
+	"ecommerce-microservices/order-service/internal/domain"
+	repo "ecommerce-microservices/order-service/internal/repository"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// fakeInventory answers ReleaseReservation with the error in errs, if any,
+// and records the reservations it was asked to release.
+type fakeInventory struct {
+	errs     map[string]error
+	released []string
+}
+
+func (f *fakeInventory) GetProduct(ctx context.Context, productID string) (*inventorypb.Product, error) {
+	return nil, status.Error(codes.Unimplemented, "not used")
+}
+
+func (f *fakeInventory) GetProducts(ctx context.Context, productIDs []string) (map[string]*inventorypb.Product, []string, error) {
+	return nil, nil, status.Error(codes.Unimplemented, "not used")
+}
+
+func (f *fakeInventory) ReserveStock(ctx context.Context, items []*inventorypb.StockItem) (*inventorypb.Reservation, error) {
+	return nil, status.Error(codes.Unimplemented, "not used")
+}
+
+func (f *fakeInventory) CommitReservation(ctx context.Context, reservationID string) error {
+	return status.Error(codes.Unimplemented, "not used")
+}
+
+func (f *fakeInventory) ReleaseReservation(ctx context.Context, reservationID string) error {
+	f.released = append(f.released, reservationID)
+	return f.errs[reservationID]
+}
+
+func reasonError(code codes.Code, reason string) error {
+	st, _ := status.New(code, reason).WithDetails(&errdetails.ErrorInfo{Reason: reason, Domain: "inventory-service"})
+	return st.Err()
+}
+
+// sentCommands drains the commands the mock server received.
+func sentCommands(mt *mtest.T) []string {
+	var names []string
+	for started := mt.GetStartedEvent(); started != nil; started = mt.GetStartedEvent() {
+		names = append(names, started.CommandName)
+	}
+	return names
+}
+
+// toDoc converts v to the document a mock server returns for it.
+func toDoc(t *mtest.T, v any) bson.D {
+	t.Helper()
+	data, err := bson.Marshal(v)
+	if err != nil {
+		t.Fatalf("bson.Marshal() error = %v", err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("bson.Unmarshal() error = %v", err)
+	}
+	return doc
+}
+
+func TestStockReleaserRelease(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		name          string
+		reservationID string
+		releaseErr    error
+		wantErr       bool
+		wantCommands  []string
+	}{
+		{"released", "r1", nil, false, []string{"update"}},
+		// Резерв уже возвращен раньше, повтор завершает выпуск
+		{"already released", "r1", reasonError(codes.FailedPrecondition, reasonReservationReleased), false, []string{"update"}},
+		// Заказ остается в ожидании, следующий проход повторит выпуск
+		{"inventory unavailable", "r1", status.Error(codes.Unavailable, "connection refused"), true, nil},
+		{"other refusal", "r1", reasonError(codes.FailedPrecondition, "RESERVATION_COMMITTED"), true, nil},
+		{"no reservation", "", nil, false, []string{"update"}},
+	}
+
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}))
+			inventory := &fakeInventory{errs: map[string]error{"r1": tt.releaseErr}}
+			releaser := NewStockReleaser(repo.NewMongoOrderStore(mt.DB), inventory, time.Minute)
+			order := &domain.Order{ID: primitive.NewObjectID(), ReservationID: tt.reservationID}
+
+			err := releaser.Release(context.Background(), order)
+			if (err != nil) != tt.wantErr {
+				mt.Fatalf("Release() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := sentCommands(mt); !slices.Equal(got, tt.wantCommands) {
+				mt.Errorf("commands = %v, want %v", got, tt.wantCommands)
+			}
+			if tt.reservationID == "" && len(inventory.released) > 0 {
+				mt.Errorf("released %v, want no inventory call", inventory.released)
+			}
+		})
+	}
+}
+
+func TestStockReleaserReleasePending(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("failed release does not stop the batch", func(mt *mtest.T) {
+		first := domain.Order{ID: primitive.NewObjectID(), ReservationID: "r1", StockRelease: domain.StockReleasePending}
+		second := domain.Order{ID: primitive.NewObjectID(), ReservationID: "r2", StockRelease: domain.StockReleasePending}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, "test.orders", mtest.FirstBatch, toDoc(mt, first), toDoc(mt, second)),
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}, bson.E{Key: "nModified", Value: 1}),
+		)
+		inventory := &fakeInventory{errs: map[string]error{"r1": status.Error(codes.Unavailable, "connection refused")}}
+		releaser := NewStockReleaser(repo.NewMongoOrderStore(mt.DB), inventory, time.Minute)
+
+		releaser.releasePending(context.Background())
+
+		if want := []string{"r1", "r2"}; !slices.Equal(inventory.released, want) {
+			mt.Errorf("released = %v, want %v", inventory.released, want)
+		}
+		// Только второй заказ помечается как выпущенный
+		if got, want := sentCommands(mt), []string{"find", "update"}; !slices.Equal(got, want) {
+			mt.Errorf("commands = %v, want %v", got, want)
+		}
+	})
+}
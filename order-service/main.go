@@ -5,27 +5,44 @@ import (
 	"ecommerce-microservices/order-service/pb"
 	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net"
+	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
 	invClient "ecommerce-microservices/order-service/internal/client"
 	grpcServer "ecommerce-microservices/order-service/internal/delivery/grpc"
 	repo "ecommerce-microservices/order-service/internal/repository"
+	"ecommerce-microservices/order-service/internal/worker"
+	"ecommerce-microservices/pkg/events"
+	"ecommerce-microservices/pkg/identity"
+	"ecommerce-microservices/pkg/logging"
+	"ecommerce-microservices/pkg/metrics"
+	"ecommerce-microservices/pkg/outbox"
+	"ecommerce-microservices/pkg/rbac"
+	"ecommerce-microservices/pkg/tracing"
 
 	"go.mongodb.org/mongo-driver/mongo"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 )
 
+// outboxPollInterval is how often the outbox relay looks for new events.
+const outboxPollInterval = time.Second
+
+// stockReleaseRetryInterval is how often stock releases that failed during
+// UpdateOrderStatus are retried.
+const stockReleaseRetryInterval = 30 * time.Second
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
 	}
-	log.Printf("Using fallback for env var %s: %s", key, fallback)
+	slog.Info("Using fallback for env var", "key", key, "value", fallback)
 	return fallback
 }
 
@@ -36,6 +53,14 @@ var (
 )
 
 func main() {
+	if _, err := logging.Setup(logging.Config{
+		Service: "order-service",
+		Format:  os.Getenv("LOG_FORMAT"),
+		Level:   os.Getenv("LOG_LEVEL"),
+	}); err != nil {
+		logging.Fatal("Failed to configure logging", "error", err)
+	}
+
 	mongoCfg := repo.MongoConfig{
 		Host:     getEnv("MONGO_HOST", "localhost"),
 		Port:     getEnv("MONGO_PORT", "27017"),
@@ -45,63 +70,130 @@ func main() {
 		Timeout:  15 * time.Second,
 	}
 	grpcPort := getEnv("GRPC_PORT", "50052")
+	metricsPort := getEnv("METRICS_PORT", "9092")
 	inventoryServiceAddr := getEnv("INVENTORY_SERVICE_ADDR", "localhost:50051")
 
-	var err error
+	// Общий с gateway и inventory секрет для подписи identity-токенов
+	identitySigner, err := identity.NewSigner(os.Getenv("INTERNAL_AUTH_SECRET"))
+	if err != nil {
+		logging.Fatal("Invalid INTERNAL_AUTH_SECRET", "error", err)
+	}
+	eventBrokerKind := getEnv("EVENT_BROKER", "memory")
+	natsURL := getEnv("NATS_URL", "nats://localhost:4222")
+
+	sampleRatio, err := strconv.ParseFloat(getEnv("TRACES_SAMPLE_RATIO", "1"), 64)
+	if err != nil || sampleRatio < 0 || sampleRatio > 1 {
+		logging.Fatal("Invalid TRACES_SAMPLE_RATIO, expected a number from 0 to 1")
+	}
+	shutdownTracing, err := tracing.Init(context.Background(), tracing.Config{
+		ServiceName:  "order-service",
+		Exporter:     getEnv("TRACES_EXPORTER", tracing.ExporterNone),
+		OTLPEndpoint: getEnv("TRACES_OTLP_ENDPOINT", ""),
+		FilePath:     getEnv("TRACES_FILE", "traces.json"),
+		SampleRatio:  sampleRatio,
+	})
+	if err != nil {
+		logging.Fatal("Failed to initialize tracing", "error", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Error("Failed to flush traces", "error", err)
+		}
+	}()
 
 	mongoClient, err = repo.NewMongoConnection(mongoCfg)
 	if err != nil {
-		log.Fatalf("Failed to connect to MongoDB: %v", err)
+		logging.Fatal("Failed to connect to MongoDB", "error", err)
 	}
 	defer func() {
-		log.Println("Disconnecting MongoDB client...")
+		slog.Info("Disconnecting MongoDB client")
 		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 		defer cancel()
 		if err = mongoClient.Disconnect(ctx); err != nil {
-			log.Printf("Error disconnecting MongoDB client: %v", err)
+			slog.Error("Error disconnecting MongoDB client", "error", err)
 		}
 	}()
 	mongoDB := repo.GetMongoDatabase(mongoClient, mongoCfg.DBName)
 
 	connCtx, connCancel := context.WithTimeout(context.Background(), 15*time.Second)
-	inventoryServiceClient, inventoryServiceConn, err = invClient.NewInventoryGRPCClient(connCtx, inventoryServiceAddr)
+	inventoryServiceClient, inventoryServiceConn, err = invClient.NewInventoryGRPCClient(connCtx, inventoryServiceAddr, identitySigner)
 	connCancel()
 	if err != nil {
-		log.Fatalf("Failed to connect to Inventory Service at %s: %v", inventoryServiceAddr, err)
+		logging.Fatal("Failed to connect to Inventory Service", "addr", inventoryServiceAddr, "error", err)
 	}
 	defer func() {
-		log.Println("Closing connection to Inventory Service...")
+		slog.Info("Closing connection to Inventory Service")
 		if inventoryServiceConn != nil {
 			inventoryServiceConn.Close()
 		}
 	}()
 
 	orderStore := repo.NewMongoOrderStore(mongoDB)
-	orderServer := grpcServer.NewOrderServer(orderStore, inventoryServiceClient)
+	indexCtx, indexCancel := context.WithTimeout(context.Background(), 15*time.Second)
+	err = orderStore.EnsureIndexes(indexCtx)
+	indexCancel()
+	if err != nil {
+		logging.Fatal("Failed to create order store indexes", "error", err)
+	}
+	eventBroker, err := events.NewBroker(eventBrokerKind, natsURL, "orders")
+	if err != nil {
+		logging.Fatal("Failed to create event broker", "error", err)
+	}
+	defer eventBroker.Close()
+
+	outboxStore := outbox.NewStore(mongoDB)
+	indexCtx, indexCancel = context.WithTimeout(context.Background(), 15*time.Second)
+	err = outboxStore.EnsureIndexes(indexCtx)
+	indexCancel()
+	if err != nil {
+		logging.Fatal("Failed to create outbox indexes", "error", err)
+	}
+
+	stockReleaser := worker.NewStockReleaser(orderStore, inventoryServiceClient, stockReleaseRetryInterval)
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	defer stopWorkers()
+	go stockReleaser.Run(workerCtx)
+	go outbox.NewRelay(outboxStore, eventBroker, outboxPollInterval).Run(workerCtx)
+
+	orderServer := grpcServer.NewOrderServer(orderStore, inventoryServiceClient, stockReleaser)
 
 	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", grpcPort))
 	if err != nil {
-		log.Fatalf("Failed to listen on port %s: %v", grpcPort, err)
+		logging.Fatal("Failed to listen", "port", grpcPort, "error", err)
 	}
 
-	srv := grpc.NewServer()
+	srv := grpc.NewServer(
+		tracing.ServerOption(),
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(), metrics.UnaryServerInterceptor(), identitySigner.UnaryServerInterceptor(), rbac.UnaryServerInterceptor(grpcServer.MethodPolicy)),
+		grpc.ChainStreamInterceptor(logging.StreamServerInterceptor(), metrics.StreamServerInterceptor(), identitySigner.StreamServerInterceptor(), rbac.StreamServerInterceptor(grpcServer.MethodPolicy)),
+	)
 	pb.RegisterOrderServiceServer(srv, orderServer)
 	reflection.Register(srv)
 
 	go func() {
-		log.Printf("Starting Order gRPC Service on port %s", grpcPort)
+		slog.Info("Starting Order gRPC Service", "port", grpcPort)
 		if err := srv.Serve(lis); err != nil {
 			if !errors.Is(err, grpc.ErrServerStopped) {
-				log.Fatalf("Failed to serve gRPC: %v", err)
+				logging.Fatal("Failed to serve gRPC", "error", err)
 			}
 		}
-		log.Println("gRPC server stopped serving")
+		slog.Info("gRPC server stopped serving")
+	}()
+
+	metricsServer := metrics.NewServer(":" + metricsPort)
+	go func() {
+		slog.Info("Starting metrics server", "port", metricsPort)
+		if err := metricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.Fatal("Failed to serve metrics", "error", err)
+		}
 	}()
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	sig := <-quit
-	log.Printf("Received signal %v, shutting down gRPC server...", sig)
+	slog.Info("Received signal, shutting down gRPC server", "signal", sig.String())
 
 	stopped := make(chan struct{})
 	go func() {
@@ -112,12 +204,13 @@ func main() {
 	t := time.NewTimer(10 * time.Second)
 	select {
 	case <-t.C:
-		log.Println("Graceful shutdown timed out, forcing stop.")
+		slog.Warn("Graceful shutdown timed out, forcing stop")
 		srv.Stop()
 	case <-stopped:
 		t.Stop()
-		log.Println("gRPC server stopped gracefully.")
+		slog.Info("gRPC server stopped gracefully")
 	}
 
-	log.Println("Order Service exiting")
+	metricsServer.Close()
+	slog.Info("Order Service exiting")
 }
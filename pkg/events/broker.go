@@ -0,0 +1,29 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Broker delivers published events to downstream consumers.
+type Broker interface {
+	Publish(ctx context.Context, event Event) error
+	Close() error
+}
+
+// NewBroker builds the broker selected by kind: "memory" or "nats". url is
+// only used by brokers that connect to a server.
+func NewBroker(kind, url, subjectPrefix string) (Broker, error) {
+	switch strings.ToLower(kind) {
+	case "", "memory":
+		slog.Info("Using in-memory event broker")
+		return NewMemoryBroker(), nil
+	case "nats":
+		slog.Info("Connecting to NATS event broker", "url", url)
+		return NewNATSBroker(url, subjectPrefix)
+	default:
+		return nil, fmt.Errorf("unknown event broker %q", kind)
+	}
+}
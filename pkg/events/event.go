@@ -0,0 +1,43 @@
+// Package events defines the domain event envelope shared by the services
+// and the brokers the events are published to.
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Event is the envelope every domain event is published in. Payload holds the
+// JSON encoded, event specific body.
+type Event struct {
+	ID          string          `json:"id" bson:"id"`
+	Type        string          `json:"type" bson:"type"`
+	Source      string          `json:"source" bson:"source"`
+	AggregateID string          `json:"aggregate_id" bson:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at" bson:"occurred_at"`
+	Payload     json.RawMessage `json:"payload" bson:"payload"`
+}
+
+func New(source, eventType, aggregateID string, payload any) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to marshal %s payload: %w", eventType, err)
+	}
+
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return Event{}, fmt.Errorf("failed to generate event id: %w", err)
+	}
+
+	return Event{
+		ID:          hex.EncodeToString(id),
+		Type:        eventType,
+		Source:      source,
+		AggregateID: aggregateID,
+		OccurredAt:  time.Now().UTC(),
+		Payload:     data,
+	}, nil
+}
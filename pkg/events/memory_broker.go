@@ -0,0 +1,45 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Handler consumes events delivered by the MemoryBroker.
+type Handler func(ctx context.Context, event Event)
+
+// MemoryBroker delivers events synchronously to handlers registered in the
+// same process. It is meant for local runs and tests.
+type MemoryBroker struct {
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+}
+
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{handlers: make(map[string][]Handler)}
+}
+
+// Subscribe registers handler for events of eventType, or for all events
+// if eventType is "*".
+func (b *MemoryBroker) Subscribe(eventType string, handler Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[eventType] = append(b.handlers[eventType], handler)
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, event Event) error {
+	b.mu.RLock()
+	handlers := append(append([]Handler{}, b.handlers[event.Type]...), b.handlers["*"]...)
+	b.mu.RUnlock()
+
+	slog.DebugContext(ctx, "Event published", "event_type", event.Type, "event_id", event.ID, "aggregate_id", event.AggregateID, "handlers", len(handlers))
+	for _, handler := range handlers {
+		handler(ctx, event)
+	}
+	return nil
+}
+
+func (b *MemoryBroker) Close() error {
+	return nil
+}
@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+// setupTimeout bounds creating the stream when the broker starts.
+const setupTimeout = 10 * time.Second
+
+// NATSBroker publishes every event to the subject "<prefix>.<event type>"
+// through JetStream. The broker keeps a stream for "<prefix>.>", and an event
+// only counts as published once the stream has acknowledged it. The event ID
+// is sent as the Nats-Msg-Id header, so the stream drops events that the
+// relay publishes more than once within its duplicate window.
+type NATSBroker struct {
+	conn          *nats.Conn
+	js            jetstream.JetStream
+	stream        string
+	subjectPrefix string
+}
+
+func NewNATSBroker(url, subjectPrefix string) (*NATSBroker, error) {
+	if subjectPrefix == "" {
+		return nil, errors.New("nats broker requires a subject prefix")
+	}
+	conn, err := nats.Connect(url, nats.Name("ecommerce-outbox-relay"), nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create jetstream context: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), setupTimeout)
+	defer cancel()
+	b := &NATSBroker{conn: conn, js: js, stream: streamName(subjectPrefix), subjectPrefix: subjectPrefix}
+	_, err = js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:     b.stream,
+		Subjects: []string{subjectPrefix + ".>"},
+	})
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to create stream %s: %w", b.stream, err)
+	}
+	return b, nil
+}
+
+func (b *NATSBroker) Publish(ctx context.Context, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event %s: %w", event.ID, err)
+	}
+
+	// Publish ждет PubAck от нашего стрима, поэтому relay не отметит событие
+	// опубликованным, пока JetStream его не сохранил. Повтор с тем же ID
+	// тоже подтверждается (ack.Duplicate), и это не ошибка
+	_, err = b.js.Publish(ctx, b.subject(event), data,
+		jetstream.WithMsgID(event.ID),
+		jetstream.WithExpectStream(b.stream))
+	if err != nil {
+		return fmt.Errorf("failed to publish event %s: %w", event.ID, err)
+	}
+	return nil
+}
+
+func (b *NATSBroker) subject(event Event) string {
+	return b.subjectPrefix + "." + event.Type
+}
+
+// streamName derives the stream name from the subject prefix; stream names
+// cannot contain dots.
+func streamName(subjectPrefix string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "*", "_", ">", "_").Replace(subjectPrefix)) + "_EVENTS"
+}
+
+func (b *NATSBroker) Close() error {
+	return b.conn.Drain()
+}
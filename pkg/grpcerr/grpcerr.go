@@ -0,0 +1,185 @@
+// Package grpcerr turns domain errors into gRPC statuses that carry
+// google.rpc error details, so clients do not have to parse messages.
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// ResourceError is implemented by domain errors that refer to a single
+// resource. It is reported as google.rpc.ResourceInfo.
+type ResourceError interface {
+	error
+	ResourceType() string
+	ResourceName() string
+}
+
+// FieldError is implemented by domain errors caused by one request field.
+// It is reported as a google.rpc.BadRequest field violation.
+type FieldError interface {
+	error
+	Field() string
+}
+
+// FieldViolation describes one invalid request field.
+type FieldViolation struct {
+	Field       string
+	Description string
+}
+
+// Rule maps errors matching Err (by errors.Is) to Code. Reason is a short
+// UPPER_SNAKE_CASE identifier reported in google.rpc.ErrorInfo, so that
+// clients can tell apart errors that share a code.
+type Rule struct {
+	Err    error
+	Code   codes.Code
+	Reason string
+}
+
+// Translator converts the errors of one service into gRPC statuses.
+type Translator struct {
+	domain string
+	rules  []Rule
+}
+
+// NewTranslator returns a translator for the given error domain, usually
+// the service name. Rules are matched in order.
+func NewTranslator(domain string, rules ...Rule) *Translator {
+	return &Translator{domain: domain, rules: rules}
+}
+
+// Error converts err into a gRPC status error. Errors that already carry a
+// status are returned unchanged, context errors become Canceled or
+// DeadlineExceeded, and errors that match no rule become Internal with msg
+// in front of the original error.
+func (t *Translator) Error(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	for _, rule := range t.rules {
+		if errors.Is(err, rule.Err) {
+			return t.status(rule, err)
+		}
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return status.FromContextError(err).Err()
+	}
+	return status.Errorf(codes.Internal, "%s: %v", msg, err)
+}
+
+func (t *Translator) status(rule Rule, err error) error {
+	st := status.New(rule.Code, err.Error())
+	if rule.Reason != "" {
+		st = withDetail(st, &errdetails.ErrorInfo{Reason: rule.Reason, Domain: t.domain})
+	}
+
+	var resErr ResourceError
+	if errors.As(err, &resErr) && resErr.ResourceType() != "" {
+		st = withDetail(st, &errdetails.ResourceInfo{
+			ResourceType: resErr.ResourceType(),
+			ResourceName: resErr.ResourceName(),
+			Description:  err.Error(),
+		})
+	}
+
+	var fieldErr FieldError
+	if errors.As(err, &fieldErr) && fieldErr.Field() != "" {
+		st = withDetail(st, badRequest(FieldViolation{
+			Field:       fieldErr.Field(),
+			Description: err.Error(),
+		}))
+	}
+	return st.Err()
+}
+
+// Status returns a status with code and msg that carries reason and the
+// violations like the statuses made by rules. It is meant for errors that
+// no single domain error describes, such as one listing several fields.
+func (t *Translator) Status(code codes.Code, reason, msg string, violations ...FieldViolation) error {
+	st := withDetail(status.New(code, msg), &errdetails.ErrorInfo{Reason: reason, Domain: t.domain})
+	if len(violations) > 0 {
+		st = withDetail(st, badRequest(violations...))
+	}
+	return st.Err()
+}
+
+// InvalidArgument returns an InvalidArgument status with msg and a
+// google.rpc.BadRequest detail listing the violations.
+func InvalidArgument(msg string, violations ...FieldViolation) error {
+	st := status.New(codes.InvalidArgument, msg)
+	if len(violations) > 0 {
+		st = withDetail(st, badRequest(violations...))
+	}
+	return st.Err()
+}
+
+// InvalidArgumentf is InvalidArgument for a single field, with the message
+// used as the violation description.
+func InvalidArgumentf(field, format string, args ...any) error {
+	msg := fmt.Sprintf(format, args...)
+	return InvalidArgument(msg, FieldViolation{Field: field, Description: msg})
+}
+
+// FieldViolations returns the BadRequest field violations attached to err,
+// if it is a gRPC status error.
+func FieldViolations(err error) []FieldViolation {
+	st, ok := status.FromError(err)
+	if !ok {
+		return nil
+	}
+	var violations []FieldViolation
+	for _, detail := range st.Details() {
+		if br, ok := detail.(*errdetails.BadRequest); ok {
+			for _, v := range br.GetFieldViolations() {
+				violations = append(violations, FieldViolation{Field: v.GetField(), Description: v.GetDescription()})
+			}
+		}
+	}
+	return violations
+}
+
+// Reason returns the google.rpc.ErrorInfo reason attached to err, or an
+// empty string.
+func Reason(err error) string {
+	st, ok := status.FromError(err)
+	if !ok {
+		return ""
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			return info.GetReason()
+		}
+	}
+	return ""
+}
+
+func badRequest(violations ...FieldViolation) *errdetails.BadRequest {
+	br := &errdetails.BadRequest{}
+	for _, v := range violations {
+		br.FieldViolations = append(br.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	return br
+}
+
+// withDetail attaches detail to st. Details only fail to marshal on
+// programming errors, in which case the status is returned without them.
+func withDetail(st *status.Status, detail protoadapt.MessageV1) *status.Status {
+	withDetail, err := st.WithDetails(detail)
+	if err != nil {
+		return st
+	}
+	return withDetail
+}
@@ -0,0 +1,135 @@
+package grpcerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	errNotFound = errors.New("not found")
+	errConflict = errors.New("conflict")
+)
+
+// testError is a domain error about one resource and request field.
+type testError struct {
+	kind     error
+	resource string
+	id       string
+	field    string
+}
+
+func (e *testError) Error() string        { return fmt.Sprintf("%s %s: %v", e.resource, e.id, e.kind) }
+func (e *testError) Unwrap() error        { return e.kind }
+func (e *testError) ResourceType() string { return e.resource }
+func (e *testError) ResourceName() string { return e.id }
+func (e *testError) Field() string        { return e.field }
+
+var translator = NewTranslator("test-service",
+	Rule{Err: errNotFound, Code: codes.NotFound, Reason: "NOT_FOUND"},
+	Rule{Err: errConflict, Code: codes.AlreadyExists},
+)
+
+// detailsOf returns the details of err by type name.
+func detailsOf(t *testing.T, err error) map[string]any {
+	t.Helper()
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Fatalf("%v is not a status error", err)
+	}
+	details := make(map[string]any)
+	for _, d := range st.Details() {
+		details[fmt.Sprintf("%T", d)] = d
+	}
+	return details
+}
+
+func TestTranslatorError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		code codes.Code
+		msg  string
+	}{
+		{"matching rule", &testError{kind: errNotFound, resource: "order", id: "1"}, codes.NotFound, "order 1: not found"},
+		{"wrapped sentinel", fmt.Errorf("load: %w", errConflict), codes.AlreadyExists, "load: conflict"},
+		{"status is kept", status.Error(codes.PermissionDenied, "denied"), codes.PermissionDenied, "denied"},
+		{"cancelled", fmt.Errorf("find: %w", context.Canceled), codes.Canceled, "find: context canceled"},
+		{"deadline", context.DeadlineExceeded, codes.DeadlineExceeded, context.DeadlineExceeded.Error()},
+		{"no rule", errors.New("connection reset"), codes.Internal, "Failed to load: connection reset"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st, _ := status.FromError(translator.Error(tt.err, "Failed to load"))
+			if st.Code() != tt.code || st.Message() != tt.msg {
+				t.Errorf("Error() = %v %q, want %v %q", st.Code(), st.Message(), tt.code, tt.msg)
+			}
+		})
+	}
+
+	if err := translator.Error(nil, "Failed to load"); err != nil {
+		t.Errorf("Error(nil) = %v, want nil", err)
+	}
+}
+
+func TestTranslatorErrorDetails(t *testing.T) {
+	err := translator.Error(&testError{kind: errNotFound, resource: "order", id: "1", field: "id"}, "Failed to load")
+	details := detailsOf(t, err)
+
+	info, _ := details["*errdetails.ErrorInfo"].(*errdetails.ErrorInfo)
+	if info.GetReason() != "NOT_FOUND" || info.GetDomain() != "test-service" {
+		t.Errorf("ErrorInfo = %v, want reason NOT_FOUND in test-service", info)
+	}
+	resource, _ := details["*errdetails.ResourceInfo"].(*errdetails.ResourceInfo)
+	if resource.GetResourceType() != "order" || resource.GetResourceName() != "1" {
+		t.Errorf("ResourceInfo = %v, want order 1", resource)
+	}
+	want := []FieldViolation{{Field: "id", Description: "order 1: not found"}}
+	if got := FieldViolations(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldViolations() = %v, want %v", got, want)
+	}
+	if got := Reason(err); got != "NOT_FOUND" {
+		t.Errorf("Reason() = %q, want NOT_FOUND", got)
+	}
+}
+
+func TestTranslatorErrorWithoutDetails(t *testing.T) {
+	// Правило без reason и ошибка без ресурса и поля дают статус без деталей
+	err := translator.Error(errConflict, "Failed to create")
+	if details := detailsOf(t, err); len(details) != 0 {
+		t.Errorf("details = %v, want none", details)
+	}
+	if got := Reason(err); got != "" {
+		t.Errorf("Reason() = %q, want empty", got)
+	}
+}
+
+func TestInvalidArgumentf(t *testing.T) {
+	err := InvalidArgumentf("items[1].quantity", "Quantity must be positive, got %d", -2)
+
+	st, _ := status.FromError(err)
+	if st.Code() != codes.InvalidArgument || st.Message() != "Quantity must be positive, got -2" {
+		t.Errorf("InvalidArgumentf() = %v %q", st.Code(), st.Message())
+	}
+	want := []FieldViolation{{Field: "items[1].quantity", Description: "Quantity must be positive, got -2"}}
+	if got := FieldViolations(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("FieldViolations() = %v, want %v", got, want)
+	}
+}
+
+func TestDetailsOfPlainErrors(t *testing.T) {
+	err := errors.New("plain")
+	if got := FieldViolations(err); got != nil {
+		t.Errorf("FieldViolations() = %v, want nil", got)
+	}
+	if got := Reason(err); got != "" {
+		t.Errorf("Reason() = %q, want empty", got)
+	}
+}
@@ -0,0 +1,227 @@
+// Package identity carries the authenticated caller from the API gateway to
+// the backend services. The gateway verifies the user's token and forwards
+// the caller as a short-lived identity token signed with a secret shared by
+// the gateway and the services. The services verify that signature, so a
+// client that reaches them directly cannot claim another user or role.
+package identity
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// TokenMetadataKey is the gRPC metadata key with the signed identity token.
+const TokenMetadataKey = "x-identity-token"
+
+// tokenTTL limits how long a captured token can be replayed. Tokens are
+// signed for every call, so it only has to cover clock skew and the call
+// itself; streams are checked once when they start.
+const tokenTTL = time.Minute
+
+// minSecretLength is the shortest shared secret NewSigner accepts.
+const minSecretLength = 16
+
+// ErrInvalidToken is returned for tokens that are malformed, expired or not
+// signed with the shared secret.
+var ErrInvalidToken = errors.New("invalid identity token")
+
+// Identity is the authenticated caller of a request.
+type Identity struct {
+	UserID string
+	Roles  []string
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries id.
+func NewContext(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext returns the caller stored in ctx by NewContext or by the server
+// interceptors. On the services it is only set from a verified token.
+func FromContext(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(contextKey{}).(Identity)
+	if !ok || id.UserID == "" {
+		return Identity{}, false
+	}
+	return id, true
+}
+
+// claims is the payload of an identity token.
+type claims struct {
+	Subject   string   `json:"sub"`
+	Roles     []string `json:"roles,omitempty"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// Signer signs and verifies identity tokens with the shared secret. A token
+// is the base64url JSON payload and its base64url HMAC-SHA256, joined by a
+// dot.
+type Signer struct {
+	key []byte
+	now func() time.Time
+}
+
+// NewSigner returns a Signer for the shared secret.
+func NewSigner(secret string) (*Signer, error) {
+	if len(secret) < minSecretLength {
+		return nil, fmt.Errorf("identity secret must have at least %d characters", minSecretLength)
+	}
+	return &Signer{key: []byte(secret), now: time.Now}, nil
+}
+
+// Sign returns a token for id that expires after tokenTTL.
+func (s *Signer) Sign(id Identity) (string, error) {
+	payload, err := json.Marshal(claims{Subject: id.UserID, Roles: id.Roles, ExpiresAt: s.now().Add(tokenTTL).Unix()})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode identity token: %w", err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(s.mac(encoded)), nil
+}
+
+// Verify checks the signature and expiry of token and returns the caller.
+func (s *Signer) Verify(token string) (Identity, error) {
+	encoded, signature, found := strings.Cut(token, ".")
+	if !found {
+		return Identity{}, ErrInvalidToken
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.mac(encoded)) {
+		return Identity{}, ErrInvalidToken
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	var c claims
+	if err := json.Unmarshal(payload, &c); err != nil || c.Subject == "" {
+		return Identity{}, ErrInvalidToken
+	}
+	if !s.now().Before(time.Unix(c.ExpiresAt, 0)) {
+		return Identity{}, fmt.Errorf("%w: expired", ErrInvalidToken)
+	}
+	return Identity{UserID: c.Subject, Roles: c.Roles}, nil
+}
+
+func (s *Signer) mac(encoded string) []byte {
+	h := hmac.New(sha256.New, s.key)
+	h.Write([]byte(encoded))
+	return h.Sum(nil)
+}
+
+// appendToOutgoingContext adds a token for id to the outgoing gRPC metadata
+// of ctx.
+func (s *Signer) appendToOutgoingContext(ctx context.Context, id Identity) (context.Context, error) {
+	token, err := s.Sign(id)
+	if err != nil {
+		return nil, err
+	}
+	return metadata.AppendToOutgoingContext(ctx, TokenMetadataKey, token), nil
+}
+
+// fromIncomingContext reads and verifies the caller of the incoming gRPC
+// metadata. Calls without a token have no caller; calls with an invalid one
+// are rejected.
+func (s *Signer) fromIncomingContext(ctx context.Context) (Identity, bool, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(TokenMetadataKey)
+	if len(values) == 0 {
+		return Identity{}, false, nil
+	}
+	id, err := s.Verify(values[0])
+	if err != nil {
+		return Identity{}, false, status.Errorf(codes.Unauthenticated, "Invalid %s metadata: %v", TokenMetadataKey, err)
+	}
+	return id, true, nil
+}
+
+// UnaryClientInterceptor forwards the caller stored in the call context.
+func (s *Signer) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if id, ok := FromContext(ctx); ok {
+			var err error
+			if ctx, err = s.appendToOutgoingContext(ctx, id); err != nil {
+				return err
+			}
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StaticUnaryClientInterceptor sends id with every call. Services use it for
+// calls they make on their own behalf rather than for a user.
+func (s *Signer) StaticUnaryClientInterceptor(id Identity) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		ctx, err := s.appendToOutgoingContext(ctx, id)
+		if err != nil {
+			return err
+		}
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor forwards the caller stored in the stream context.
+func (s *Signer) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if id, ok := FromContext(ctx); ok {
+			var err error
+			if ctx, err = s.appendToOutgoingContext(ctx, id); err != nil {
+				return nil, err
+			}
+		}
+		return streamer(ctx, desc, cc, method, opts...)
+	}
+}
+
+// UnaryServerInterceptor makes the caller of a verified token available
+// through FromContext and rejects calls with an invalid token.
+func (s *Signer) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		id, ok, err := s.fromIncomingContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			ctx = NewContext(ctx, id)
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is the streaming counterpart of
+// UnaryServerInterceptor.
+func (s *Signer) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		id, ok, err := s.fromIncomingContext(ss.Context())
+		if err != nil {
+			return err
+		}
+		if ok {
+			ss = &serverStream{ServerStream: ss, ctx: NewContext(ss.Context(), id)}
+		}
+		return handler(srv, ss)
+	}
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,182 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const testSecret = "0123456789abcdef"
+
+func newTestSigner(t *testing.T, secret string, now time.Time) *Signer {
+	t.Helper()
+	s, err := NewSigner(secret)
+	if err != nil {
+		t.Fatalf("NewSigner() error = %v", err)
+	}
+	s.now = func() time.Time { return now }
+	return s
+}
+
+func TestNewSigner(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  string
+		wantErr bool
+	}{
+		{"long enough", testSecret, false},
+		{"too short", testSecret[:minSecretLength-1], true},
+		{"empty", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewSigner(tt.secret); (err != nil) != tt.wantErr {
+				t.Errorf("NewSigner() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignerVerify(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	signer := newTestSigner(t, testSecret, now)
+	id := Identity{UserID: "user-1", Roles: []string{"customer", "support"}}
+	token, err := signer.Sign(id)
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+	payload, signature, _ := strings.Cut(token, ".")
+
+	tests := []struct {
+		name     string
+		verifier *Signer
+		token    string
+		wantErr  bool
+	}{
+		{"valid", signer, token, false},
+		{"just before expiry", newTestSigner(t, testSecret, now.Add(tokenTTL-time.Second)), token, false},
+		{"expired", newTestSigner(t, testSecret, now.Add(tokenTTL)), token, true},
+		{"other secret", newTestSigner(t, testSecret+"x", now), token, true},
+		{"tampered payload", signer, payload + "x." + signature, true},
+		{"tampered signature", signer, payload + "." + signature[:len(signature)-2], true},
+		{"no signature", signer, payload, true},
+		{"empty", signer, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidToken) {
+					t.Errorf("Verify() error = %v, want %v", err, ErrInvalidToken)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, id) {
+				t.Errorf("Verify() = %+v, want %+v", got, id)
+			}
+		})
+	}
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	signer := newTestSigner(t, testSecret, time.Now())
+	token, err := signer.Sign(Identity{UserID: "user-1", Roles: []string{"customer"}})
+	if err != nil {
+		t.Fatalf("Sign() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantUser string
+		wantCode codes.Code
+	}{
+		{"signed token", metadata.Pairs(TokenMetadataKey, token), "user-1", codes.OK},
+		{"no token", metadata.MD{}, "", codes.OK},
+		// Метаданные без подписи больше не дают личность
+		{"plain user id", metadata.Pairs("x-user-id", "admin", "x-user-roles", "order_admin"), "", codes.OK},
+		{"forged token", metadata.Pairs(TokenMetadataKey, "eyJzdWIiOiJhZG1pbiJ9.c2ln"), "", codes.Unauthenticated},
+	}
+
+	interceptor := signer.UnaryServerInterceptor()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+			var gotUser string
+			handler := func(ctx context.Context, req any) (any, error) {
+				if id, ok := FromContext(ctx); ok {
+					gotUser = id.UserID
+				}
+				return nil, nil
+			}
+
+			_, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/test/Method"}, handler)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Fatalf("interceptor code = %v, want %v", code, tt.wantCode)
+			}
+			if gotUser != tt.wantUser {
+				t.Errorf("caller = %q, want %q", gotUser, tt.wantUser)
+			}
+		})
+	}
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	signer := newTestSigner(t, testSecret, time.Now())
+	id := Identity{UserID: "user-1", Roles: []string{"customer"}}
+
+	tests := []struct {
+		name        string
+		interceptor grpc.UnaryClientInterceptor
+		ctx         context.Context
+		want        *Identity
+	}{
+		{"caller from context", signer.UnaryClientInterceptor(), NewContext(context.Background(), id), &id},
+		{"no caller", signer.UnaryClientInterceptor(), context.Background(), nil},
+		{"static caller", signer.StaticUnaryClientInterceptor(id), context.Background(), &id},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var tokens []string
+			invoker := func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+				md, _ := metadata.FromOutgoingContext(ctx)
+				tokens = md.Get(TokenMetadataKey)
+				return nil
+			}
+			if err := tt.interceptor(tt.ctx, "/test/Method", nil, nil, nil, invoker); err != nil {
+				t.Fatalf("interceptor error = %v", err)
+			}
+
+			if tt.want == nil {
+				if len(tokens) != 0 {
+					t.Errorf("sent tokens %v, want none", tokens)
+				}
+				return
+			}
+			if len(tokens) != 1 {
+				t.Fatalf("sent %d tokens, want 1", len(tokens))
+			}
+			got, err := signer.Verify(tokens[0])
+			if err != nil {
+				t.Fatalf("Verify() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, *tt.want) {
+				t.Errorf("sent caller %+v, want %+v", got, *tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,26 @@
+// Package metrics exposes Prometheus metrics of the services: gRPC calls,
+// Mongo store calls and the HTTP endpoint Prometheus scrapes.
+package metrics
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler serves all registered metrics.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// NewServer returns an HTTP server for the metrics port of a gRPC service.
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", Handler())
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+}
@@ -0,0 +1,41 @@
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	storeDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "mongo_store_operation_duration_seconds",
+		Help:    "Time spent in a Mongo store method.",
+		Buckets: []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"store", "operation"})
+
+	storeErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "mongo_store_operation_errors_total",
+		Help: "Store method calls that returned an error, including not found.",
+	}, []string{"store", "operation"})
+)
+
+// StoreMetrics times the methods of one store.
+type StoreMetrics struct {
+	store string
+}
+
+func NewStoreMetrics(store string) *StoreMetrics {
+	return &StoreMetrics{store: store}
+}
+
+// Observe records a finished store call. It is meant to be deferred at the
+// top of a method with a named error result:
+//
+//	defer storeMetrics.Observe("GetByID", time.Now(), &err)
+func (m *StoreMetrics) Observe(operation string, start time.Time, err *error) {
+	storeDuration.WithLabelValues(m.store, operation).Observe(time.Since(start).Seconds())
+	if err != nil && *err != nil {
+		storeErrors.WithLabelValues(m.store, operation).Inc()
+	}
+}
@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"ecommerce-microservices/pkg/events"
+	"log/slog"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	relayBatchSize      = 100
+	relayPublishTimeout = 5 * time.Second
+)
+
+// relayStore is the part of Store the relay uses.
+type relayStore interface {
+	Unpublished(ctx context.Context, limit int64) ([]Record, error)
+	MarkPublished(ctx context.Context, id primitive.ObjectID) error
+	MarkFailed(ctx context.Context, record Record, publishErr error) (bool, error)
+}
+
+// Relay polls the outbox and publishes pending events. Events of one
+// aggregate are published in insertion order: while an event waits for a
+// retry, later events of its aggregate wait too. Other aggregates are not
+// held up, and an event that keeps failing is dead-lettered by the store.
+// Delivery is at least once: an event is marked published only after the
+// broker accepted it, so consumers must de-duplicate by event ID.
+type Relay struct {
+	store    relayStore
+	broker   events.Broker
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewRelay(store *Store, broker events.Broker, interval time.Duration) *Relay {
+	return &Relay{store: store, broker: broker, interval: interval, now: time.Now}
+}
+
+// Run publishes pending events every interval until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			slog.Info("Outbox relay stopped")
+			return
+		case <-ticker.C:
+			r.publishPending(ctx)
+		}
+	}
+}
+
+func (r *Relay) publishPending(ctx context.Context) {
+	records, err := r.store.Unpublished(ctx, relayBatchSize)
+	if err != nil {
+		slog.ErrorContext(ctx, "Outbox relay failed to load events", "error", err)
+		return
+	}
+
+	now := r.now()
+	blocked := make(map[string]bool)
+	for _, record := range records {
+		aggregateID := record.Event.AggregateID
+		if blocked[aggregateID] {
+			continue
+		}
+		if record.NextAttemptAt != nil && record.NextAttemptAt.After(now) {
+			// Следующие события агрегата ждут этот повтор, чтобы сохранить порядок
+			blocked[aggregateID] = true
+			continue
+		}
+
+		publishCtx, cancel := context.WithTimeout(ctx, relayPublishTimeout)
+		err := r.broker.Publish(publishCtx, record.Event)
+		cancel()
+		if err != nil {
+			blocked[aggregateID] = true
+			r.markFailed(ctx, record, err)
+			continue
+		}
+		if err := r.store.MarkPublished(ctx, record.ID); err != nil {
+			slog.ErrorContext(ctx, "Outbox relay failed to mark event published", "event_id", record.Event.ID, "error", err)
+			blocked[aggregateID] = true
+		}
+	}
+}
+
+func (r *Relay) markFailed(ctx context.Context, record Record, publishErr error) {
+	dead, err := r.store.MarkFailed(ctx, record, publishErr)
+	switch {
+	case err != nil:
+		slog.ErrorContext(ctx, "Outbox relay failed to record publish error", "event_id", record.Event.ID, "error", err)
+	case dead:
+		slog.ErrorContext(ctx, "Outbox relay gave up on event", "event_type", record.Event.Type, "event_id", record.Event.ID, "attempts", record.Attempts+1, "error", publishErr)
+	default:
+		slog.WarnContext(ctx, "Outbox relay failed to publish event", "event_type", record.Event.Type, "event_id", record.Event.ID, "error", publishErr)
+	}
+}
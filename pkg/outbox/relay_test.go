@@ -0,0 +1,139 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"ecommerce-microservices/pkg/events"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type fakeRelayStore struct {
+	records   []Record
+	published []string
+	failed    []string
+	dead      []string
+}
+
+func (s *fakeRelayStore) Unpublished(ctx context.Context, limit int64) ([]Record, error) {
+	return s.records, nil
+}
+
+func (s *fakeRelayStore) MarkPublished(ctx context.Context, id primitive.ObjectID) error {
+	for _, record := range s.records {
+		if record.ID == id {
+			s.published = append(s.published, record.Event.ID)
+		}
+	}
+	return nil
+}
+
+func (s *fakeRelayStore) MarkFailed(ctx context.Context, record Record, publishErr error) (bool, error) {
+	s.failed = append(s.failed, record.Event.ID)
+	dead := record.Attempts+1 >= maxPublishAttempts
+	if dead {
+		s.dead = append(s.dead, record.Event.ID)
+	}
+	return dead, nil
+}
+
+// fakeBroker fails to publish the events in reject.
+type fakeBroker struct {
+	reject map[string]bool
+}
+
+func (b *fakeBroker) Publish(ctx context.Context, event events.Event) error {
+	if b.reject[event.ID] {
+		return errors.New("rejected")
+	}
+	return nil
+}
+
+func (b *fakeBroker) Close() error { return nil }
+
+func TestRelayPublishPending(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	later := now.Add(time.Minute)
+	earlier := now.Add(-time.Minute)
+
+	record := func(id, aggregateID string) Record {
+		return Record{ID: primitive.NewObjectID(), Event: events.Event{ID: id, AggregateID: aggregateID}}
+	}
+	retrying := func(r Record, at time.Time) Record {
+		r.Attempts = 1
+		r.NextAttemptAt = &at
+		return r
+	}
+	exhausted := func(r Record) Record {
+		r.Attempts = maxPublishAttempts - 1
+		return r
+	}
+
+	tests := []struct {
+		name          string
+		records       []Record
+		reject        []string
+		wantPublished []string
+		wantFailed    []string
+		wantDead      []string
+	}{
+		{
+			name:          "all published in order",
+			records:       []Record{record("e1", "a"), record("e2", "b"), record("e3", "a")},
+			wantPublished: []string{"e1", "e2", "e3"},
+		},
+		{
+			// Ошибка одного события не останавливает другие агрегаты
+			name:          "failure holds back only its aggregate",
+			records:       []Record{record("e1", "a"), record("e2", "b"), record("e3", "a"), record("e4", "c")},
+			reject:        []string{"e1"},
+			wantPublished: []string{"e2", "e4"},
+			wantFailed:    []string{"e1"},
+		},
+		{
+			name:          "waiting for retry",
+			records:       []Record{retrying(record("e1", "a"), later), record("e2", "a"), record("e3", "b")},
+			wantPublished: []string{"e3"},
+		},
+		{
+			name:          "retry is due",
+			records:       []Record{retrying(record("e1", "a"), earlier), record("e2", "a")},
+			wantPublished: []string{"e1", "e2"},
+		},
+		{
+			name:          "poison event is dead-lettered",
+			records:       []Record{exhausted(record("e1", "a")), record("e2", "b")},
+			reject:        []string{"e1"},
+			wantPublished: []string{"e2"},
+			wantFailed:    []string{"e1"},
+			wantDead:      []string{"e1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeRelayStore{records: tt.records}
+			broker := &fakeBroker{reject: make(map[string]bool)}
+			for _, id := range tt.reject {
+				broker.reject[id] = true
+			}
+			relay := &Relay{store: store, broker: broker, now: func() time.Time { return now }}
+
+			relay.publishPending(context.Background())
+
+			if !slices.Equal(store.published, tt.wantPublished) {
+				t.Errorf("published = %v, want %v", store.published, tt.wantPublished)
+			}
+			if !slices.Equal(store.failed, tt.wantFailed) {
+				t.Errorf("failed = %v, want %v", store.failed, tt.wantFailed)
+			}
+			if !slices.Equal(store.dead, tt.wantDead) {
+				t.Errorf("dead = %v, want %v", store.dead, tt.wantDead)
+			}
+		})
+	}
+}
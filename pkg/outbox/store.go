@@ -0,0 +1,168 @@
+// Package outbox implements the transactional outbox: events are written to
+// an outbox collection in the same MongoDB transaction as the change they
+// describe, and a Relay publishes them to a broker afterwards.
+package outbox
+
+import (
+	"context"
+	"ecommerce-microservices/pkg/events"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	collectionName = "outbox"
+
+	// Опубликованные события удаляются TTL-индексом
+	publishedRetention = 7 * 24 * time.Hour
+
+	maxPublishAttempts = 10
+	minRetryDelay      = time.Second
+	maxRetryDelay      = 5 * time.Minute
+)
+
+// Record is an event waiting in the outbox. PublishedAt is set once the
+// relay handed the event to the broker. A failed event is retried after
+// NextAttemptAt; after maxPublishAttempts failures it is dead-lettered by
+// setting DeadAt and is no longer returned by Unpublished.
+type Record struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty"`
+	Event         events.Event       `bson:"event"`
+	CreatedAt     time.Time          `bson:"created_at"`
+	PublishedAt   *time.Time         `bson:"published_at,omitempty"`
+	Attempts      int                `bson:"attempts"`
+	LastError     string             `bson:"last_error,omitempty"`
+	NextAttemptAt *time.Time         `bson:"next_attempt_at,omitempty"`
+	DeadAt        *time.Time         `bson:"dead_at,omitempty"`
+}
+
+type Store struct {
+	collection *mongo.Collection
+}
+
+func NewStore(db *mongo.Database) *Store {
+	return &Store{collection: db.Collection(collectionName)}
+}
+
+// EnsureIndexes creates the index the relay polls by and a TTL index that
+// removes published events after publishedRetention. Pending and
+// dead-lettered events have no published_at and are never expired.
+func (s *Store) EnsureIndexes(ctx context.Context) error {
+	_, err := s.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "published_at", Value: 1}, {Key: "dead_at", Value: 1}, {Key: "_id", Value: 1}}},
+		{
+			Keys:    bson.D{{Key: "published_at", Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(int32(publishedRetention.Seconds())),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create outbox indexes: %w", err)
+	}
+	return nil
+}
+
+// WithTransaction runs fn inside a MongoDB transaction. Writes made by fn
+// with the context it receives, including Add, commit or abort together.
+// If ctx already belongs to a transaction, fn joins it instead, so that
+// transactional store methods can be combined into one transaction.
+func (s *Store) WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if mongo.SessionFromContext(ctx) != nil {
+		return fn(ctx)
+	}
+	session, err := s.collection.Database().Client().StartSession()
+	if err != nil {
+		return fmt.Errorf("failed to start mongo session: %w", err)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (any, error) {
+		return nil, fn(sc)
+	})
+	return err
+}
+
+// Add stores events in the outbox. Call it with the context passed to a
+// WithTransaction callback so the events are committed with the change.
+func (s *Store) Add(ctx context.Context, evts ...events.Event) error {
+	if len(evts) == 0 {
+		return nil
+	}
+	now := time.Now()
+	docs := make([]any, len(evts))
+	for i, event := range evts {
+		docs[i] = Record{Event: event, CreatedAt: now}
+	}
+	if _, err := s.collection.InsertMany(ctx, docs); err != nil {
+		return fmt.Errorf("failed to insert outbox events: %w", err)
+	}
+	return nil
+}
+
+// Unpublished returns up to limit events that were neither published nor
+// dead-lettered yet, oldest first. Events waiting for a retry are included;
+// the relay skips them until their NextAttemptAt.
+func (s *Store) Unpublished(ctx context.Context, limit int64) ([]Record, error) {
+	findOptions := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(limit)
+	cursor, err := s.collection.Find(ctx, bson.M{"published_at": nil, "dead_at": nil}, findOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list outbox events: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox events: %w", err)
+	}
+	return records, nil
+}
+
+func (s *Store) MarkPublished(ctx context.Context, id primitive.ObjectID) error {
+	update := bson.M{
+		"$set": bson.M{"published_at": time.Now()},
+		"$inc": bson.M{"attempts": 1},
+	}
+	if _, err := s.collection.UpdateByID(ctx, id, update); err != nil {
+		return fmt.Errorf("failed to mark outbox event published: %w", err)
+	}
+	return nil
+}
+
+// MarkFailed records a failed publish of record and schedules the next
+// attempt with exponential backoff. Once the event failed maxPublishAttempts
+// times it is dead-lettered instead, and dead reports that.
+func (s *Store) MarkFailed(ctx context.Context, record Record, publishErr error) (dead bool, err error) {
+	now := time.Now()
+	attempts := record.Attempts + 1
+	set := bson.M{"last_error": publishErr.Error()}
+	if attempts >= maxPublishAttempts {
+		dead = true
+		set["dead_at"] = now
+	} else {
+		set["next_attempt_at"] = now.Add(retryDelay(attempts))
+	}
+
+	update := bson.M{
+		"$set": set,
+		"$inc": bson.M{"attempts": 1},
+	}
+	if _, err := s.collection.UpdateByID(ctx, record.ID, update); err != nil {
+		return false, fmt.Errorf("failed to record outbox publish failure: %w", err)
+	}
+	return dead, nil
+}
+
+// retryDelay returns how long to wait before the next publish of an event
+// that failed attempts times: minRetryDelay doubled per failure, up to
+// maxRetryDelay.
+func retryDelay(attempts int) time.Duration {
+	delay := minRetryDelay
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
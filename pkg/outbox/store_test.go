@@ -0,0 +1,174 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"ecommerce-microservices/pkg/events"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newMockStore(mt *mtest.T) *Store {
+	return &Store{collection: mt.Coll}
+}
+
+func TestStoreAdd(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("inserts records", func(mt *mtest.T) {
+		store := newMockStore(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		evts := []events.Event{
+			{ID: "e1", Type: "order.created", AggregateID: "o1"},
+			{ID: "e2", Type: "order.paid", AggregateID: "o1"},
+		}
+		if err := store.Add(context.Background(), evts...); err != nil {
+			mt.Fatalf("Add() error = %v", err)
+		}
+
+		started := mt.GetStartedEvent()
+		if started.CommandName != "insert" {
+			mt.Fatalf("command = %s, want insert", started.CommandName)
+		}
+		docs, err := started.Command.Lookup("documents").Array().Values()
+		if err != nil {
+			mt.Fatalf("documents: %v", err)
+		}
+		if len(docs) != len(evts) {
+			mt.Fatalf("inserted %d documents, want %d", len(docs), len(evts))
+		}
+		for i, doc := range docs {
+			var record Record
+			if err := bson.Unmarshal(doc.Document(), &record); err != nil {
+				mt.Fatalf("decode record: %v", err)
+			}
+			if record.Event.ID != evts[i].ID || record.PublishedAt != nil || record.Attempts != 0 {
+				mt.Errorf("record %d = %+v", i, record)
+			}
+		}
+	})
+
+	mt.Run("no events", func(mt *mtest.T) {
+		store := newMockStore(mt)
+		if err := store.Add(context.Background()); err != nil {
+			mt.Fatalf("Add() error = %v", err)
+		}
+		if started := mt.GetStartedEvent(); started != nil {
+			mt.Errorf("sent %s, want no command", started.CommandName)
+		}
+	})
+
+	mt.Run("insert error", func(mt *mtest.T) {
+		store := newMockStore(mt)
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 1, Message: "boom"}))
+		if err := store.Add(context.Background(), events.Event{ID: "e1"}); err == nil {
+			mt.Error("Add() error = nil, want error")
+		}
+	})
+}
+
+// updateOf returns the update document of the single update command sent.
+func updateOf(mt *mtest.T) bson.Raw {
+	mt.Helper()
+	started := mt.GetStartedEvent()
+	if started == nil || started.CommandName != "update" {
+		mt.Fatalf("sent %v, want update", started)
+	}
+	updates, err := started.Command.Lookup("updates").Array().Values()
+	if err != nil || len(updates) != 1 {
+		mt.Fatalf("updates = %v, %v", updates, err)
+	}
+	return updates[0].Document().Lookup("u").Document()
+}
+
+func TestStoreMarkPublished(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("sets published_at", func(mt *mtest.T) {
+		store := newMockStore(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		before := time.Now().Truncate(time.Millisecond)
+		if err := store.MarkPublished(context.Background(), primitive.NewObjectID()); err != nil {
+			mt.Fatalf("MarkPublished() error = %v", err)
+		}
+
+		u := updateOf(mt)
+		publishedAt, ok := u.Lookup("$set", "published_at").TimeOK()
+		if !ok || publishedAt.Before(before) {
+			mt.Errorf("published_at = %v, want a time after %v", publishedAt, before)
+		}
+		if attempts := u.Lookup("$inc", "attempts").Int32(); attempts != 1 {
+			mt.Errorf("attempts increment = %d, want 1", attempts)
+		}
+	})
+}
+
+func TestStoreMarkFailed(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	publishErr := errors.New("broker unavailable")
+
+	mt.Run("schedules a retry", func(mt *mtest.T) {
+		store := newMockStore(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		before := time.Now().Truncate(time.Millisecond)
+		dead, err := store.MarkFailed(context.Background(), Record{ID: primitive.NewObjectID(), Attempts: 2}, publishErr)
+		if err != nil || dead {
+			mt.Fatalf("MarkFailed() = %v, %v, want false, nil", dead, err)
+		}
+
+		set := updateOf(mt).Lookup("$set").Document()
+		if got := set.Lookup("last_error").StringValue(); got != publishErr.Error() {
+			mt.Errorf("last_error = %q, want %q", got, publishErr.Error())
+		}
+		next, ok := set.Lookup("next_attempt_at").TimeOK()
+		if !ok || next.Before(before.Add(retryDelay(3))) {
+			mt.Errorf("next_attempt_at = %v, want at least %v", next, before.Add(retryDelay(3)))
+		}
+		if _, ok := set.Lookup("dead_at").TimeOK(); ok {
+			mt.Error("dead_at is set for a retried event")
+		}
+	})
+
+	mt.Run("dead-letters the last attempt", func(mt *mtest.T) {
+		store := newMockStore(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		dead, err := store.MarkFailed(context.Background(), Record{ID: primitive.NewObjectID(), Attempts: maxPublishAttempts - 1}, publishErr)
+		if err != nil || !dead {
+			mt.Fatalf("MarkFailed() = %v, %v, want true, nil", dead, err)
+		}
+
+		set := updateOf(mt).Lookup("$set").Document()
+		if _, ok := set.Lookup("dead_at").TimeOK(); !ok {
+			mt.Error("dead_at is not set")
+		}
+	})
+}
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{4, 8 * time.Second},
+		{9, 256 * time.Second},
+		{10, maxRetryDelay},
+		{100, maxRetryDelay},
+	}
+
+	for _, tt := range tests {
+		if got := retryDelay(tt.attempts); got != tt.want {
+			t.Errorf("retryDelay(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
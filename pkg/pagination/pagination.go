@@ -0,0 +1,110 @@
+// Package pagination implements keyset pagination over MongoDB collections
+// with opaque page tokens.
+//
+// A list sorted by (field, _id) is continued after the last item of the
+// previous page instead of skipping a number of documents, so pages stay
+// fast deep into a list and do not repeat or miss items when documents are
+// added or removed in between.
+package pagination
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidToken is returned for page tokens that are malformed or were
+// issued for a different filter or sort order.
+var ErrInvalidToken = errors.New("invalid page token")
+
+// Cursor is the sort key of the last item of a page.
+type Cursor struct {
+	Value any                `bson:"v"`
+	ID    primitive.ObjectID `bson:"id"`
+}
+
+// Page selects one page of a list. If After is set the page starts after
+// that cursor and Offset is ignored. The total number of items is only
+// counted if CountTotal is set, as it costs a full scan of the matches.
+type Page struct {
+	Limit      int64
+	Offset     int64
+	After      *Cursor
+	CountTotal bool
+}
+
+type token struct {
+	Query  string `bson:"q"`
+	Cursor Cursor `bson:"c"`
+}
+
+// Fingerprint identifies a filter and sort order. Tokens carry it so that
+// they are not used to continue a different list. The parts must be
+// encodable as JSON.
+func Fingerprint(parts ...any) string {
+	data, err := json.Marshal(parts)
+	if err != nil {
+		panic(fmt.Sprintf("pagination: cannot fingerprint %v: %v", parts, err))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:8])
+}
+
+// EncodeToken returns an opaque token for the page after c.
+func EncodeToken(c Cursor, fingerprint string) (string, error) {
+	data, err := bson.Marshal(token{Query: fingerprint, Cursor: c})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode page token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeToken returns the cursor of a token made by EncodeToken with the
+// same fingerprint.
+func DecodeToken(s, fingerprint string) (Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidToken
+	}
+	var t token
+	if err := bson.Unmarshal(data, &t); err != nil || t.Query != fingerprint {
+		return Cursor{}, ErrInvalidToken
+	}
+	return t.Cursor, nil
+}
+
+// After restricts filter to the items that come after c when sorted by
+// field and then _id, both in the same direction. It returns filter
+// unchanged if c is nil.
+func After(filter bson.M, field string, descending bool, c *Cursor) bson.M {
+	if c == nil {
+		return filter
+	}
+	op := "$gt"
+	if descending {
+		op = "$lt"
+	}
+	after := bson.M{"$or": bson.A{
+		bson.M{field: bson.M{op: c.Value}},
+		bson.M{field: c.Value, "_id": bson.M{op: c.ID}},
+	}}
+	if len(filter) == 0 {
+		return after
+	}
+	return bson.M{"$and": bson.A{filter, after}}
+}
+
+// Sort returns the sort document that After relies on.
+func Sort(field string, descending bool) bson.D {
+	direction := 1
+	if descending {
+		direction = -1
+	}
+	return bson.D{{Key: field, Value: direction}, {Key: "_id", Value: direction}}
+}
@@ -0,0 +1,143 @@
+package pagination
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestTokenRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+	createdAt := primitive.NewDateTimeFromTime(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC))
+
+	tests := []struct {
+		name   string
+		cursor Cursor
+	}{
+		{"string value", Cursor{Value: "Keyboard", ID: id}},
+		{"number value", Cursor{Value: 19.99, ID: id}},
+		{"integer value", Cursor{Value: int64(42), ID: id}},
+		{"date value", Cursor{Value: createdAt, ID: id}},
+		{"null value", Cursor{Value: nil, ID: id}},
+	}
+
+	fingerprint := Fingerprint("products", "price", true)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := EncodeToken(tt.cursor, fingerprint)
+			if err != nil {
+				t.Fatalf("EncodeToken() error = %v", err)
+			}
+			got, err := DecodeToken(token, fingerprint)
+			if err != nil {
+				t.Fatalf("DecodeToken() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.cursor) {
+				t.Errorf("DecodeToken() = %#v, want %#v", got, tt.cursor)
+			}
+		})
+	}
+}
+
+func TestDecodeTokenInvalid(t *testing.T) {
+	fingerprint := Fingerprint("user-1")
+	valid, err := EncodeToken(Cursor{Value: "a", ID: primitive.NewObjectID()}, fingerprint)
+	if err != nil {
+		t.Fatalf("EncodeToken() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		token       string
+		fingerprint string
+	}{
+		{"other fingerprint", valid, Fingerprint("user-2")},
+		{"empty fingerprint", valid, ""},
+		{"not base64", "not a token!", fingerprint},
+		{"not bson", "aGVsbG8", fingerprint},
+		{"truncated", valid[:len(valid)/2], fingerprint},
+		{"empty", "", fingerprint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := DecodeToken(tt.token, tt.fingerprint); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("DecodeToken() error = %v, want %v", err, ErrInvalidToken)
+			}
+		})
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []any
+		same bool
+	}{
+		{"same parts", []any{"user-1", 10}, []any{"user-1", 10}, true},
+		{"other value", []any{"user-1"}, []any{"user-2"}, false},
+		{"other order", []any{"a", "b"}, []any{"b", "a"}, false},
+		{"parts are not concatenated", []any{"ab", "c"}, []any{"a", "bc"}, false},
+		{"other type", []any{"1"}, []any{1}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Fingerprint(tt.a...) == Fingerprint(tt.b...); got != tt.same {
+				t.Errorf("Fingerprint(%v) == Fingerprint(%v) is %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestAfter(t *testing.T) {
+	id := primitive.NewObjectID()
+	cursor := &Cursor{Value: 10, ID: id}
+
+	tests := []struct {
+		name       string
+		filter     bson.M
+		descending bool
+		cursor     *Cursor
+		want       bson.M
+	}{
+		{
+			name:   "no cursor",
+			filter: bson.M{"stock": 1},
+			want:   bson.M{"stock": 1},
+		},
+		{
+			name:   "ascending without filter",
+			cursor: cursor,
+			want: bson.M{"$or": bson.A{
+				bson.M{"price": bson.M{"$gt": 10}},
+				bson.M{"price": 10, "_id": bson.M{"$gt": id}},
+			}},
+		},
+		{
+			name:       "descending with filter",
+			filter:     bson.M{"stock": 1},
+			descending: true,
+			cursor:     cursor,
+			want: bson.M{"$and": bson.A{
+				bson.M{"stock": 1},
+				bson.M{"$or": bson.A{
+					bson.M{"price": bson.M{"$lt": 10}},
+					bson.M{"price": 10, "_id": bson.M{"$lt": id}},
+				}},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := After(tt.filter, "price", tt.descending, tt.cursor); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("After() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}